@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// validateAllowlistedInterface checks an interface allowlist entry of the form pkg.Interface
+func validateAllowlistedInterface(name string) error {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.LastIndex(name, ".")
+	if dot <= slash+1 || dot == len(name)-1 {
+		return fmt.Errorf("invalid interface %q (expected pkg.Interface, e.g. database/sql/driver.Valuer)", name)
+	}
+	return nil
+}
+
+// findAllowlistedMethods keeps alive the methods through which project types implement an
+// allowlisted interface. Such interfaces are contracts consumed outside the module (by
+// codecs, drivers or generated code), so their methods count as used even when nothing in
+// the project references the interface.
+func (a *Analyzer) findAllowlistedMethods() {
+	if len(a.config.InterfaceAllowlist) == 0 {
+		return
+	}
+
+	var interfaces []*types.Interface
+	for _, name := range a.config.InterfaceAllowlist {
+		iface := a.lookupInterface(name)
+		if iface == nil {
+			if a.config.Verbose && !a.config.OutputJSON {
+				fmt.Printf("⚠️  Allowlisted interface %s is not imported by the project\n", name)
+			}
+			continue
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	for _, pkg := range a.packages {
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			named, ok := typeName.Type().(*types.Named)
+			// Generic types only implement interfaces once instantiated
+			if !ok || types.IsInterface(named) || named.TypeParams().Len() > 0 {
+				continue
+			}
+
+			ptr := types.NewPointer(named)
+			for _, iface := range interfaces {
+				if !types.Implements(ptr, iface) {
+					continue
+				}
+				for i := 0; i < iface.NumMethods(); i++ {
+					obj, _, _ := types.LookupFieldOrMethod(ptr, false, pkg.Types, iface.Method(i).Name())
+					fn, ok := obj.(*types.Func)
+					if !ok || fn.Pkg() == nil {
+						continue
+					}
+					if id, ok := a.objectID(fn); ok {
+						if _, exists := a.symbols[a.graph.keys[id]]; exists {
+							a.allowlisted[id] = true
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// lookupInterface resolves a pkg.Interface name among the loaded packages and everything
+// they import, or returns nil
+func (a *Analyzer) lookupInterface(name string) *types.Interface {
+	dot := strings.LastIndex(name, ".")
+	pkgPath, typeName := name[:dot], name[dot+1:]
+
+	visited := make(map[*types.Package]bool)
+	var find func(pkg *types.Package) *types.Package
+	find = func(pkg *types.Package) *types.Package {
+		if pkg == nil || visited[pkg] {
+			return nil
+		}
+		visited[pkg] = true
+		if pkg.Path() == pkgPath {
+			return pkg
+		}
+		for _, imported := range pkg.Imports() {
+			if found := find(imported); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	for _, pkg := range a.packages {
+		if found := find(pkg.Types); found != nil {
+			obj, ok := found.Scope().Lookup(typeName).(*types.TypeName)
+			if !ok {
+				return nil
+			}
+			iface, _ := obj.Type().Underlying().(*types.Interface)
+			return iface
+		}
+	}
+	return nil
+}
+
+// allowlistRoots returns the keys of methods implementing an allowlisted interface
+func (a *Analyzer) allowlistRoots() []string {
+	var roots []string
+	for id := range a.allowlisted {
+		roots = append(roots, a.graph.keys[id])
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(roots) > 0 {
+		fmt.Printf("📜 %d method(s) implementing allowlisted interfaces kept alive\n", len(roots))
+	}
+
+	return roots
+}
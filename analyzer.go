@@ -3,77 +3,283 @@ package main
 import (
 	"fmt"
 	"go/token"
+	"go/types"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
-// NewAnalyzer creates a new analyzer instance
+// loadMode is the information loaded for every project package
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+	packages.NeedImports | packages.NeedTypes | packages.NeedTypesSizes | packages.NeedModule |
+	packages.NeedSyntax | packages.NeedTypesInfo
+
+// NewAnalyzer creates a new analyzer instance with a copy of config
 func NewAnalyzer(config *Config) *Analyzer {
-	return &Analyzer{
-		config:     config,
-		fileSet:    token.NewFileSet(),
-		symbols:    make(map[string]*Symbol),
-		references: make(map[string][]Reference),
-		reachable:  make(map[string]bool),
+	return New(WithConfig(config))
+}
+
+// reset clears the state of a previous analysis
+func (a *Analyzer) reset() {
+	*a = Analyzer{
+		config:          a.config,
+		cache:           a.cache,
+		moduleOnly:      a.moduleOnly,
+		fileSet:         token.NewFileSet(),
+		symbols:         make(map[string]*Symbol),
+		objectIDs:       make(map[types.Object]int32),
+		keyPaths:        make(map[*types.Package]string),
+		moduleDirs:      make(map[*types.Package]string),
+		projectPkgs:     make(map[string]bool),
+		references:      make(map[int32][]token.Pos),
+		aliasLinks:      make(map[string][]string),
+		walkedFiles:     make(map[string]bool),
+		declUses:        make(map[int32][]fileUse),
+		initUses:        make(map[string][]fileUse),
+		keepAliveUses:   make(map[string][]fileUse),
+		assertedMethods: make(map[string][]int32),
+		keepAlivePos:    make(map[int32]token.Pos),
+		varWrites:       make(map[int32][]token.Pos),
+		writePositions:  make(map[token.Pos]bool),
+		funcSinks:       make(map[token.Pos]string),
+		graph:           newSymbolGraph(),
+		usedMethods:     make(map[string]bool),
+		callbacks:       make(map[int32]bool),
+		handlers:        make(map[int32]string),
+		linknamed:       make(map[int32]bool),
+		assemblyRefs:    make(map[int32]bool),
+		cgoSymbols:      make(map[int32]bool),
+		generatedFiles:  make(map[string]bool),
+		fileDecls:       make(map[string]*fileDecls),
+		reflectedTypes:  make(map[string]*types.Named),
+		allowlisted:     make(map[int32]bool),
+		wellKnown:       make(map[int32]bool),
+		invokedMethods:  make(map[int32][]*types.Func),
+		instantiated:    make(map[int32]bool),
+		genericDeps:     make(map[int32][]int32),
 	}
 }
 
-// Analyze performs the complete orphaned code analysis
+// Analyze performs the complete orphaned code analysis. Each call starts from a clean
+// state, so an analyzer can be reused for sequential analyses.
 func (a *Analyzer) Analyze() (*AnalysisResult, error) {
+	if a.config.Recursive {
+		return a.analyzeRecursive()
+	}
+	if len(a.config.BuildMatrix) > 0 {
+		return a.analyzeMatrix()
+	}
+	return a.analyzeBuild(nil)
+}
+
+// analyzeBuild analyzes the project as built for target, or for the host when target is nil
+func (a *Analyzer) analyzeBuild(target *BuildTarget) (*AnalysisResult, error) {
+	a.reset()
+	a.target = target
+
+	done := a.startPhase(PhaseLoad)
 	if err := a.loadProject(); err != nil {
 		return nil, fmt.Errorf("loading project: %w", err)
 	}
+	done()
 
 	if a.config.Verbose && !a.config.OutputJSON {
 		fmt.Printf("📦 Loaded %d packages\n", len(a.packages))
 	}
+	a.skipLargePackages()
 
+	done = a.startPhase(PhaseSymbols)
 	if err := a.findSymbols(); err != nil {
 		return nil, fmt.Errorf("finding symbols: %w", err)
 	}
+	done()
 
 	if a.config.Verbose && !a.config.OutputJSON {
 		fmt.Printf("🔍 Found %d symbols\n", len(a.symbols))
 	}
 
+	done = a.startPhase(PhaseReferences)
 	if err := a.findReferences(); err != nil {
 		return nil, fmt.Errorf("finding references: %w", err)
 	}
 
+	if a.config.Fields {
+		a.findFields()
+	}
+	if a.config.Results {
+		a.findResults()
+	}
+	if a.config.Wrappers {
+		a.findWrappers()
+	}
+	done()
+
+	done = a.startPhase(PhaseGraph)
+	a.buildGraph()
+	if a.config.SizeEstimate {
+		a.estimateSizes()
+	}
+	a.propagateInstantiations()
+	a.findInterfaceNarrowing()
+	a.findAllowlistedMethods()
+	if a.usesCallGraph() {
+		a.buildProgram()
+	}
+
+	// Probing and code actions edit declarations and still need the syntax trees, and a
+	// daemon keeps them for the next analysis
+	if !a.config.Probe && !a.config.CodeActions && a.cache == nil {
+		a.releaseSyntax()
+	}
+	done()
+
+	done = a.startPhase(PhaseReachability)
 	if err := a.identifyMainPackages(); err != nil {
 		return nil, fmt.Errorf("identifying main packages: %w", err)
 	}
 
+	a.reportShard()
+
 	if err := a.traceReachability(); err != nil {
 		return nil, fmt.Errorf("tracing reachability: %w", err)
 	}
+	done()
+
+	done = a.startPhase(PhaseFindings)
+	orphans, mainHelpers := a.splitMainHelpers(a.findOrphans())
+	orphans, generatedOrphans := a.splitGenerated(orphans)
+	sortOrphans(orphans, a.config.Sort)
+	sortOrphans(mainHelpers, SortFile)
+	sortOrphans(generatedOrphans, SortFile)
+
+	if err := a.linkGeneratedTwins(orphans); err != nil {
+		return nil, fmt.Errorf("linking generated files: %w", err)
+	}
+
+	a.findBuildVariants(orphans)
+
+	if a.config.CoverProfile != "" {
+		if err := a.annotateCoverage(orphans); err != nil {
+			return nil, fmt.Errorf("cross-referencing coverage: %w", err)
+		}
+	}
+
+	var comparison *ToolComparison
+	if a.config.ImportFindings != "" {
+		var err error
+		if comparison, err = a.compareFindings(orphans); err != nil {
+			return nil, fmt.Errorf("cross-checking imported findings: %w", err)
+		}
+	}
+
+	var suggestedRoots []string
+	if len(a.config.PprofProfiles) > 0 {
+		roots, err := a.crossCheckProfiles(orphans)
+		if err != nil {
+			return nil, fmt.Errorf("cross-checking runtime profiles: %w", err)
+		}
+		suggestedRoots = roots
+	}
+
+	if a.config.Probe {
+		if _, err := a.probeOrphans(orphans); err != nil {
+			return nil, fmt.Errorf("probing orphans: %w", err)
+		}
+	}
+
+	stateCounts, err := a.applyBaseline(orphans)
+	if err != nil {
+		return nil, fmt.Errorf("applying baseline: %w", err)
+	}
+
+	if a.config.CodeActions {
+		a.attachCodeActions(orphans)
+	}
+
+	var sizeClusters []*SizeCluster
+	if a.config.SizeEstimate {
+		sizeClusters = a.sizeClusters(orphans)
+	}
 
-	orphans := a.findOrphans()
+	var byAuthor []AuthorSummary
+	if a.config.ByAuthor {
+		byAuthor, err = a.summarizeByAuthor(orphans)
+		if err != nil {
+			return nil, fmt.Errorf("summarizing by author: %w", err)
+		}
+	}
+
+	byComponent, err := a.summarizeByComponent(orphans)
+	if err != nil {
+		return nil, fmt.Errorf("grouping by component: %w", err)
+	}
+	// The findings of a build matrix are known once every configuration is analyzed
+	if target == nil {
+		a.emitFindings(orphans)
+	}
 
+	totalSymbols, reachableSymbols := a.symbolCounts()
 	result := &AnalysisResult{
 		ProjectPath:      a.config.ProjectPath,
-		TotalSymbols:     len(a.symbols),
-		ReachableSymbols: len(a.reachable),
+		TotalSymbols:     totalSymbols,
+		ReachableSymbols: reachableSymbols,
 		MainPackages:     len(a.mainPackages),
+		Semantics:        a.semantics,
+		Precision:        a.config.Precision,
 		OrphanedSymbols:  orphans,
 		ExcludedPackages: a.config.Exclude,
+		SkippedPackages:  a.skippedPackages,
 		IncludedTests:    a.config.IncludeTests,
+		SuggestedRoots:   suggestedRoots,
+		StateCounts:      stateCounts,
+		ByAuthor:         byAuthor,
+		ByComponent:      byComponent,
+
+		InterfaceNarrowings: a.reachableNarrowings(),
+		DocsOnlySymbols:     a.findDocsOnly(),
+		ObsoleteFiles:       a.findObsoleteFiles(),
+		OrphanedFiles:       a.orphanedFiles(orphans),
+		SizeClusters:        sizeClusters,
+		ExtractableClusters: a.extractableClusters(orphans),
+		DeadClusters:        a.deadClusters(orphans),
+
+		UnusedConstraintPackages: a.unusedConstraintPackages(orphans),
+		OrphanedPackages:         a.orphanedPackages(),
+		WriteOnlyVariables:       a.writeOnlyVariables(),
+		KeptByAssertion:          a.keptByAssertion(),
+		MainHelpers:              mainHelpers,
+		GeneratedOrphans:         generatedOrphans,
+		ToolComparison:           comparison,
+	}
+	if a.config.Fields {
+		result.UnreadFields = a.unreadFields()
+	}
+	if a.config.Results {
+		result.UnusedResults = a.unusedResults()
+	}
+	if a.config.Wrappers {
+		result.TrivialWrappers = a.trivialWrappers()
+	}
+	if a.config.SuggestUnexport {
+		result.UnexportCandidates = a.unexportCandidates()
+	}
+	if a.config.WithReferences {
+		result.References = a.crossReferences()
 	}
+	result.UncalledFunctions = a.uncalledFunctions(result.WriteOnlyVariables, result.UnreadFields)
+	result.Labels = a.labelKeys(result)
+	done()
 
 	return result, nil
 }
 
 // loadProject loads all packages in the project
 func (a *Analyzer) loadProject() error {
-	cfg := &packages.Config{
-		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
-			packages.NeedImports | packages.NeedTypes | packages.NeedTypesSizes |
-			packages.NeedSyntax | packages.NeedTypesInfo,
-		Dir:   a.config.ProjectPath,
-		Fset:  a.fileSet,
-		Tests: a.config.IncludeTests,
+	if err := checkLoadPreconditions(a.config.ProjectPath); err != nil {
+		return err
 	}
 
 	if a.config.Verbose && !a.config.OutputJSON {
@@ -83,16 +289,26 @@ func (a *Analyzer) loadProject() error {
 		}
 	}
 
-	pkgs, err := packages.Load(cfg, "./...")
+	var pkgs []*packages.Package
+	var err error
+	if a.cache != nil {
+		pkgs, err = a.cache.load(a)
+	} else {
+		pkgs, err = a.loadModules(nil)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to load packages: %w", err)
+		return err
 	}
 
 	// Filter out packages with errors and excluded packages
 	var validPkgs []*packages.Package
+	var firstErr error
 	for _, pkg := range pkgs {
 		// Skip packages with errors
 		if len(pkg.Errors) > 0 {
+			if firstErr == nil {
+				firstErr = pkg.Errors[0]
+			}
 			if a.config.Verbose && !a.config.OutputJSON {
 				fmt.Printf("⚠️  Skipping package %s due to errors:\n", pkg.PkgPath)
 				for _, err := range pkg.Errors {
@@ -110,28 +326,206 @@ func (a *Analyzer) loadProject() error {
 			continue
 		}
 
+		if reason := a.skippedDirectory(pkg.Dir); pkg.Dir != "" && reason != "" {
+			if a.config.Verbose && !a.config.OutputJSON {
+				fmt.Printf("📋 Skipping package %s (under %s)\n", pkg.PkgPath, reason)
+			}
+			continue
+		}
+
 		validPkgs = append(validPkgs, pkg)
+		if a.config.Hooks.PackageLoaded != nil {
+			a.config.Hooks.PackageLoaded(pkg.PkgPath, len(pkg.CompiledGoFiles))
+		}
+	}
+
+	// Nothing loadable at all usually means a broken environment rather than broken code
+	if len(validPkgs) == 0 && firstErr != nil {
+		loadErr := classifyLoadError(firstErr)
+		if loadErr.Code == ErrCodeLoadFailed {
+			loadErr.Code = ErrCodeNoPackages
+			loadErr.Condition = "no package in the project could be loaded"
+		}
+		return loadErr
 	}
 
 	a.packages = validPkgs
 	return nil
 }
 
+// loadModules loads the project module and every nested module, each from its own
+// directory so that the go command resolves it against its own go.mod. The modules of a
+// go.work workspace at the project root are loaded together instead, so that the
+// references between them resolve to the same declarations.
+func (a *Analyzer) loadModules(overlay map[string][]byte) ([]*packages.Package, error) {
+	workspace, err := findWorkspaceModules(a.config.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	workspace = slices.DeleteFunc(workspace, func(dir string) bool { return a.skippedDirectory(dir) != "" })
+
+	patterns, err := a.loadPatterns(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := a.loadPackages(a.config.ProjectPath, patterns, overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	var nested []string
+	if !a.moduleOnly {
+		if nested, err = findNestedModules(a.config.ProjectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// Packages can be reached from several modules (replace directives); keep the first
+	// copy of each package directory. Copies of an import path in other directories, such
+	// as vendored forks, are analyzed on their own.
+	seen := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		seen[packageCopy(pkg)] = true
+	}
+
+	for _, dir := range nested {
+		if a.skippedDirectory(dir) != "" || slices.Contains(workspace, dir) {
+			continue
+		}
+		if a.config.Verbose && !a.config.OutputJSON && overlay == nil {
+			fmt.Printf("📦 Loading nested module in %s\n", dir)
+		}
+
+		nestedPkgs, err := a.loadPackages(dir, []string{"./..."}, overlay)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range nestedPkgs {
+			if !seen[packageCopy(pkg)] {
+				seen[packageCopy(pkg)] = true
+				pkgs = append(pkgs, pkg)
+			}
+		}
+	}
+
+	return pkgs, nil
+}
+
+// packageCopy identifies a loaded package by its ID and the module directory providing it
+func packageCopy(pkg *packages.Package) string {
+	if pkg.Module == nil {
+		return pkg.ID
+	}
+	return pkg.ID + "@" + pkg.Module.Dir
+}
+
+// loadPackages loads packages matching patterns relative to dir, with optional file overlays
+func (a *Analyzer) loadPackages(dir string, patterns []string, overlay map[string][]byte) ([]*packages.Package, error) {
+	// Call graphs are built over the whole program, dependencies included
+	mode := loadMode
+	if a.usesCallGraph() {
+		mode |= packages.NeedDeps
+	}
+
+	cfg := &packages.Config{
+		Mode:    mode,
+		Dir:     dir,
+		Fset:    a.fileSet,
+		Tests:   a.config.IncludeTests,
+		Overlay: overlay,
+	}
+	if a.target != nil {
+		cfg.Env = a.target.env(os.Environ())
+	}
+	if tags := a.buildTags(); len(tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(tags, ",")}
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, classifyLoadError(err)
+	}
+	return pkgs, nil
+}
+
+// buildTags returns the build tags set with --tags, followed by those of the build matrix
+// configuration analyzed
+func (a *Analyzer) buildTags() []string {
+	tags := a.config.Tags
+	if a.target != nil {
+		tags = append(append([]string(nil), tags...), a.target.Tags...)
+	}
+	return tags
+}
+
+// loadPatterns returns the package patterns that make up the project: every package
+// below its root, or of the workspace modules when it is a go.work workspace
+func (a *Analyzer) loadPatterns(workspace []string) ([]string, error) {
+	patterns := []string{"./..."}
+	if len(workspace) > 0 {
+		patterns = patterns[:0]
+		for _, dir := range workspace {
+			patterns = append(patterns, filepath.Join(dir, "..."))
+		}
+		if a.config.Verbose && !a.config.OutputJSON {
+			fmt.Printf("🧩 Loading the %d module(s) of go.work together\n", len(workspace))
+		}
+	}
+	if a.config.IncludeTestdata {
+		testdata, err := testdataPatterns(a.config.ProjectPath)
+		if err != nil {
+			return nil, err
+		}
+		if a.config.Verbose && !a.config.OutputJSON && len(testdata) > 0 {
+			fmt.Printf("🧪 Including %d testdata package(s)\n", len(testdata))
+		}
+		patterns = append(patterns, testdata...)
+	}
+	if a.config.IncludeReplaced {
+		replacements, err := findLocalReplacements(a.config.ProjectPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, rep := range replacements {
+			if a.config.Verbose && !a.config.OutputJSON {
+				fmt.Printf("🔗 Including replaced module %s => %s\n", rep.ModulePath, rep.Dir)
+			}
+			patterns = append(patterns, rep.ModulePath+"/...")
+		}
+	}
+	return patterns, nil
+}
+
 // isPackageExcluded checks if a package should be excluded based on patterns
 func (a *Analyzer) isPackageExcluded(pkgPath string) bool {
 	for _, pattern := range a.config.Exclude {
-		if matched, _ := filepath.Match(pattern, pkgPath); matched {
+		if matchPackagePath(pattern, pkgPath) {
 			return true
 		}
 		// Also check if the pattern matches any part of the path
-		if strings.Contains(pkgPath, strings.Trim(pattern, "*")) {
+		if strings.Contains(pkgPath, strings.Trim(filepath.ToSlash(pattern), "*")) {
 			return true
 		}
 	}
 	return false
 }
 
-// identifyMainPackages finds all main packages in the project
+// Analysis semantics deciding which symbols are roots
+const (
+	SemanticsAuto   = "auto"   // binary when the project has main packages, module otherwise
+	SemanticsBinary = "binary" // only what main packages can reach is used
+	SemanticsModule = "module" // the exported API of non-internal packages is used as well
+)
+
+// rootModes maps the values of --roots, named after the roots they select, to semantics
+var rootModes = map[string]string{
+	"main":     SemanticsBinary,
+	"exported": SemanticsModule,
+	"auto":     SemanticsAuto,
+}
+
+// identifyMainPackages finds all main packages in the project and resolves the semantics
 func (a *Analyzer) identifyMainPackages() error {
 	for _, pkg := range a.packages {
 		if pkg.Name == "main" {
@@ -139,26 +533,44 @@ func (a *Analyzer) identifyMainPackages() error {
 		}
 	}
 
-	if len(a.mainPackages) == 0 {
-		if a.config.Verbose && !a.config.OutputJSON {
-			fmt.Println("⚠️  No main packages found - analyzing all packages for internal usage")
-		}
-		// If no main packages, treat all packages as potentially reachable
-		for _, pkg := range a.packages {
-			a.mainPackages = append(a.mainPackages, pkg)
+	a.semantics = a.config.Semantics
+	if a.semantics == SemanticsAuto || a.semantics == "" {
+		a.semantics = SemanticsBinary
+		if len(a.mainPackages) == 0 {
+			a.semantics = SemanticsModule
 		}
-	} else {
-		if a.config.Verbose && !a.config.OutputJSON {
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		switch {
+		case len(a.mainPackages) > 0:
 			fmt.Printf("📦 Found %d main package(s)\n", len(a.mainPackages))
 			for _, pkg := range a.mainPackages {
 				fmt.Printf("    %s\n", pkg.PkgPath)
 			}
+		case a.semantics == SemanticsBinary:
+			fmt.Println("⚠️  No main packages found - binary semantics leaves nothing reachable")
+		default:
+			fmt.Println("⚠️  No main packages found - analyzing as a library")
+		}
+		if a.semantics == SemanticsModule {
+			fmt.Println("📚 Module semantics: exported symbols of non-internal packages are roots")
 		}
 	}
 
 	return nil
 }
 
+// isInternalPackage reports whether a package path lies under an internal directory
+func isInternalPackage(pkgPath string) bool {
+	for _, element := range strings.Split(pkgPath, "/") {
+		if element == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
 // getSymbolKey generates a unique key for a symbol
 func (a *Analyzer) getSymbolKey(pkgPath, name, kind string) string {
 	return fmt.Sprintf("%s.%s.%s", pkgPath, name, kind)
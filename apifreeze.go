@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// APIFreezeFile holds the snapshot of the exported API, in the project root by default
+const APIFreezeFile = ".gorphanage-api.json"
+
+var (
+	apiFreezeFile      string
+	apiFreezeUpdate    bool
+	apiFreezeJSON      bool
+	apiFreezeConsumers []string
+)
+
+// APISnapshot is the exported API of a library at the time it was frozen: the symbols
+// module semantics keeps as public API, by key
+type APISnapshot struct {
+	Version int      `json:"version"`
+	Symbols []string `json:"symbols"`
+}
+
+// APIFreezeReport compares the exported API with its snapshot. An exported symbol added
+// since the snapshot that neither the main packages nor the declared consumers reach was
+// dead on arrival: nothing in the project uses it yet, and once released it can't be
+// removed without breaking importers of a v1.
+type APIFreezeReport struct {
+	Snapshot      string    `json:"snapshot"`
+	Recorded      bool      `json:"recorded"` // the snapshot was written by this run
+	Exported      int       `json:"exported"`
+	Added         []string  `json:"added"`           // exported since the snapshot and used
+	DeadOnArrival []*Symbol `json:"dead_on_arrival"` // exported since the snapshot and unused
+	Removed       []string  `json:"removed"`         // in the snapshot, no longer exported
+}
+
+// exportedAPI returns the keys of the exported symbols of the public API, sorted
+func (a *Analyzer) exportedAPI() []string {
+	api := []string{}
+	for key, symbol := range a.symbols {
+		if symbol.Exported && !isInternalPackage(symbol.Package) && !a.isMainPackage(symbol.Package) {
+			api = append(api, key)
+		}
+	}
+	sort.Strings(api)
+	return api
+}
+
+// loadAPISnapshot reads an API snapshot, returning nil if there is none yet
+func loadAPISnapshot(path string) (*APISnapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API snapshot: %w", err)
+	}
+	var snapshot APISnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// save writes the snapshot in a stable, diff-friendly format
+func (s *APISnapshot) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal API snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write API snapshot: %w", err)
+	}
+	return nil
+}
+
+// APIFreeze analyzes a library under binary semantics, with every function and method of
+// the consumer packages as an entry point, and compares its exported API with the
+// snapshot at path. Without a snapshot, or with update, the current API is recorded.
+func APIFreeze(config *Config, path string, consumers []string, update bool) (*APIFreezeReport, error) {
+	config.Semantics = SemanticsBinary
+	for _, consumer := range consumers {
+		entry := EntryPoint{Pattern: ".", Packages: consumer, Reason: "declared API consumer"}
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("invalid --consumer %q: %w", consumer, err)
+		}
+		config.EntryPoints = append(config.EntryPoints, entry)
+	}
+
+	analyzer, _, err := analyze(config)
+	if err != nil {
+		return nil, err
+	}
+	api := analyzer.exportedAPI()
+
+	report := &APIFreezeReport{
+		Snapshot:      path,
+		Exported:      len(api),
+		Added:         []string{},
+		DeadOnArrival: []*Symbol{},
+		Removed:       []string{},
+	}
+	snapshot, err := loadAPISnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil || update {
+		report.Recorded = true
+		return report, (&APISnapshot{Version: 1, Symbols: api}).save(path)
+	}
+
+	frozen := make(map[string]bool, len(snapshot.Symbols))
+	for _, key := range snapshot.Symbols {
+		frozen[key] = true
+	}
+	current := make(map[string]bool, len(api))
+	for _, key := range api {
+		current[key] = true
+		if frozen[key] {
+			continue
+		}
+		if analyzer.isReachable(key) {
+			report.Added = append(report.Added, key)
+		} else {
+			report.DeadOnArrival = append(report.DeadOnArrival, analyzer.symbols[key])
+		}
+	}
+	for _, key := range snapshot.Symbols {
+		if !current[key] {
+			report.Removed = append(report.Removed, key)
+		}
+	}
+	sortOrphans(report.DeadOnArrival, SortFile)
+	return report, nil
+}
+
+// printAPIFreeze outputs an API freeze report in human-readable format
+func printAPIFreeze(projectPath string, report *APIFreezeReport) {
+	if report.Recorded {
+		fmt.Printf("📝 Recorded %d exported symbol(s) in %s\n", report.Exported, report.Snapshot)
+		return
+	}
+
+	fmt.Printf("🧊 %d exported symbol(s), %d added and %d removed since %s\n",
+		report.Exported, len(report.Added)+len(report.DeadOnArrival), len(report.Removed), report.Snapshot)
+	if len(report.DeadOnArrival) > 0 {
+		fmt.Printf("\n💀 Dead on arrival (%d):\n", len(report.DeadOnArrival))
+		for _, symbol := range report.DeadOnArrival {
+			file, err := filepath.Rel(projectPath, symbol.File)
+			if err != nil {
+				file = symbol.File
+			}
+			fmt.Printf("  • %s.%s (%s) at %s\n", symbol.Package, symbol.displayName(), symbol.Kind, formatPosition(file, symbol.Start))
+		}
+	}
+	if len(report.Added) > 0 {
+		fmt.Printf("\n➕ Added and used (%d):\n", len(report.Added))
+		for _, key := range report.Added {
+			fmt.Printf("  • %s\n", key)
+		}
+	}
+	if len(report.Removed) > 0 {
+		fmt.Printf("\n➖ Removed (%d):\n", len(report.Removed))
+		for _, key := range report.Removed {
+			fmt.Printf("  • %s\n", key)
+		}
+	}
+	if len(report.DeadOnArrival) > 0 {
+		fmt.Printf("\n💡 Use them from a consumer or unexport them before the release; once intended, record the API with: gorphanage api-freeze --update\n")
+	}
+}
+
+var apiFreezeCmd = &cobra.Command{
+	Use:   "api-freeze [path]",
+	Short: "Report exported symbols added to a library while already unused",
+	Long: `Snapshots the exported API of a library, the symbols module semantics keeps as public
+API, into .gorphanage-api.json. Later runs compare the API with the snapshot and report
+the exported symbols added since that are dead on arrival: neither the main packages
+nor the consumer packages declared with --consumer, such as examples or an in-repo
+client, reach them. For a v0 library, that is the last chance to drop or unexport them
+before a v1 freezes them for good. The command fails when it finds any; the snapshot is
+recorded on the first run and with --update.`,
+	Example: `  gorphanage api-freeze
+  gorphanage api-freeze --consumer 'github.com/acme/lib/examples/...'
+  gorphanage api-freeze --update`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+		config, err := configFromViper(projectPath)
+		if err != nil {
+			return err
+		}
+		config.OutputJSON = config.OutputJSON || apiFreezeJSON
+		cmd.SilenceUsage = true
+
+		path := apiFreezeFile
+		if path == "" {
+			path = filepath.Join(config.ProjectPath, APIFreezeFile)
+		}
+		report, err := APIFreeze(config, path, apiFreezeConsumers, apiFreezeUpdate)
+		if err != nil {
+			return err
+		}
+		if config.OutputJSON {
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			printAPIFreeze(config.ProjectPath, report)
+		}
+
+		if len(report.DeadOnArrival) > 0 {
+			return fmt.Errorf("%d exported symbol(s) dead on arrival", len(report.DeadOnArrival))
+		}
+		return nil
+	},
+}
+
+func init() {
+	apiFreezeCmd.Flags().StringVar(&apiFreezeFile, "file", "", "API snapshot file (default: .gorphanage-api.json in the project)")
+	apiFreezeCmd.Flags().BoolVar(&apiFreezeUpdate, "update", false, "record the current exported API as the snapshot")
+	apiFreezeCmd.Flags().BoolVar(&apiFreezeJSON, "json", false, "output the comparison in JSON format")
+	apiFreezeCmd.Flags().StringSliceVar(&apiFreezeConsumers, "consumer", nil, "import path pattern of a consumer package of the API, e.g. github.com/acme/lib/examples/... (repeatable)")
+	rootCmd.AddCommand(apiFreezeCmd)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// recordAssertions records the methods checked by compile-time interface assertions in a
+// blank variable declaration, var _ io.Writer = (*T)(nil) or var _ = io.Writer(T{}). Such an
+// assertion states that T implements the interface on purpose, so the methods implementing
+// it are kept whenever the package is linked, even if nothing calls them yet.
+func (a *Analyzer) recordAssertions(pkg *packages.Package, spec *ast.ValueSpec) {
+	keyPath := a.keyPath(pkg.Types)
+	for _, value := range spec.Values {
+		target, expr := spec.Type, value
+		// var _ = I(value)
+		if call, ok := ast.Unparen(value).(*ast.CallExpr); ok && target == nil && len(call.Args) == 1 {
+			if tv, ok := pkg.TypesInfo.Types[call.Fun]; ok && tv.IsType() {
+				target, expr = call.Fun, call.Args[0]
+			}
+		}
+		if target == nil {
+			continue
+		}
+
+		iface, ok := pkg.TypesInfo.TypeOf(target).Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		t := pkg.TypesInfo.TypeOf(expr)
+		if t == nil || types.IsInterface(t) || !types.AssignableTo(t, iface) {
+			continue
+		}
+
+		for i := 0; i < iface.NumMethods(); i++ {
+			obj, _, _ := types.LookupFieldOrMethod(t, true, pkg.Types, iface.Method(i).Name())
+			fn, ok := obj.(*types.Func)
+			if !ok {
+				continue
+			}
+			// Methods of dependencies have nothing to keep
+			if id, ok := a.objectIDs[fn.Origin()]; ok {
+				a.assertedMethods[keyPath] = append(a.assertedMethods[keyPath], id)
+				a.recordKeepAlive(id, value.Pos())
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AuthorSummary counts the orphaned symbols and lines an author last touched
+type AuthorSummary struct {
+	Author  string `json:"author"`
+	Symbols int    `json:"symbols"`
+	Lines   int    `json:"lines"`
+}
+
+// summarizeByAuthor attributes orphaned lines to their last author with git blame. Each
+// symbol counts for the author of most of its lines. This is a heuristic: the last author
+// of a line is not necessarily the one who made it dead.
+func (a *Analyzer) summarizeByAuthor(orphans []*Symbol) ([]AuthorSummary, error) {
+	if _, err := gitOutput(a.config.ProjectPath, "rev-parse", "--show-toplevel"); err != nil {
+		return nil, fmt.Errorf("--by-author needs a git repository: %w", err)
+	}
+
+	byFile := make(map[string][]*Symbol)
+	for _, orphan := range orphans {
+		byFile[orphan.File] = append(byFile[orphan.File], orphan)
+	}
+
+	summaries := make(map[string]*AuthorSummary)
+	summaryFor := func(author string) *AuthorSummary {
+		if summaries[author] == nil {
+			summaries[author] = &AuthorSummary{Author: author}
+		}
+		return summaries[author]
+	}
+
+	for file, symbols := range byFile {
+		lineAuthors, err := blameAuthors(file)
+		if err != nil {
+			// Untracked files have no history to attribute
+			if a.config.Verbose && !a.config.OutputJSON {
+				fmt.Printf("⚠️  Skipping blame for %s: %v\n", file, err)
+			}
+			continue
+		}
+
+		for _, symbol := range symbols {
+			lines := make(map[string]int)
+			for line := symbol.Start.Line; line <= symbol.End.Line && line <= len(lineAuthors); line++ {
+				lines[lineAuthors[line-1]]++
+			}
+
+			owner := ""
+			for author, count := range lines {
+				summaryFor(author).Lines += count
+				if owner == "" || count > lines[owner] || (count == lines[owner] && author < owner) {
+					owner = author
+				}
+			}
+			if owner != "" {
+				summaryFor(owner).Symbols++
+			}
+		}
+	}
+
+	result := make([]AuthorSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Lines != result[j].Lines {
+			return result[i].Lines > result[j].Lines
+		}
+		return result[i].Author < result[j].Author
+	})
+
+	return result, nil
+}
+
+// blameAuthors returns the last author of every line in a file
+func blameAuthors(file string) ([]string, error) {
+	out, err := gitOutput(filepath.Dir(file), "blame", "--line-porcelain", "--", filepath.Base(file))
+	if err != nil {
+		return nil, err
+	}
+
+	// --line-porcelain repeats the full header, including the author, for every line
+	var authors []string
+	for _, line := range strings.Split(out, "\n") {
+		if author, ok := strings.CutPrefix(line, "author "); ok {
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
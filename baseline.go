@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+// DefaultBaselineFile is the baseline looked up in the project root when none is configured
+const DefaultBaselineFile = ".gorphanage-baseline.json"
+
+// Finding lifecycle states
+const (
+	StateNew          = "new"
+	StateAcknowledged = "acknowledged"
+	StateWontfix      = "wontfix"
+)
+
+// validStates lists the states that can be recorded in a baseline
+var validStates = []string{StateNew, StateAcknowledged, StateWontfix}
+
+// Baseline records known findings and their triage state
+type Baseline struct {
+	Version  int              `json:"version"`
+	Findings []*BaselineEntry `json:"findings"`
+
+	path    string
+	entries map[string]*BaselineEntry
+}
+
+// BaselineEntry is a single triaged finding
+type BaselineEntry struct {
+	Fingerprint string    `json:"fingerprint"`
+	Package     string    `json:"package"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	State       string    `json:"state"`
+	Reason      string    `json:"reason,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Updated     time.Time `json:"updated"`
+}
+
+// fingerprint identifies a finding independently of its position in the file
+func fingerprint(symbol *Symbol) string {
+	return fmt.Sprintf("%s.%s.%s", symbol.keyPackage(), symbol.keyName(), symbol.Kind)
+}
+
+// legacyFingerprint is the fingerprint of a method in baselines written before methods
+// were keyed by receiver type: pkg.Method.function
+func legacyFingerprint(symbol *Symbol) string {
+	return fmt.Sprintf("%s.%s.function", symbol.Package, symbol.Name)
+}
+
+// resolveBaselinePath returns the configured baseline path or the default one in the project root
+func resolveBaselinePath(projectPath, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	return filepath.Join(projectPath, DefaultBaselineFile)
+}
+
+// LoadBaseline reads a baseline file; a missing file yields an empty baseline
+func LoadBaseline(path string) (*Baseline, error) {
+	baseline := &Baseline{Version: 1, path: path, entries: make(map[string]*BaselineEntry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	for _, entry := range baseline.Findings {
+		baseline.entries[entry.Fingerprint] = entry
+	}
+
+	return baseline, nil
+}
+
+// Save writes the baseline back to disk in a stable order
+func (b *Baseline) Save() error {
+	b.Findings = make([]*BaselineEntry, 0, len(b.entries))
+	for _, entry := range b.entries {
+		b.Findings = append(b.Findings, entry)
+	}
+	sort.Slice(b.Findings, func(i, j int) bool {
+		return b.Findings[i].Fingerprint < b.Findings[j].Fingerprint
+	})
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.WriteFile(b.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Set records the state of a finding, creating the entry if needed
+func (b *Baseline) Set(symbol *Symbol, state, reason, author string) {
+	fp := fingerprint(symbol)
+	entry, ok := b.entries[fp]
+	if !ok {
+		entry = &BaselineEntry{
+			Fingerprint: fp,
+			Package:     symbol.Package,
+			Name:        symbol.keyName(),
+			Kind:        symbol.Kind,
+		}
+		b.entries[fp] = entry
+	}
+	entry.State = state
+	entry.Reason = reason
+	entry.Author = author
+	entry.Updated = time.Now().UTC().Truncate(time.Second)
+}
+
+// StateOf returns the recorded state of a finding, or "new" when it is not in the baseline
+func (b *Baseline) StateOf(symbol *Symbol) string {
+	if entry, ok := b.entries[fingerprint(symbol)]; ok && entry.State != "" {
+		return entry.State
+	}
+	if symbol.Kind == "method" {
+		if entry, ok := b.entries[legacyFingerprint(symbol)]; ok && entry.State != "" {
+			return entry.State
+		}
+	}
+	return StateNew
+}
+
+// applyBaseline annotates orphans with their lifecycle state and returns counts by state
+func (a *Analyzer) applyBaseline(orphans []*Symbol) (map[string]int, error) {
+	path := resolveBaselinePath(a.config.ProjectPath, a.config.BaselineFile)
+	if a.config.BaselineFile == "" {
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, orphan := range orphans {
+		orphan.State = baseline.StateOf(orphan)
+		counts[orphan.State]++
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("📒 Applied baseline %s (%d recorded findings)\n", path, len(baseline.entries))
+	}
+
+	return counts, nil
+}
+
+// isValidState reports whether state is a known lifecycle state
+func isValidState(state string) bool {
+	for _, valid := range validStates {
+		if state == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Record acknowledges the findings not in the baseline yet, keeping the state of the
+// others, and returns how many were added
+func (b *Baseline) Record(findings []*Symbol, reason, author string) int {
+	added := 0
+	for _, symbol := range findings {
+		if b.StateOf(symbol) == StateNew {
+			b.Set(symbol, StateAcknowledged, reason, author)
+			added++
+		}
+	}
+	return added
+}
+
+// Prune drops the entries matching none of the findings, and returns how many were dropped
+func (b *Baseline) Prune(findings []*Symbol) int {
+	current := make(map[string]bool, len(findings))
+	for _, symbol := range findings {
+		current[fingerprint(symbol)] = true
+		if symbol.Kind == "method" {
+			current[legacyFingerprint(symbol)] = true
+		}
+	}
+	pruned := 0
+	for fp := range b.entries {
+		if !current[fp] {
+			delete(b.entries, fp)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// onlyNewFindings leaves the orphans recorded in the baseline out of a result, with
+// --fail-on-new, so only the findings missing from it are reported. Whole files and
+// clusters of orphans are kept while they hold a new one. State counts still cover every
+// orphan.
+func onlyNewFindings(result *AnalysisResult) {
+	if result.StateCounts == nil {
+		fmt.Fprintf(os.Stderr, "⚠️  No baseline found, every orphan is new: record the current ones with gorphanage baseline write\n")
+	}
+
+	var kept []*Symbol
+	fresh := make(map[string]bool)
+	for _, orphan := range result.OrphanedSymbols {
+		if orphan.State == "" || orphan.State == StateNew {
+			kept = append(kept, orphan)
+			fresh[fingerprint(orphan)] = true
+		}
+	}
+	result.BaselinedOrphans += len(result.OrphanedSymbols) - len(kept)
+	result.OrphanedSymbols = kept
+
+	isFresh := func(key string) bool { return fresh[key] }
+	var files []*OrphanedFile
+	for _, file := range result.OrphanedFiles {
+		if slices.ContainsFunc(file.Symbols, isFresh) {
+			files = append(files, file)
+		}
+	}
+	result.OrphanedFiles = files
+
+	var clusters []*DeadCluster
+	for _, cluster := range result.DeadClusters {
+		if slices.ContainsFunc(cluster.Root, isFresh) || slices.ContainsFunc(cluster.Unlocks, isFresh) {
+			clusters = append(clusters, cluster)
+		}
+	}
+	result.DeadClusters = clusters
+
+	var extractable []*ExtractableCluster
+	for _, cluster := range result.ExtractableClusters {
+		if slices.ContainsFunc(cluster.Symbols, isFresh) {
+			extractable = append(extractable, cluster)
+		}
+	}
+	result.ExtractableClusters = extractable
+
+	var sized []*SizeCluster
+	for _, cluster := range result.SizeClusters {
+		if slices.ContainsFunc(cluster.Symbols, isFresh) {
+			sized = append(sized, cluster)
+		}
+	}
+	result.SizeClusters = sized
+}
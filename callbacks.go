@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// CallbackRegistry is an API that stores a function to be invoked later by code the
+// analysis cannot follow, such as the runtime or an OS hook. Functions handed to it are
+// kept alive. Besides the built-in ones, registries are declared in the config file.
+type CallbackRegistry struct {
+	Func   string `mapstructure:"func"`  // qualified function or method taking the callback, e.g. runtime.SetFinalizer
+	Arg    int    `mapstructure:"arg"`   // index of the callback argument of Func
+	Field  string `mapstructure:"field"` // qualified struct field holding the callback, e.g. sync.Pool.New
+	Reason string `mapstructure:"reason"`
+}
+
+// builtinCallbackRegistries are the standard library callback registration patterns
+var builtinCallbackRegistries = []CallbackRegistry{
+	{Func: "runtime.SetFinalizer", Arg: 1},
+	{Field: "sync.Pool.New"},
+	{Func: "net/http.Server.RegisterOnShutdown", Arg: 0},
+	{Field: "net/http.Server.ConnState"},
+	{Field: "net/http.Server.BaseContext"},
+	{Field: "net/http.Server.ConnContext"},
+}
+
+// validate checks that a registry is well-formed
+func (r CallbackRegistry) validate() error {
+	if (r.Func == "") == (r.Field == "") {
+		return fmt.Errorf("callback registry needs exactly one of func or field")
+	}
+	if r.Arg < 0 {
+		return fmt.Errorf("invalid arg %d for %s", r.Arg, r.Func)
+	}
+	if r.Field != "" && strings.Count(r.Field[strings.LastIndex(r.Field, "/")+1:], ".") != 2 {
+		return fmt.Errorf("invalid field %q (expected pkg.Type.Field)", r.Field)
+	}
+	return nil
+}
+
+// callbackRegistries returns the built-in and configured callback registries
+func (a *Analyzer) callbackRegistries() []CallbackRegistry {
+	return append(append([]CallbackRegistry(nil), builtinCallbackRegistries...), a.config.CallbackRegistries...)
+}
+
+// findCallbackRegistrations records the functions a file hands to a callback registry,
+// either as a call argument or as the value of a registry field in a composite literal
+// or an assignment
+func (a *Analyzer) findCallbackRegistrations(pkg *packages.Package, file *ast.File) {
+	info := pkg.TypesInfo
+	registries := a.callbackRegistries()
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			callee := typeutil.StaticCallee(info, node)
+			if callee == nil {
+				return true
+			}
+			name := qualifiedFuncName(callee)
+			for _, registry := range registries {
+				if registry.Func == name && registry.Arg < len(node.Args) {
+					a.keepCallbackAlive(info, node.Args[registry.Arg])
+				}
+			}
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if isCallbackField(registries, qualifiedFieldName(info.TypeOf(node), key.Name)) {
+					a.keepCallbackAlive(info, kv.Value)
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				sel, ok := lhs.(*ast.SelectorExpr)
+				if !ok || i >= len(node.Rhs) {
+					continue
+				}
+				selection, ok := info.Selections[sel]
+				if !ok || selection.Kind() != types.FieldVal {
+					continue
+				}
+				if isCallbackField(registries, qualifiedFieldName(selection.Recv(), sel.Sel.Name)) {
+					a.keepCallbackAlive(info, node.Rhs[i])
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isCallbackField reports whether a qualified struct field is a callback registry
+func isCallbackField(registries []CallbackRegistry, field string) bool {
+	if field == "" {
+		return false
+	}
+	for _, registry := range registries {
+		if registry.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// keepCallbackAlive records the function named by a callback expression as a root.
+// Function literals need nothing: their references belong to the enclosing declaration.
+func (a *Analyzer) keepCallbackAlive(info *types.Info, expr ast.Expr) {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return
+	}
+	if id, ok := a.objectID(fn); ok {
+		if _, exists := a.symbols[a.graph.keys[id]]; exists {
+			a.callbacks[id] = true
+		}
+	}
+}
+
+// callbackRoots returns the keys of functions registered as callbacks
+func (a *Analyzer) callbackRoots() []string {
+	var roots []string
+	for id := range a.callbacks {
+		roots = append(roots, a.graph.keys[id])
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(roots) > 0 {
+		fmt.Printf("🪝 %d function(s) registered as callbacks kept alive\n", len(roots))
+	}
+
+	return roots
+}
+
+// qualifiedFuncName names a function as pkg.Func, or a method as pkg.Type.Method
+func qualifiedFuncName(fn *types.Func) string {
+	if key, ok := methodKey(fn.Origin()); ok {
+		return key
+	}
+	if fn.Pkg() == nil {
+		return fn.Name()
+	}
+	return fn.Pkg().Path() + "." + fn.Name()
+}
+
+// qualifiedFieldName names a field of a named struct type as pkg.Type.Field
+func qualifiedFieldName(t types.Type, field string) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	named = named.Origin()
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name() + "." + field
+}
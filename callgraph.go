@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/rta"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Precision modes deciding how calls between functions are resolved
+const (
+	PrecisionReferences = "references" // a function is used when a reachable declaration names it
+	PrecisionRTA        = "rta"        // a function is used when the RTA call graph calls it
+	PrecisionVTA        = "vta"        // RTA refined by propagating the types values can hold
+)
+
+// precisionTradeoffs describes what each precision mode resolves and what it costs, in
+// order of increasing precision
+var precisionTradeoffs = []struct {
+	mode     string
+	resolves string
+	cost     string
+}{
+	{PrecisionReferences, "any reference to a function or same-named method", "fastest, no SSA"},
+	{PrecisionRTA, "calls to types converted to interfaces anywhere", "whole-program SSA"},
+	{PrecisionVTA, "calls to types that can flow to the call site", "SSA + type propagation, slowest"},
+}
+
+// usesCallGraph reports whether calls are resolved with a call graph rather than references
+func (a *Analyzer) usesCallGraph() bool {
+	return a.config.Precision == PrecisionRTA || a.config.Precision == PrecisionVTA
+}
+
+// buildProgram builds the SSA form of the whole program, dependencies included, while the
+// syntax trees are still available
+func (a *Analyzer) buildProgram() {
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Println("🏗️  Building SSA program for call-graph analysis...")
+	}
+
+	prog, _ := ssautil.AllPackages(a.packages, ssa.InstantiateGenerics)
+	prog.Build()
+	a.program = prog
+}
+
+// applyCallGraph replaces the function and method edges of the symbol graph with the
+// edges of the call graph. Reference edges to types, variables and constants are
+// kept; a function or method is only reached through an actual call, a call through an
+// interface or function value to it included, or by being an entry point itself. Only
+// well-known methods stay linked to their receiver type.
+// Functions first called from outside the project (the runtime, or library code calling
+// back into an http.Handler) become additional entry points, which are returned together
+// with the original ones.
+func (a *Analyzer) applyCallGraph(entryPoints []int32) []int32 {
+	g := a.graph
+
+	// SSA functions of every project function and method, closures excluded
+	functions := make(map[int32][]*ssa.Function)
+	for fn := range ssautil.AllFunctions(a.program) {
+		if fn.Parent() != nil || fn.TypeParams().Len() > 0 {
+			continue
+		}
+		if id, ok := a.functionSymbol(fn); ok {
+			functions[id] = append(functions[id], fn)
+		}
+	}
+
+	var roots []*ssa.Function
+	for _, id := range entryPoints {
+		roots = append(roots, functions[id]...)
+	}
+	// Examples are traced on the same graph to tell docs-only symbols apart
+	if a.config.IncludeTests {
+		for key, symbol := range a.symbols {
+			if isExampleFunction(symbol) {
+				roots = append(roots, functions[g.ids[key]]...)
+			}
+		}
+	}
+	if len(roots) == 0 {
+		return entryPoints
+	}
+
+	cg := a.callGraph(roots)
+
+	calls := make(map[int32][]int32)
+	external := 0
+	queued := make(map[int32]bool, len(entryPoints))
+	for _, id := range entryPoints {
+		queued[id] = true
+	}
+	for _, fn := range roots {
+		if id, ok := a.functionSymbol(fn); ok {
+			queued[id] = true
+		}
+	}
+	for fn, node := range cg.Nodes {
+		caller, fromProject := a.functionSymbol(fn)
+		for _, edge := range node.Out {
+			callee, ok := a.functionSymbol(edge.Callee.Func)
+			if !ok || callee == caller {
+				continue
+			}
+			if fromProject {
+				calls[caller] = append(calls[caller], callee)
+			} else if !queued[callee] {
+				queued[callee] = true
+				entryPoints = append(entryPoints, callee)
+				external++
+			}
+		}
+	}
+
+	// Rebuild the edge lists: references to callables are replaced by calls
+	offsets := make([]int32, len(g.offsets))
+	var targets []int32
+	stamp := make([]int32, g.size())
+	for id := int32(0); id < int32(g.size()); id++ {
+		add := func(target int32) {
+			if target != id && stamp[target] != id+1 {
+				stamp[target] = id + 1
+				targets = append(targets, target)
+			}
+		}
+		for _, target := range g.successors(id) {
+			if !a.isCallable(target) || a.wellKnown[target] {
+				add(target)
+			}
+		}
+		for _, target := range calls[id] {
+			add(target)
+		}
+		offsets[id+1] = int32(len(targets))
+	}
+	g.offsets, g.targets = offsets, targets
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		edges := 0
+		for _, callees := range calls {
+			edges += len(callees)
+		}
+		fmt.Printf("📞 %s call graph: %d call(s) between project functions, %d function(s) first called from outside the project\n",
+			strings.ToUpper(a.config.Precision), edges, external)
+	}
+
+	return entryPoints
+}
+
+// callGraph builds the call graph of the code reachable from the roots. RTA only keeps
+// functions reachable from the roots; VTA then refines the calls of those functions
+// through interfaces and function values to the types that can actually reach them.
+func (a *Analyzer) callGraph(roots []*ssa.Function) *callgraph.Graph {
+	result := rta.Analyze(roots, true)
+	if a.config.Precision != PrecisionVTA {
+		return result.CallGraph
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🔬 Refining the call graph of %d reachable function(s) with VTA...\n", len(result.Reachable))
+	}
+
+	reachable := make(map[*ssa.Function]bool, len(result.Reachable))
+	for fn := range result.Reachable {
+		reachable[fn] = true
+	}
+	return vta.CallGraph(reachable, result.CallGraph)
+}
+
+// functionSymbol returns the ID of the project function or method an SSA function belongs
+// to. Closures belong to their enclosing declaration, instantiations to their generic
+// origin and wrappers to the method they wrap; package initializers and init#N functions
+// all map to the package's init symbol.
+func (a *Analyzer) functionSymbol(fn *ssa.Function) (int32, bool) {
+	if fn == nil {
+		return 0, false
+	}
+	for fn.Parent() != nil {
+		fn = fn.Parent()
+	}
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+
+	if obj := fn.Object(); obj != nil {
+		id, ok := a.objectIDs[obj]
+		return id, ok
+	}
+	if fn.Pkg != nil && (fn.Name() == "init" || strings.HasPrefix(fn.Name(), "init#")) {
+		key := a.getSymbolKey(a.keyPath(fn.Pkg.Pkg), "init", "function")
+		if _, exists := a.symbols[key]; exists {
+			return a.graph.ids[key], true
+		}
+	}
+	return 0, false
+}
+
+// isCallable reports whether a graph node is a project function or method
+func (a *Analyzer) isCallable(id int32) bool {
+	symbol, ok := a.symbols[a.graph.keys[id]]
+	return ok && (symbol.Kind == "function" || symbol.Kind == "method")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// findCgoExports records the functions of a cgo file exported to C with an //export
+// directive, which C code calls without any reference in Go source. The declarations
+// cmd/cgo adds to the files it generates, such as _Cgo_ptr or the _Cfunc_ wrappers, are
+// recorded as well: the C side and the runtime use them.
+func (a *Analyzer) findCgoExports(pkg *packages.Package, file *ast.File) {
+	generated := isCgoGenerated(file)
+	if !generated && !importsC(file) {
+		return
+	}
+
+	keep := func(name *ast.Ident, exported bool) {
+		if !exported && !(generated && isCgoName(name.Name)) {
+			return
+		}
+		if id, ok := a.objectIDs[pkg.TypesInfo.Defs[name]]; ok {
+			a.cgoSymbols[id] = true
+		}
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				keep(d.Name, hasExportDirective(d.Doc, d.Name.Name))
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					keep(s.Name, false)
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						keep(name, false)
+					}
+				}
+			}
+		}
+	}
+}
+
+// cgoRoots returns the keys of the functions exported to C and of cgo's generated
+// declarations
+func (a *Analyzer) cgoRoots() []string {
+	return a.linkedRoots(a.cgoSymbols, "🔌 %d symbol(s) exported to C or generated by cgo kept alive\n")
+}
+
+// importsC reports whether a file uses cgo through import "C"
+func importsC(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil && path == "C" {
+			return true
+		}
+	}
+	return false
+}
+
+// isCgoName reports whether a name is reserved for the declarations cmd/cgo generates
+func isCgoName(name string) bool {
+	return strings.HasPrefix(name, "_C") || strings.HasPrefix(name, "_cgo") || strings.HasPrefix(name, "__cgo")
+}
+
+// isCgoGenerated reports whether a file was written by cmd/cgo: the rewritten form of a
+// file importing "C", which the type checker sees instead of it, or its support file
+func isCgoGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "// Code generated by cmd/cgo;") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasExportDirective reports whether a doc comment carries //export name
+func hasExportDirective(doc *ast.CommentGroup, name string) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if fields := strings.Fields(comment.Text); len(fields) == 2 && fields[0] == "//export" && fields[1] == name {
+			return true
+		}
+	}
+	return false
+}
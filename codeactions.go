@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// CodeAction is an editor quick fix for an orphan, shaped like an LSP code action so that
+// an editor integration can offer it on the orphan's diagnostic as is
+type CodeAction struct {
+	Title       string         `json:"title"`
+	Kind        string         `json:"kind"` // always "quickfix"
+	IsPreferred bool           `json:"isPreferred,omitempty"`
+	Edit        *WorkspaceEdit `json:"edit,omitempty"`
+	Command     *EditCommand   `json:"command,omitempty"`
+}
+
+// WorkspaceEdit lists the text edits of a code action by file URI
+type WorkspaceEdit struct {
+	Changes map[string][]*TextEdit `json:"changes"`
+}
+
+// TextEdit replaces a range of a file with new text
+type TextEdit struct {
+	Range   TextRange `json:"range"`
+	NewText string    `json:"newText"`
+}
+
+// TextRange is a range of a file, end excluded
+type TextRange struct {
+	Start TextPosition `json:"start"`
+	End   TextPosition `json:"end"`
+}
+
+// TextPosition is a zero-based line and character offset in UTF-16 code units, as LSP
+// counts them
+type TextPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// EditCommand is a gorphanage command line a code action runs, Arguments excluding the
+// program name
+type EditCommand struct {
+	Title     string   `json:"title"`
+	Command   string   `json:"command"`
+	Arguments []string `json:"arguments"`
+}
+
+// attachCodeActions gives every orphan its editor quick fixes, with --code-actions:
+// deleting its declaration with the imports it leaves unused, the same edits fix applies,
+// and marking it wontfix in the baseline. Orphans sharing a spec with other names cannot
+// be deleted alone and only get the latter.
+func (a *Analyzer) attachCodeActions(orphans []*Symbol) {
+	contents := make(map[string][]byte)
+	count := 0
+	for _, orphan := range orphans {
+		if action := a.deleteAction(orphan, contents); action != nil {
+			orphan.CodeActions = append(orphan.CodeActions, action)
+		}
+		if orphan.State != StateWontfix {
+			orphan.CodeActions = append(orphan.CodeActions, a.wontfixAction(orphan))
+		}
+		count += len(orphan.CodeActions)
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🩺 Attached %d code action(s) to %d orphan(s)\n", count, len(orphans))
+	}
+}
+
+// deleteAction returns the code action deleting an orphan's declaration, or nil when it
+// cannot be deleted alone or cleanly. File contents are read once into contents.
+func (a *Analyzer) deleteAction(orphan *Symbol, contents map[string][]byte) *CodeAction {
+	declRange, ok := a.declarationRange(orphan)
+	if !ok {
+		return nil
+	}
+	content, ok := contents[declRange.File]
+	if !ok {
+		var err error
+		if content, err = os.ReadFile(declRange.File); err != nil {
+			return nil
+		}
+		contents[declRange.File] = content
+	}
+
+	// Like fix, leave alone a deletion that doesn't parse, e.g. next to a semicolon
+	edits := a.deletionEdits(declRange.File, content, []DeclRange{declRange})
+	if _, err := parser.ParseFile(token.NewFileSet(), declRange.File, applyEdits(content, edits), parser.ParseComments); err != nil {
+		return nil
+	}
+
+	// The edits come last in the file first
+	textEdits := make([]*TextEdit, len(edits))
+	for i, edit := range edits {
+		textEdits[len(edits)-1-i] = &TextEdit{
+			Range:   TextRange{textPosition(content, edit.Start), textPosition(content, edit.End)},
+			NewText: string(edit.NewText),
+		}
+	}
+
+	title := fmt.Sprintf("Delete unused %s %s", orphan.Kind, orphan.displayName())
+	if orphan.DeleteWithCare {
+		title += " (its initializer has side effects)"
+	}
+	return &CodeAction{
+		Title:       title,
+		Kind:        "quickfix",
+		IsPreferred: orphan.Confidence == ConfidenceVerified,
+		Edit:        &WorkspaceEdit{Changes: map[string][]*TextEdit{fileURI(declRange.File): textEdits}},
+	}
+}
+
+// wontfixAction returns the code action recording an orphan as kept on purpose in the
+// baseline, running gorphanage triage set from any directory
+func (a *Analyzer) wontfixAction(orphan *Symbol) *CodeAction {
+	title := fmt.Sprintf("Keep %s: mark it %s in the baseline", orphan.displayName(), StateWontfix)
+	args := []string{"triage", "set", "--project", a.config.ProjectPath}
+	if a.config.BaselineFile != "" {
+		args = append(args, "--baseline", absolutePath(a.config.BaselineFile))
+	}
+	args = append(args, StateWontfix, orphan.Package+"."+orphan.keyName())
+	return &CodeAction{
+		Title:   title,
+		Kind:    "quickfix",
+		Command: &EditCommand{Title: title, Command: "gorphanage", Arguments: args},
+	}
+}
+
+// textPosition converts a byte offset of content to an LSP position
+func textPosition(content []byte, offset int) TextPosition {
+	lineStart := bytes.LastIndexByte(content[:offset], '\n') + 1
+	character := 0
+	for _, r := range string(content[lineStart:offset]) {
+		character += utf16.RuneLen(r)
+	}
+	return TextPosition{Line: bytes.Count(content[:lineStart], []byte("\n")), Character: character}
+}
+
+// fileURI returns the file URI of an absolute path
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows drive letter
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
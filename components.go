@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultComponentsFile is the component manifest looked up in the project root when none
+// is configured
+const DefaultComponentsFile = "components.yaml"
+
+// unassignedComponent groups the symbols of files outside every component
+const unassignedComponent = "(unassigned)"
+
+// Component is a named slice of the project, such as a team's services, made of directory
+// prefixes relative to the project root
+type Component struct {
+	Name  string   `mapstructure:"name"`
+	Paths []string `mapstructure:"paths"`
+}
+
+// validate checks a component manifest entry
+func (c Component) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("component without a name")
+	}
+	if len(c.Paths) == 0 {
+		return fmt.Errorf("component %s has no paths", c.Name)
+	}
+	for _, prefix := range c.Paths {
+		if filepath.IsAbs(prefix) || strings.HasPrefix(path.Clean(filepath.ToSlash(prefix)), "..") {
+			return fmt.Errorf("component %s: path %q must be relative to the project root", c.Name, prefix)
+		}
+	}
+	return nil
+}
+
+// ComponentSummary holds the metrics of one component
+type ComponentSummary struct {
+	Component string `json:"component"`
+	Symbols   int    `json:"symbols"`
+	Reachable int    `json:"reachable"`
+	Orphans   int    `json:"orphans"`
+	Exported  int    `json:"exported"` // exported orphans
+	New       int    `json:"new"`      // orphans not triaged in the baseline
+}
+
+// componentPrefix is a directory prefix owned by a component
+type componentPrefix struct {
+	dir       string // slash-separated, relative to the project root
+	component string
+}
+
+// loadComponents reads the component manifest: the configured file, components.yaml in the
+// project root, or else the project's git submodules, one component per submodule. No
+// manifest and no submodules means no grouping.
+func (a *Analyzer) loadComponents() ([]componentPrefix, error) {
+	manifest := a.config.ComponentsFile
+	if manifest == "" {
+		manifest = filepath.Join(a.config.ProjectPath, DefaultComponentsFile)
+		if _, err := os.Stat(manifest); err != nil {
+			return a.submoduleComponents(), nil
+		}
+	}
+
+	v := viper.New()
+	v.SetConfigFile(manifest)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read component manifest: %w", err)
+	}
+	var components []Component
+	if err := v.UnmarshalKey("components", &components); err != nil {
+		return nil, fmt.Errorf("invalid component manifest %s: %w", manifest, err)
+	}
+
+	var prefixes []componentPrefix
+	for i, component := range components {
+		if err := component.validate(); err != nil {
+			return nil, fmt.Errorf("invalid component manifest entry %d: %w", i+1, err)
+		}
+		for _, dir := range component.Paths {
+			prefixes = append(prefixes, componentPrefix{dir: path.Clean(filepath.ToSlash(dir)), component: component.Name})
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🏢 Grouping by %d component(s) from %s\n", len(components), manifest)
+	}
+	return prefixes, nil
+}
+
+// submoduleComponents returns a component per git submodule of the project, named by its
+// path
+func (a *Analyzer) submoduleComponents() []componentPrefix {
+	if _, err := os.Stat(filepath.Join(a.config.ProjectPath, ".gitmodules")); err != nil {
+		return nil
+	}
+	out, err := gitOutput(a.config.ProjectPath, "config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+	if err != nil {
+		return nil
+	}
+
+	var prefixes []componentPrefix
+	for _, line := range strings.Split(out, "\n") {
+		_, dir, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		dir = path.Clean(dir)
+		prefixes = append(prefixes, componentPrefix{dir: dir, component: dir})
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(prefixes) > 0 {
+		fmt.Printf("🏢 Grouping by %d git submodule(s)\n", len(prefixes))
+	}
+	return prefixes
+}
+
+// componentOf returns the component owning a file: the one with the longest matching
+// directory prefix
+func (a *Analyzer) componentOf(prefixes []componentPrefix, file string) string {
+	rel := a.relativePath(file)
+	best, component := -1, unassignedComponent
+	for _, prefix := range prefixes {
+		if (prefix.dir == "." || rel == prefix.dir || strings.HasPrefix(rel, prefix.dir+"/")) && len(prefix.dir) > best {
+			best, component = len(prefix.dir), prefix.component
+		}
+	}
+	return component
+}
+
+// summarizeByComponent assigns every orphan its component and returns the metrics of each
+// component, in manifest order with unassigned symbols last
+func (a *Analyzer) summarizeByComponent(orphans []*Symbol) ([]ComponentSummary, error) {
+	prefixes, err := a.loadComponents()
+	if err != nil || len(prefixes) == 0 {
+		return nil, err
+	}
+
+	summaries := make(map[string]*ComponentSummary)
+	summaryFor := func(component string) *ComponentSummary {
+		if summaries[component] == nil {
+			summaries[component] = &ComponentSummary{Component: component}
+		}
+		return summaries[component]
+	}
+
+	for key, symbol := range a.symbols {
+		if !a.inShard(symbol.Package) {
+			continue
+		}
+		summary := summaryFor(a.componentOf(prefixes, symbol.File))
+		summary.Symbols++
+		if a.isReachable(key) {
+			summary.Reachable++
+		}
+	}
+	for _, orphan := range orphans {
+		orphan.Component = a.componentOf(prefixes, orphan.File)
+		summary := summaryFor(orphan.Component)
+		summary.Orphans++
+		if orphan.Exported {
+			summary.Exported++
+		}
+		if orphan.State == "" || orphan.State == StateNew {
+			summary.New++
+		}
+	}
+
+	order := make(map[string]int)
+	for _, prefix := range prefixes {
+		if _, ok := order[prefix.component]; !ok {
+			order[prefix.component] = len(order)
+		}
+	}
+	order[unassignedComponent] = len(order)
+
+	result := make([]ComponentSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return order[result[i].Component] < order[result[j].Component] })
+	return result, nil
+}
+
+// printComponents outputs the metrics of each component
+func printComponents(summaries []ComponentSummary) {
+	fmt.Printf("\n🏢 Orphaned code by component:\n")
+	for _, summary := range summaries {
+		rate := 0.0
+		if summary.Symbols > 0 {
+			rate = float64(summary.Orphans) / float64(summary.Symbols) * 100
+		}
+		fmt.Printf("  • %s: %d orphan(s) of %d symbol(s) (%.1f%%), %d exported, %d new\n",
+			summary.Component, summary.Orphans, summary.Symbols, rate, summary.Exported, summary.New)
+	}
+}
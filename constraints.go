@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/build/constraint"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ObsoleteFile is a file whose build constraints no configured platform satisfies, which
+// makes the whole file dead
+type ObsoleteFile struct {
+	File       string `json:"file"`
+	Package    string `json:"package"`
+	Constraint string `json:"constraint"` // including the constraint implied by the file name
+	Reason     string `json:"reason"`
+}
+
+// Known operating systems and architectures, as in go/build
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+		"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	unixOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "linux": true, "netbsd": true,
+		"openbsd": true, "solaris": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+		"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+		"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+		"sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// maxFreeTags bounds the custom tags enumerated when checking satisfiability; constraints
+// with more are assumed satisfiable
+const maxFreeTags = 10
+
+// validatePlatform checks a platform matrix entry of the form os/arch
+func validatePlatform(platform string) error {
+	goos, goarch, ok := strings.Cut(platform, "/")
+	if !ok || !knownOS[goos] || !knownArch[goarch] {
+		return fmt.Errorf("invalid platform %q (expected os/arch, e.g. linux/amd64)", platform)
+	}
+	return nil
+}
+
+// validateBuildTag checks a build tag set with --tags or a build matrix entry
+func validateBuildTag(tag string) error {
+	if tag == "" || strings.TrimFunc(tag, func(r rune) bool {
+		return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) != "" {
+		return fmt.Errorf("invalid build tag %q (expected letters, digits, underscores and dots)", tag)
+	}
+	return nil
+}
+
+// findObsoleteFiles reports the files excluded from the build whose constraints can never
+// be satisfied: on none of the configured platforms (every known one by default), with
+// any Go version allowed by the module's go directive, under any set of custom tags
+func (a *Analyzer) findObsoleteFiles() []*ObsoleteFile {
+	var obsolete []*ObsoleteFile
+	seen := make(map[string]bool)
+
+	for _, pkg := range a.packages {
+		if !a.inShard(pkg.PkgPath) {
+			continue
+		}
+		minGo := moduleGoMinor(pkg)
+		for _, file := range pkg.IgnoredFiles {
+			if seen[file] || !strings.HasSuffix(file, ".go") {
+				continue
+			}
+			seen[file] = true
+
+			expr, err := fileConstraint(file)
+			if err != nil {
+				if a.config.Verbose && !a.config.OutputJSON {
+					fmt.Printf("⚠️  Skipping build constraints of %s: %v\n", file, err)
+				}
+				continue
+			}
+
+			if reason, ok := a.unsatisfiable(expr, minGo); ok {
+				obsolete = append(obsolete, &ObsoleteFile{
+					File:       file,
+					Package:    pkg.PkgPath,
+					Constraint: expr.String(),
+					Reason:     reason,
+				})
+			}
+		}
+	}
+
+	sort.Slice(obsolete, func(i, j int) bool { return obsolete[i].File < obsolete[j].File })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(obsolete) > 0 {
+		fmt.Printf("🚧 %d file(s) have build constraints that can never be satisfied\n", len(obsolete))
+	}
+
+	return obsolete
+}
+
+// fileConstraint returns the build constraint of a file, combined with the constraint
+// implied by an _os, _arch or _os_arch file name suffix
+func fileConstraint(path string) (constraint.Expr, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	var goBuild constraint.Expr
+	var plusBuild []constraint.Expr
+	inBlock := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case inBlock:
+			inBlock = !strings.Contains(line, "*/")
+			continue
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "/*"):
+			inBlock = !strings.Contains(line, "*/")
+			continue
+		case !strings.HasPrefix(line, "//"):
+			// Constraints must appear before the package clause
+			return combineConstraints(fileNameConstraint(path), goBuild, plusBuild), nil
+		}
+
+		if constraint.IsGoBuild(line) || constraint.IsPlusBuild(line) {
+			expr, err := constraint.Parse(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid build constraint %q: %w", line, err)
+			}
+			if constraint.IsGoBuild(line) {
+				goBuild = expr
+			} else {
+				plusBuild = append(plusBuild, expr)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return combineConstraints(fileNameConstraint(path), goBuild, plusBuild), nil
+}
+
+// combineConstraints ands the file name constraint with the //go:build line, or with the
+// legacy // +build lines when there is no //go:build line
+func combineConstraints(name, goBuild constraint.Expr, plusBuild []constraint.Expr) constraint.Expr {
+	expr := goBuild
+	if expr == nil {
+		for _, line := range plusBuild {
+			if expr == nil {
+				expr = line
+			} else {
+				expr = &constraint.AndExpr{X: expr, Y: line}
+			}
+		}
+	}
+
+	switch {
+	case name == nil:
+		return expr
+	case expr == nil:
+		return name
+	default:
+		return &constraint.AndExpr{X: name, Y: expr}
+	}
+}
+
+// fileNameConstraint returns the constraint implied by a file name, as go/build applies it
+func fileNameConstraint(path string) constraint.Expr {
+	name, _, _ := strings.Cut(filepath.Base(path), ".")
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+
+	parts := strings.Split(name[i:], "_")
+	if n := len(parts); n > 0 && parts[n-1] == "test" {
+		parts = parts[:n-1]
+	}
+
+	n := len(parts)
+	switch {
+	case n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]]:
+		return &constraint.AndExpr{X: &constraint.TagExpr{Tag: parts[n-2]}, Y: &constraint.TagExpr{Tag: parts[n-1]}}
+	case n >= 1 && knownOS[parts[n-1]]:
+		return &constraint.TagExpr{Tag: parts[n-1]}
+	case n >= 1 && knownArch[parts[n-1]]:
+		return &constraint.TagExpr{Tag: parts[n-1]}
+	}
+	return nil
+}
+
+// unsatisfiable reports whether a constraint is false on every configured platform for
+// every Go version from minGo on and every assignment of custom tags, and why
+func (a *Analyzer) unsatisfiable(expr constraint.Expr, minGo int) (string, bool) {
+	if expr == nil {
+		return "", false
+	}
+
+	if satisfiable(expr, a.platforms(), minGo) {
+		return "", false
+	}
+	// Without the version floor the constraint could hold: it targets older Go releases
+	if minGo > 0 && satisfiable(expr, a.platforms(), 0) {
+		return fmt.Sprintf("requires a Go release older than the module minimum go1.%d", minGo), true
+	}
+	if len(a.config.Platforms) > 0 {
+		return "not satisfied on any configured platform", true
+	}
+	return "mutually exclusive constraints", true
+}
+
+// platforms returns the configured platform matrix, or every known os/arch pair
+func (a *Analyzer) platforms() []string {
+	if len(a.config.Platforms) > 0 {
+		return a.config.Platforms
+	}
+
+	var platforms []string
+	for goos := range knownOS {
+		for goarch := range knownArch {
+			platforms = append(platforms, goos+"/"+goarch)
+		}
+	}
+	return platforms
+}
+
+// satisfiable reports whether some platform, Go release from go1.minGo on and assignment
+// of the remaining tags satisfies the constraint
+func satisfiable(expr constraint.Expr, platforms []string, minGo int) bool {
+	var free []string
+	seen := make(map[string]bool)
+	expr.Eval(func(tag string) bool {
+		if !seen[tag] && !knownOS[tag] && !knownArch[tag] && tag != "unix" && !(minGo > 0 && isReleaseTagUpTo(tag, minGo)) {
+			seen[tag] = true
+			free = append(free, tag)
+		}
+		return false
+	})
+	if len(free) > maxFreeTags {
+		return true
+	}
+
+	for _, platform := range platforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		for assignment := 0; assignment < 1<<len(free); assignment++ {
+			ok := expr.Eval(func(tag string) bool {
+				for i, name := range free {
+					if name == tag {
+						return assignment&(1<<i) != 0
+					}
+				}
+				return matchPlatformTag(tag, goos, goarch) || isReleaseTagUpTo(tag, minGo)
+			})
+			if ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPlatformTag reports whether a tag is satisfied by a platform, including the tags
+// implied by it, as go/build does
+func matchPlatformTag(tag, goos, goarch string) bool {
+	switch {
+	case tag == goos || tag == goarch:
+		return true
+	case tag == "unix":
+		return unixOS[goos]
+	case tag == "linux":
+		return goos == "android"
+	case tag == "solaris":
+		return goos == "illumos"
+	case tag == "darwin":
+		return goos == "ios"
+	}
+	return false
+}
+
+// isReleaseTagUpTo reports whether tag is a go1.N release tag with N <= minor
+func isReleaseTagUpTo(tag string, minor int) bool {
+	n, ok := releaseTagMinor(tag)
+	return ok && n <= minor
+}
+
+// releaseTagMinor parses the minor version of a go1.N release tag
+func releaseTagMinor(tag string) (int, bool) {
+	rest, ok := strings.CutPrefix(tag, "go1.")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	return n, err == nil
+}
+
+// moduleGoMinor returns the minor Go version required by a package's module, or 0
+func moduleGoMinor(pkg *packages.Package) int {
+	if pkg.Module == nil || pkg.Module.GoVersion == "" {
+		return 0
+	}
+	version := strings.TrimPrefix(pkg.Module.GoVersion, "go")
+	minor, _, _ := strings.Cut(strings.TrimPrefix(version, "1."), ".")
+	n, err := strconv.Atoi(minor)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/tools/cover"
+)
+
+// Coverage annotations for orphaned symbols
+const (
+	CoverageCovered   = "covered"   // executed at least once - suspicious, maybe reached via reflection
+	CoverageUncovered = "uncovered" // never executed - safer to delete
+)
+
+// Confidence levels for orphan findings
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// annotateCoverage marks orphans with their historical coverage from a Go coverage profile
+func (a *Analyzer) annotateCoverage(orphans []*Symbol) error {
+	profiles, err := cover.ParseProfiles(a.config.CoverProfile)
+	if err != nil {
+		return fmt.Errorf("failed to parse coverage profile: %w", err)
+	}
+
+	// Coverage profiles name files by import path, e.g. example.com/app/pkg/file.go
+	byFile := make(map[string]*cover.Profile, len(profiles))
+	for _, profile := range profiles {
+		byFile[profile.FileName] = profile
+	}
+
+	for _, orphan := range orphans {
+		profile, ok := byFile[path.Join(orphan.Package, filepath.Base(orphan.File))]
+		if !ok {
+			continue
+		}
+
+		orphan.Coverage = CoverageUncovered
+		orphan.Confidence = ConfidenceHigh
+		for _, block := range profile.Blocks {
+			if block.Count > 0 && block.StartLine <= orphan.End.Line && block.EndLine >= orphan.Start.Line {
+				orphan.Coverage = CoverageCovered
+				orphan.Confidence = ConfidenceLow
+				break
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🧮 Cross-referenced orphans with %d coverage profile file(s)\n", len(profiles))
+	}
+
+	return nil
+}
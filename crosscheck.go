@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ImportedFinding is an unused-code finding of another tool, such as staticcheck's U1000
+type ImportedFinding struct {
+	Tool    string `json:"tool"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+	Symbol  string `json:"symbol,omitempty"` // key of the symbol it designates
+
+	// ReachableVia is the chain keeping a symbol the tool reports alive, from its root
+	ReachableVia []string `json:"reachable_via,omitempty"`
+}
+
+// ToolComparison reconciles the findings imported with --import-findings with the
+// orphans: a symbol both report is more certainly dead, and a disagreement points at a
+// blind spot of either tool.
+type ToolComparison struct {
+	Source         string             `json:"source"`
+	Agreed         []string           `json:"agreed"`                    // symbol keys both report
+	ToolOnly       []*ImportedFinding `json:"tool_only,omitempty"`       // reported by the tool, reachable for gorphanage
+	GorphanageOnly []string           `json:"gorphanage_only,omitempty"` // unexported orphans the tool doesn't report
+	Unmatched      []*ImportedFinding `json:"unmatched,omitempty"`       // findings at no analyzed symbol
+}
+
+// unusedChecks are the staticcheck check and golangci-lint linters reporting unused code
+var unusedChecks = map[string]bool{
+	"U1000":    true,
+	"unused":   true,
+	"deadcode": true,
+	"varcheck": true,
+}
+
+// readImportedFindings reads the unused-code findings of staticcheck -f json (one JSON
+// object per line) or golangci-lint --out-format json, ignoring any other check
+func readImportedFindings(path string) ([]*ImportedFinding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings: %w", err)
+	}
+
+	var golangci struct {
+		Issues []struct {
+			FromLinter string `json:"FromLinter"`
+			Text       string `json:"Text"`
+			Pos        struct {
+				Filename string `json:"Filename"`
+				Line     int    `json:"Line"`
+			} `json:"Pos"`
+		} `json:"Issues"`
+	}
+	if err := json.Unmarshal(data, &golangci); err == nil && golangci.Issues != nil {
+		var findings []*ImportedFinding
+		for _, issue := range golangci.Issues {
+			if unusedChecks[issue.FromLinter] {
+				findings = append(findings, &ImportedFinding{Tool: "golangci-lint", File: issue.Pos.Filename, Line: issue.Pos.Line, Message: issue.Text})
+			}
+		}
+		return findings, nil
+	}
+
+	var findings []*ImportedFinding
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var problem struct {
+			Code     string `json:"code"`
+			Message  string `json:"message"`
+			Location struct {
+				File string `json:"file"`
+				Line int    `json:"line"`
+			} `json:"location"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &problem); err != nil {
+			return nil, fmt.Errorf("invalid findings file %s:%d (expected staticcheck -f json or golangci-lint JSON output): %w", path, line, err)
+		}
+		if unusedChecks[problem.Code] {
+			findings = append(findings, &ImportedFinding{Tool: "staticcheck", File: problem.Location.File, Line: problem.Location.Line, Message: problem.Message})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read findings: %w", err)
+	}
+	return findings, nil
+}
+
+// reportedName extracts the name of the symbol an unused finding reports, e.g. "m" from
+// "func (*T).m is unused" or "foo" from "func `foo` is unused"
+func reportedName(message string) string {
+	fields := strings.Fields(message)
+	if len(fields) < 2 {
+		return ""
+	}
+	name := strings.Trim(fields[1], "`")
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// compareFindings reconciles the findings of --import-findings with the orphans. A finding
+// designates the symbol of its file whose declaration spans its line, with the name its
+// message gives. Orphans both report get high confidence. Other tools leave the exported
+// API alone, so only unexported orphans count as reported by gorphanage alone.
+func (a *Analyzer) compareFindings(orphans []*Symbol) (*ToolComparison, error) {
+	findings, err := readImportedFindings(a.config.ImportFindings)
+	if err != nil {
+		return nil, err
+	}
+
+	byFile := make(map[string][]string)
+	for key, symbol := range a.symbols {
+		file := normalizePath(symbol.File)
+		byFile[file] = append(byFile[file], key)
+	}
+	orphanKeys := make(map[string]*Symbol, len(orphans))
+	for _, orphan := range orphans {
+		orphanKeys[a.symbolKey(orphan)] = orphan
+	}
+
+	comparison := &ToolComparison{Source: a.config.ImportFindings, Agreed: []string{}}
+	reported := make(map[string]bool)
+	for _, finding := range findings {
+		file := finding.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(a.config.ProjectPath, file)
+		}
+		name := reportedName(finding.Message)
+		for _, key := range byFile[normalizePath(file)] {
+			symbol := a.symbols[key]
+			if symbol.Name == name && symbol.Start.Line <= finding.Line && finding.Line <= symbol.End.Line {
+				finding.Symbol = key
+				break
+			}
+		}
+		finding.File = a.relativePath(file)
+
+		if finding.Symbol == "" {
+			comparison.Unmatched = append(comparison.Unmatched, finding)
+			continue
+		}
+		if reported[finding.Symbol] || !a.inShard(a.symbols[finding.Symbol].Package) {
+			continue
+		}
+		reported[finding.Symbol] = true
+
+		if orphan, ok := orphanKeys[finding.Symbol]; ok {
+			comparison.Agreed = append(comparison.Agreed, finding.Symbol)
+			orphan.ConfirmedBy = finding.Tool
+			if orphan.Confidence == ConfidenceMedium {
+				orphan.Confidence = ConfidenceHigh
+			}
+		} else {
+			finding.ReachableVia = a.reachabilityPath(finding.Symbol)
+			comparison.ToolOnly = append(comparison.ToolOnly, finding)
+		}
+	}
+	for key, orphan := range orphanKeys {
+		if !reported[key] && !orphan.Exported {
+			comparison.GorphanageOnly = append(comparison.GorphanageOnly, key)
+		}
+	}
+	sort.Strings(comparison.Agreed)
+	sort.Strings(comparison.GorphanageOnly)
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🤝 Cross-checked %d imported finding(s): %d agreed, %d reachable for gorphanage, %d unmatched\n",
+			len(findings), len(comparison.Agreed), len(comparison.ToolOnly), len(comparison.Unmatched))
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,583 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/tools/go/packages"
+)
+
+// Daemon timings
+const (
+	daemonStartTimeout = 10 * time.Second
+	daemonDialTimeout  = time.Second
+)
+
+var (
+	daemonJSON        bool
+	daemonIdleTimeout time.Duration
+)
+
+// packageCache keeps the packages of the last load, so that a daemon only loads the project
+// again when its files or the load options change
+type packageCache struct {
+	key      string // load options
+	stamp    string // sizes and modification times of the project's files
+	fileSet  *token.FileSet
+	packages []*packages.Package
+	hits     int
+}
+
+// load returns the cached packages when they are still current, or loads them again
+func (c *packageCache) load(a *Analyzer) ([]*packages.Package, error) {
+	key := fmt.Sprintf("tests=%t replaced=%t testdata=%t deps=%t tags=%s", a.config.IncludeTests, a.config.IncludeReplaced, a.config.IncludeTestdata, a.usesCallGraph(), strings.Join(a.config.Tags, ","))
+	stamp, err := projectStamp(a.config.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.packages != nil && c.key == key && c.stamp == stamp {
+		c.hits++
+		a.fileSet = c.fileSet
+		return c.packages, nil
+	}
+
+	pkgs, err := a.loadModules(nil)
+	if err != nil {
+		return nil, err
+	}
+	c.key, c.stamp, c.fileSet, c.packages = key, stamp, a.fileSet, pkgs
+	return pkgs, nil
+}
+
+// projectStamp summarizes the size and modification time of every Go source and module
+// file of a project. Hidden directories are skipped like the go command does.
+func projectStamp(projectPath string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != projectPath && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") && name != "go.mod" && name != "go.sum" && name != "go.work" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan project files: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// daemonSocket returns the path of the socket a project's daemon listens on
+func daemonSocket(projectPath string) string {
+	sum := sha256.Sum256([]byte(projectPath))
+	return filepath.Join(os.TempDir(), "gorphanage-"+hex.EncodeToString(sum[:6])+".sock")
+}
+
+// daemonLog returns the path of the log a project's daemon writes to
+func daemonLog(projectPath string) string {
+	return strings.TrimSuffix(daemonSocket(projectPath), ".sock") + ".log"
+}
+
+// daemonRequest is one line sent to the daemon
+type daemonRequest struct {
+	Op     string  `json:"op"` // analyze, status or stop
+	Config *Config `json:"config,omitempty"`
+}
+
+// daemonResponse is the daemon's one-line answer
+type daemonResponse struct {
+	Result    *AnalysisResult `json:"result,omitempty"`
+	Status    *DaemonStatus   `json:"status,omitempty"`
+	LoadError *LoadError      `json:"load_error,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// DaemonStatus describes a running daemon
+type DaemonStatus struct {
+	Project   string    `json:"project"`
+	PID       int       `json:"pid"`
+	Started   time.Time `json:"started"`
+	Analyses  int       `json:"analyses"`
+	CacheHits int       `json:"cache_hits"` // analyses that reused the loaded packages
+	Packages  int       `json:"packages"`   // packages currently loaded
+	Socket    string    `json:"socket"`
+}
+
+// daemon serves analyses of one project from packages kept loaded between requests
+type daemon struct {
+	projectPath string
+	listener    net.Listener
+	cache       packageCache
+	started     time.Time
+	analyses    int
+	activity    chan struct{}
+	mu          sync.Mutex // one analysis at a time; they share the cache
+}
+
+// serveDaemon listens on the project's socket until stopped, idle for the timeout or
+// interrupted. The project is loaded right away so that the first request is fast too.
+func serveDaemon(config *Config, idleTimeout time.Duration) error {
+	socket := daemonSocket(config.ProjectPath)
+	if _, err := callDaemon(config.ProjectPath, &daemonRequest{Op: "status"}); err == nil {
+		return fmt.Errorf("a daemon is already serving %s", config.ProjectPath)
+	}
+	os.Remove(socket) // left behind by a daemon that did not exit cleanly
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	defer os.Remove(socket)
+
+	d := &daemon{
+		projectPath: config.ProjectPath,
+		listener:    listener,
+		started:     time.Now(),
+		activity:    make(chan struct{}, 1),
+	}
+	fmt.Printf("👻 Serving %s on %s (pid %d)\n", config.ProjectPath, socket, os.Getpid())
+
+	go func() {
+		start := time.Now()
+		if _, err := d.analyze(config); err != nil {
+			fmt.Printf("⚠️  Warm-up analysis failed: %v\n", err)
+			return
+		}
+		fmt.Printf("🔥 Warmed up in %s\n", time.Since(start).Round(time.Millisecond))
+	}()
+
+	go func() {
+		interrupted := make(chan os.Signal, 1)
+		signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+		idle := time.NewTimer(idleTimeout)
+		for {
+			select {
+			case <-d.activity:
+				idle.Reset(idleTimeout)
+				continue
+			case <-idle.C:
+				fmt.Printf("💤 Idle for %s, exiting\n", idleTimeout)
+			case <-interrupted:
+				fmt.Println("🛑 Interrupted, exiting")
+			}
+			listener.Close()
+			return
+		}
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go d.handle(conn)
+	}
+}
+
+// handle answers a single request
+func (d *daemon) handle(conn net.Conn) {
+	defer conn.Close()
+	select {
+	case d.activity <- struct{}{}:
+	default:
+	}
+
+	var request daemonRequest
+	response := &daemonResponse{}
+	if err := json.NewDecoder(conn).Decode(&request); err != nil {
+		response.Error = fmt.Sprintf("invalid request: %v", err)
+		json.NewEncoder(conn).Encode(response)
+		return
+	}
+
+	switch request.Op {
+	case "analyze":
+		result, err := d.analyze(request.Config)
+		var loadErr *LoadError
+		switch {
+		case errors.As(err, &loadErr):
+			response.LoadError = loadErr
+		case err != nil:
+			response.Error = err.Error()
+		default:
+			response.Result = result
+		}
+	case "status":
+		response.Status = d.status()
+	case "stop":
+		response.Status = d.status()
+		defer d.listener.Close()
+		fmt.Println("🛑 Stop requested, exiting")
+	default:
+		response.Error = fmt.Sprintf("unknown operation %q", request.Op)
+	}
+
+	json.NewEncoder(conn).Encode(response)
+}
+
+// analyze runs an analysis on the cached packages. Progress output stays in the client's
+// hands: the daemon analyzes quietly.
+func (d *daemon) analyze(config *Config) (*AnalysisResult, error) {
+	if config == nil || config.ProjectPath != d.projectPath {
+		return nil, fmt.Errorf("this daemon serves %s", d.projectPath)
+	}
+
+	analyzer := New(WithConfig(config))
+	analyzer.config.Verbose = false
+	analyzer.config.OutputJSON = true
+	analyzer.cache = &d.cache
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.analyses++
+	return analyzer.Analyze()
+}
+
+// status reports the daemon's state
+func (d *daemon) status() *DaemonStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &DaemonStatus{
+		Project:   d.projectPath,
+		PID:       os.Getpid(),
+		Started:   d.started,
+		Analyses:  d.analyses,
+		CacheHits: d.cache.hits,
+		Packages:  len(d.cache.packages),
+		Socket:    daemonSocket(d.projectPath),
+	}
+}
+
+// callDaemon sends a request to the daemon of a project and waits for its answer
+func callDaemon(projectPath string, request *daemonRequest) (*daemonResponse, error) {
+	conn, err := net.DialTimeout("unix", daemonSocket(projectPath), daemonDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("no daemon is serving %s", projectPath)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return nil, fmt.Errorf("failed to send request to daemon: %w", err)
+	}
+	var response daemonResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to read daemon response: %w", err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("daemon: %s", response.Error)
+	}
+	return &response, nil
+}
+
+// startDaemon starts a daemon for a project in the background and waits until it accepts
+// requests. The daemon reads the same config file and profile as this invocation.
+func startDaemon(projectPath string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the gorphanage executable: %w", err)
+	}
+
+	args := []string{"daemon", "serve", projectPath}
+	if configFile != "" {
+		args = append(args, "--config", configFile)
+	}
+	if profile := viper.GetString("profile"); profile != "" {
+		args = append(args, "--profile", profile)
+	}
+
+	logFile, err := os.OpenFile(daemonLog(projectPath), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open daemon log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start daemon: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.After(daemonStartTimeout)
+	for {
+		if _, err := callDaemon(projectPath, &daemonRequest{Op: "status"}); err == nil {
+			return nil
+		}
+		select {
+		case <-exited:
+			return fmt.Errorf("daemon exited during startup; see %s", daemonLog(projectPath))
+		case <-deadline:
+			return fmt.Errorf("daemon did not start within %s; see %s", daemonStartTimeout, daemonLog(projectPath))
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// daemonIncompatible names the first requested output that needs the analyzer's state in
+// this process, which a daemon cannot provide, or returns ""
+func daemonIncompatible(config *Config) string {
+	switch {
+	case config.ExportDB != "":
+		return "--export-db"
+	case config.DumpGraph != "":
+		return "--dump-graph"
+	case config.ListReachable != "":
+		return "--list-reachable"
+	case config.WriteTodos:
+		return "--write-todos"
+	case config.Stream && !config.OutputJSON:
+		return "--stream"
+	case len(config.BuildMatrix) > 0:
+		return "--build-matrix"
+	case config.Recursive:
+		return "--recursive"
+	}
+	return ""
+}
+
+// runDaemonAnalysis runs the analysis of the root command in the project's daemon and
+// reports it like a local analysis
+func runDaemonAnalysis(config *Config) error {
+	result, err := analyzeWithDaemon(config)
+	if err != nil {
+		return err
+	}
+	if config.FailOnNew {
+		onlyNewFindings(result)
+	}
+
+	if config.OutputJSON {
+		if err := outputJSON(truncateResult(result, config.MaxFindings)); err != nil {
+			return err
+		}
+	} else {
+		New(WithConfig(config)).PrintResults(result)
+	}
+
+	printSummaryLine(os.Stderr, result)
+
+	if err := postRun(config, result); err != nil {
+		return err
+	}
+
+	return checkFailOn(config.FailOn, result)
+}
+
+// analyzeWithDaemon runs an analysis in the project's daemon, starting one if none is
+// running yet. Files named relative to the working directory are resolved first, since
+// the daemon may run elsewhere.
+func analyzeWithDaemon(config *Config) (*AnalysisResult, error) {
+	remote := *config
+	remote.CoverProfile = absolutePath(config.CoverProfile)
+	remote.BaselineFile = absolutePath(config.BaselineFile)
+	remote.ComponentsFile = absolutePath(config.ComponentsFile)
+	remote.PprofProfiles = make([]string, len(config.PprofProfiles))
+	for i, path := range config.PprofProfiles {
+		remote.PprofProfiles[i] = absolutePath(path)
+	}
+
+	if _, err := callDaemon(config.ProjectPath, &daemonRequest{Op: "status"}); err != nil {
+		if config.Verbose && !config.OutputJSON {
+			fmt.Printf("👻 Starting analysis daemon for %s\n", config.ProjectPath)
+		}
+		if err := startDaemon(config.ProjectPath); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	response, err := callDaemon(config.ProjectPath, &daemonRequest{Op: "analyze", Config: &remote})
+	if err != nil {
+		return nil, err
+	}
+	if response.LoadError != nil {
+		if config.OutputJSON {
+			if err := outputJSONError(response.LoadError); err != nil {
+				return nil, err
+			}
+		}
+		return nil, response.LoadError
+	}
+
+	if config.Verbose && !config.OutputJSON {
+		fmt.Printf("⚡ Analyzed by the daemon in %s\n", time.Since(start).Round(time.Millisecond))
+	}
+	return response.Result, nil
+}
+
+// printDaemonStatus outputs a daemon's state in human-readable format
+func printDaemonStatus(status *DaemonStatus) {
+	fmt.Printf("👻 Daemon serving %s\n", status.Project)
+	fmt.Printf("  • PID: %d\n", status.PID)
+	fmt.Printf("  • Socket: %s\n", status.Socket)
+	fmt.Printf("  • Running since: %s\n", status.Started.Local().Format(time.DateTime))
+	fmt.Printf("  • Loaded packages: %d\n", status.Packages)
+	fmt.Printf("  • Analyses: %d (%d reused the loaded packages)\n", status.Analyses, status.CacheHits)
+}
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep a project loaded in the background for fast repeated analyses",
+	Long: `Runs a background daemon that keeps a project's loaded packages in memory. Analyses
+run with --daemon (or GORPHANAGE_DAEMON=true) attach to it over a local socket and skip
+loading, which dominates the analysis time; the daemon loads again only when a Go file,
+go.mod or go.sum changed. A daemon is started automatically by the first --daemon
+analysis and exits after being idle for a while.`,
+	Example: `  gorphanage daemon start .
+  gorphanage --daemon .
+  gorphanage daemon status .
+  gorphanage daemon stop .`,
+}
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start [project-path]",
+	Short: "Start the daemon for a project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		projectPath, err := daemonProjectPath(args)
+		if err != nil {
+			return err
+		}
+
+		if response, err := callDaemon(projectPath, &daemonRequest{Op: "status"}); err == nil {
+			fmt.Printf("👻 A daemon is already serving %s (pid %d)\n", projectPath, response.Status.PID)
+			return nil
+		}
+		if err := startDaemon(projectPath); err != nil {
+			return err
+		}
+		fmt.Printf("👻 Started the daemon for %s; it logs to %s\n", projectPath, daemonLog(projectPath))
+		return nil
+	},
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop [project-path]",
+	Short: "Stop the daemon of a project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		projectPath, err := daemonProjectPath(args)
+		if err != nil {
+			return err
+		}
+
+		response, err := callDaemon(projectPath, &daemonRequest{Op: "stop"})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🛑 Stopped the daemon for %s after %d analyses\n", projectPath, response.Status.Analyses)
+		return nil
+	},
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status [project-path]",
+	Short: "Show the daemon of a project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		projectPath, err := daemonProjectPath(args)
+		if err != nil {
+			return err
+		}
+
+		response, err := callDaemon(projectPath, &daemonRequest{Op: "status"})
+		if err != nil {
+			return err
+		}
+		if daemonJSON {
+			return printJSON(response.Status)
+		}
+		printDaemonStatus(response.Status)
+		return nil
+	},
+}
+
+var daemonServeCmd = &cobra.Command{
+	Use:    "serve [project-path]",
+	Short:  "Run the daemon in the foreground",
+	Args:   cobra.MaximumNArgs(1),
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		projectPath, err := daemonProjectPath(args)
+		if err != nil {
+			return err
+		}
+		config, err := configFromViper(projectPath)
+		if err != nil {
+			return err
+		}
+		return serveDaemon(config, daemonIdleTimeout)
+	},
+}
+
+// absolutePath resolves a file named relative to the working directory; empty stays empty
+func absolutePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// daemonProjectPath resolves the project a daemon command is about, the current directory
+// by default, the same way analyses resolve it
+func daemonProjectPath(args []string) (string, error) {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project path: %w", err)
+	}
+	return normalizePath(absPath), nil
+}
+
+func init() {
+	daemonStatusCmd.Flags().BoolVar(&daemonJSON, "json", false, "output the status in JSON format")
+	daemonServeCmd.Flags().DurationVar(&daemonIdleTimeout, "idle-timeout", 30*time.Minute, "exit after receiving no request for this long")
+	daemonCmd.AddCommand(daemonStartCmd, daemonStopCmd, daemonStatusCmd, daemonServeCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
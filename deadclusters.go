@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DeadCluster is a group of orphans that goes away in cascade: the root is referenced by no
+// other orphan, and deleting it leaves the unlocked symbols referenced by nothing
+type DeadCluster struct {
+	Root    []string   `json:"root"`              // symbols to delete first, mutually recursive when several
+	Unlocks []string   `json:"unlocks,omitempty"` // symbols unreferenced once the root is gone, in deletion order
+	Cycles  [][]string `json:"cycles,omitempty"`  // mutually recursive groups among the unlocked symbols
+}
+
+// size is the number of symbols a cluster deletes
+func (c *DeadCluster) size() int {
+	return len(c.Root) + len(c.Unlocks)
+}
+
+// deadClusters groups the orphans into the strongly connected components of the graph
+// they form, and follows the cascade from every component no other orphan references.
+// Plain orphans, alone and unlocking nothing, are left out. An orphan referenced from
+// several roots is only unlocked once all of them are deleted, and belongs to no cluster.
+func (a *Analyzer) deadClusters(orphans []*Symbol) []*DeadCluster {
+	orphaned := make(map[int32]bool, len(orphans))
+	for _, orphan := range orphans {
+		orphaned[a.graph.ids[a.symbolKey(orphan)]] = true
+	}
+	successors := func(id int32) []int32 {
+		var targets []int32
+		for _, target := range a.graph.successors(id) {
+			if orphaned[target] && target != id {
+				targets = append(targets, target)
+			}
+		}
+		return targets
+	}
+
+	// Tarjan's algorithm, components numbered in reverse topological order
+	index := make(map[int32]int, len(orphaned))
+	lowlink := make(map[int32]int, len(orphaned))
+	onStack := make(map[int32]bool)
+	component := make(map[int32]int, len(orphaned))
+	var stack []int32
+	var components [][]int32
+	var connect func(id int32)
+	connect = func(id int32) {
+		index[id] = len(index)
+		lowlink[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, target := range successors(id) {
+			if _, visited := index[target]; !visited {
+				connect(target)
+				lowlink[id] = min(lowlink[id], lowlink[target])
+			} else if onStack[target] {
+				lowlink[id] = min(lowlink[id], index[target])
+			}
+		}
+		if lowlink[id] != index[id] {
+			return
+		}
+		var members []int32
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component[member] = len(components)
+			members = append(members, member)
+			if member == id {
+				break
+			}
+		}
+		components = append(components, members)
+	}
+	ids := make([]int32, 0, len(orphaned))
+	for id := range orphaned {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		if _, visited := index[id]; !visited {
+			connect(id)
+		}
+	}
+
+	// The condensed graph: edges and incoming edge counts between components
+	next := make([][]int, len(components))
+	incoming := make([]int, len(components))
+	for c, members := range components {
+		seen := make(map[int]bool)
+		for _, id := range members {
+			for _, target := range successors(id) {
+				if t := component[target]; t != c && !seen[t] {
+					seen[t] = true
+					next[c] = append(next[c], t)
+					incoming[t]++
+				}
+			}
+		}
+	}
+
+	keys := func(members []int32) []string {
+		names := make([]string, 0, len(members))
+		for _, id := range members {
+			names = append(names, a.graph.keys[id])
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	var clusters []*DeadCluster
+	for root, members := range components {
+		if incoming[root] > 0 {
+			continue
+		}
+		cluster := &DeadCluster{Root: keys(members)}
+
+		// Delete the root, then every component left without a referrer
+		remaining := make(map[int]int)
+		queue := []int{root}
+		for len(queue) > 0 {
+			c := queue[0]
+			queue = queue[1:]
+			for _, t := range next[c] {
+				if _, ok := remaining[t]; !ok {
+					remaining[t] = incoming[t]
+				}
+				remaining[t]--
+				if remaining[t] > 0 {
+					continue
+				}
+				unlocked := keys(components[t])
+				cluster.Unlocks = append(cluster.Unlocks, unlocked...)
+				if len(unlocked) > 1 {
+					cluster.Cycles = append(cluster.Cycles, unlocked)
+				}
+				queue = append(queue, t)
+			}
+		}
+
+		if cluster.size() > 1 {
+			clusters = append(clusters, cluster)
+		}
+	}
+	sortDeadClusters(clusters)
+
+	if a.config.Verbose && !a.config.OutputJSON && len(clusters) > 0 {
+		fmt.Printf("🔁 %d dead cluster(s) go away in cascade from a single root\n", len(clusters))
+	}
+
+	return clusters
+}
+
+// sortDeadClusters orders clusters by the number of symbols they delete, largest first
+func sortDeadClusters(clusters []*DeadCluster) {
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].size() != clusters[j].size() {
+			return clusters[i].size() > clusters[j].size()
+		}
+		return clusters[i].Root[0] < clusters[j].Root[0]
+	})
+}
+
+// printDeadClusters lists the largest dead clusters, each with the root to delete first
+func (a *Analyzer) printDeadClusters(result *AnalysisResult) {
+	if len(result.DeadClusters) == 0 {
+		return
+	}
+
+	describe := func(keys []string) string {
+		names := make([]string, 0, len(keys))
+		for _, key := range keys {
+			names = append(names, result.describeKey(key))
+		}
+		return strings.Join(names, ", ")
+	}
+
+	fmt.Printf("\n🔁 Dead clusters (delete the root, the rest is left unreferenced):\n")
+	for i, cluster := range result.DeadClusters {
+		if i == maxPrintedClusters {
+			fmt.Printf("  … %d more cluster(s)\n", len(result.DeadClusters)-i)
+			break
+		}
+		note := ""
+		if len(cluster.Root) > 1 {
+			note = " (mutually recursive)"
+		}
+		fmt.Printf("  • root%s: %s\n", note, describe(cluster.Root))
+		if len(cluster.Unlocks) > 0 {
+			fmt.Printf("    unlocks %d symbol(s): %s\n", len(cluster.Unlocks), describe(cluster.Unlocks))
+		}
+		for _, cycle := range cluster.Cycles {
+			fmt.Printf("    🔄 cycle: %s\n", describe(cycle))
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// DeclRange is the byte range of a symbol's declaration in its file
+type DeclRange struct {
+	File  string
+	Start int
+	End   int
+}
+
+// declarationRange locates the source range that must be removed to delete a symbol,
+// including its doc comment. Symbols sharing a spec with other names (var a, b = ...)
+// cannot be removed on their own and report false.
+func (a *Analyzer) declarationRange(symbol *Symbol) (DeclRange, bool) {
+	file := a.syntaxForFile(symbol.File)
+	if file == nil {
+		return DeclRange{}, false
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			// Function symbols are positioned at the func keyword
+			if d.Name != nil && d.Name.Name == symbol.Name && a.fileSet.Position(d.Pos()).Offset == symbol.Position.Offset {
+				return a.rangeWithDoc(symbol.File, d.Doc, d.Pos(), d.End()), true
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if !a.specDeclares(spec, symbol) {
+					continue
+				}
+				if !a.specIsSingleName(spec) {
+					return DeclRange{}, false
+				}
+				// A lone spec takes the whole declaration with it
+				if len(d.Specs) == 1 {
+					return a.rangeWithDoc(symbol.File, d.Doc, d.Pos(), d.End()), true
+				}
+				return a.rangeWithDoc(symbol.File, specDoc(spec), spec.Pos(), spec.End()), true
+			}
+		}
+	}
+
+	return DeclRange{}, false
+}
+
+// syntaxForFile returns the parsed file with the given name from the loaded packages
+func (a *Analyzer) syntaxForFile(filename string) *ast.File {
+	for _, pkg := range a.packages {
+		for i, file := range pkg.Syntax {
+			if i < len(pkg.CompiledGoFiles) && pkg.CompiledGoFiles[i] == filename {
+				return file
+			}
+		}
+	}
+	return nil
+}
+
+// isSymbolIdent reports whether ident is the declaring identifier of symbol
+func (a *Analyzer) isSymbolIdent(ident *ast.Ident, symbol *Symbol) bool {
+	return ident.Name == symbol.Name && a.fileSet.Position(ident.Pos()).Offset == symbol.Position.Offset
+}
+
+// specDeclares reports whether spec declares symbol
+func (a *Analyzer) specDeclares(spec ast.Spec, symbol *Symbol) bool {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return a.isSymbolIdent(s.Name, symbol)
+	case *ast.ValueSpec:
+		for _, name := range s.Names {
+			if a.isSymbolIdent(name, symbol) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// specIsSingleName reports whether spec declares exactly one name
+func (a *Analyzer) specIsSingleName(spec ast.Spec) bool {
+	if s, ok := spec.(*ast.ValueSpec); ok {
+		return len(s.Names) == 1
+	}
+	return true
+}
+
+// specDoc returns the doc comment attached to a spec
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Doc
+	case *ast.ValueSpec:
+		return s.Doc
+	}
+	return nil
+}
+
+// rangeWithDoc converts a node range, extended to its doc comment, into byte offsets
+func (a *Analyzer) rangeWithDoc(filename string, doc *ast.CommentGroup, pos, end token.Pos) DeclRange {
+	if doc != nil {
+		pos = doc.Pos()
+	}
+	return DeclRange{
+		File:  filename,
+		Start: a.fileSet.Position(pos).Offset,
+		End:   a.fileSet.Position(end).Offset,
+	}
+}
+
+// unusedImports returns the ranges of the imports of a file that deleting the cut ranges
+// leaves unused: every use of the package name is inside a cut. Blank, dot and cgo
+// imports are kept.
+func (a *Analyzer) unusedImports(filename string, cuts []DeclRange) []DeclRange {
+	var file *ast.File
+	var info *types.Info
+	for _, pkg := range a.packages {
+		for i, syntax := range pkg.Syntax {
+			if i < len(pkg.CompiledGoFiles) && pkg.CompiledGoFiles[i] == filename {
+				file, info = syntax, pkg.TypesInfo
+			}
+		}
+	}
+	if file == nil || info == nil {
+		return nil
+	}
+
+	inCut := func(offset int) bool {
+		for _, cut := range cuts {
+			if offset >= cut.Start && offset < cut.End {
+				return true
+			}
+		}
+		return false
+	}
+	used := make(map[*types.PkgName]bool)
+	for ident, obj := range info.Uses {
+		if name, ok := obj.(*types.PkgName); ok && ident.Pos() >= file.Pos() && ident.Pos() < file.End() {
+			if !inCut(a.fileSet.Position(ident.Pos()).Offset) {
+				used[name] = true
+			}
+		}
+	}
+
+	var ranges []DeclRange
+	for _, decl := range file.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			continue
+		}
+		var unused []*ast.ImportSpec
+		for _, spec := range d.Specs {
+			spec := spec.(*ast.ImportSpec)
+			if spec.Name != nil && (spec.Name.Name == "_" || spec.Name.Name == ".") || spec.Path.Value == `"C"` {
+				continue
+			}
+			var obj types.Object
+			if spec.Name != nil {
+				obj = info.Defs[spec.Name]
+			} else {
+				obj = info.Implicits[spec]
+			}
+			if name, ok := obj.(*types.PkgName); ok && !used[name] {
+				unused = append(unused, spec)
+			}
+		}
+
+		// An import declaration left empty goes away whole
+		if len(unused) > 0 && len(unused) == len(d.Specs) {
+			ranges = append(ranges, a.rangeWithDoc(filename, d.Doc, d.Pos(), d.End()))
+			continue
+		}
+		for _, spec := range unused {
+			end := spec.End()
+			if spec.Comment != nil {
+				end = spec.Comment.End()
+			}
+			ranges = append(ranges, a.rangeWithDoc(filename, spec.Doc, spec.Pos(), end))
+		}
+	}
+	return ranges
+}
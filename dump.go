@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// graphSymbolRecord is a symbol line in the graph dump
+type graphSymbolRecord struct {
+	Type    string `json:"type"`
+	ID      int32  `json:"id"`
+	Key     string `json:"key"`
+	Verdict string `json:"verdict"`
+	*Symbol
+}
+
+// graphEdgeRecord is an edge line in the graph dump
+type graphEdgeRecord struct {
+	Type     string `json:"type"`
+	FromID   int32  `json:"from_id"`
+	ToID     int32  `json:"to_id"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	EdgeType string `json:"edge_type"`
+}
+
+// DumpGraph writes every symbol and every edge of the symbol graph as JSON lines
+func (a *Analyzer) DumpGraph(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create graph dump: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	keys := sortedSymbolKeys(a.symbols)
+	for _, key := range keys {
+		if err := enc.Encode(graphSymbolRecord{
+			Type:    "symbol",
+			ID:      a.graph.ids[key],
+			Key:     key,
+			Verdict: a.symbolVerdict(key),
+			Symbol:  a.symbols[key],
+		}); err != nil {
+			return fmt.Errorf("failed to write symbol %s: %w", key, err)
+		}
+	}
+
+	edges := 0
+	for _, key := range keys {
+		for _, edge := range a.findReferenceEdges(key) {
+			if err := enc.Encode(a.newGraphEdgeRecord(edge)); err != nil {
+				return fmt.Errorf("failed to write edge %s -> %s: %w", edge.From, edge.To, err)
+			}
+			edges++
+		}
+		for _, target := range a.aliasLinks[key] {
+			if err := enc.Encode(a.newGraphEdgeRecord(Edge{From: key, To: target, Kind: EdgeAlias})); err != nil {
+				return fmt.Errorf("failed to write edge %s -> %s: %w", key, target, err)
+			}
+			edges++
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write graph dump: %w", err)
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🕸️  Dumped %d symbols and %d edges to %s\n", len(keys), edges, path)
+	}
+
+	return nil
+}
+
+// newGraphEdgeRecord converts an edge into its dump representation
+func (a *Analyzer) newGraphEdgeRecord(edge Edge) graphEdgeRecord {
+	return graphEdgeRecord{
+		Type:     "edge",
+		FromID:   a.graph.ids[edge.From],
+		ToID:     a.graph.ids[edge.To],
+		From:     edge.From,
+		To:       edge.To,
+		File:     edge.Position.Filename,
+		Line:     edge.Position.Line,
+		Column:   edge.Position.Column,
+		EdgeType: edge.Kind,
+	}
+}
+
+// symbolVerdict classifies a symbol for exports
+func (a *Analyzer) symbolVerdict(key string) string {
+	switch {
+	case a.isReachable(key) && a.isKeptAlive(key):
+		return "kept-by-assertion"
+	case a.isReachable(key):
+		return "reachable"
+	case a.isTestFunction(a.symbols[key].Name):
+		return "test"
+	case a.isDocsOnly(key):
+		return "docs-only"
+	default:
+		return "orphaned"
+	}
+}
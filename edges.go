@@ -0,0 +1,101 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// Edge kinds of references, as indexes in edgeKinds
+const (
+	useReference uint8 = iota
+	useCall
+	useAddressTaken
+	useType
+	useEmbed
+)
+
+// edgeKinds are the names of the edge kinds of references
+var edgeKinds = [...]string{
+	useReference:    EdgeReference,
+	useCall:         EdgeCall,
+	useAddressTaken: EdgeAddressTaken,
+	useType:         EdgeTypeUse,
+	useEmbed:        EdgeEmbed,
+}
+
+// edgeContext holds the positions of the identifiers a declaration calls or embeds, which
+// tell the kind of the references made there. Syntax is inspected in preorder, so a call
+// or a struct is visited before the identifiers it contains.
+type edgeContext struct {
+	callees map[token.Pos]bool
+	embeds  map[token.Pos]bool
+}
+
+func newEdgeContext() *edgeContext {
+	return &edgeContext{callees: make(map[token.Pos]bool), embeds: make(map[token.Pos]bool)}
+}
+
+// visit records the function a call calls and the types a struct or interface embeds
+func (c *edgeContext) visit(n ast.Node) {
+	switch node := n.(type) {
+	case *ast.CallExpr:
+		c.callees[namePos(node.Fun)] = true
+	case *ast.StructType:
+		c.visitEmbeds(node.Fields)
+	case *ast.InterfaceType:
+		c.visitEmbeds(node.Methods)
+	}
+}
+
+// visitEmbeds records the embedded fields of a struct or interface field list
+func (c *edgeContext) visitEmbeds(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			typ := field.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			c.embeds[namePos(typ)] = true
+		}
+	}
+}
+
+// kind returns the edge kind of a reference to obj at pos
+func (c *edgeContext) kind(obj types.Object, pos token.Pos) uint8 {
+	switch obj.(type) {
+	case *types.TypeName:
+		if c.embeds[pos] {
+			return useEmbed
+		}
+		return useType
+	case *types.Func:
+		if c.callees[pos] {
+			return useCall
+		}
+		return useAddressTaken
+	}
+	return useReference
+}
+
+// namePos returns the position of the identifier naming what an expression denotes: the
+// selected name of a selector, through parentheses and type arguments
+func namePos(expr ast.Expr) token.Pos {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return e.Pos()
+		case *ast.SelectorExpr:
+			return e.Sel.Pos()
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		default:
+			return token.NoPos
+		}
+	}
+}
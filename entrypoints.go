@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// EntryPoint declares functions invoked by code outside the analysis, such as a
+// framework, a scheduler or generated code, which are treated as roots. It names either
+// a single function or every function whose name matches a regular expression.
+type EntryPoint struct {
+	Func     string `mapstructure:"func"`     // qualified function or method, e.g. pkg/path.Func or pkg/path.Type.Method
+	Pattern  string `mapstructure:"pattern"`  // regular expression on function names, Type.Method for methods, e.g. ^Handle
+	Packages string `mapstructure:"packages"` // package pattern restricting Pattern; default: any project package
+	Reason   string `mapstructure:"reason"`
+}
+
+// validate checks that an entry point is well-formed
+func (e EntryPoint) validate() error {
+	if (e.Func == "") == (e.Pattern == "") {
+		return fmt.Errorf("entry point needs exactly one of func or pattern")
+	}
+	if e.Func != "" && !strings.Contains(e.Func[strings.LastIndex(e.Func, "/")+1:], ".") {
+		return fmt.Errorf("invalid func %q (expected pkg/path.Func or pkg/path.Type.Method)", e.Func)
+	}
+	if _, err := regexp.Compile(e.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", e.Pattern, err)
+	}
+	if _, err := path.Match(strings.TrimSuffix(e.Packages, "/..."), ""); err != nil {
+		return fmt.Errorf("invalid packages pattern %q: %w", e.Packages, err)
+	}
+	return nil
+}
+
+// entryPointRoots returns the keys of the functions and methods declared as entry points.
+// Entry points naming no function are reported in verbose mode, since they are usually
+// typos or leftovers of a rename.
+func (a *Analyzer) entryPointRoots() []string {
+	if len(a.config.EntryPoints) == 0 {
+		return nil
+	}
+
+	patterns := make([]*regexp.Regexp, len(a.config.EntryPoints))
+	for i, entry := range a.config.EntryPoints {
+		if entry.Pattern != "" {
+			patterns[i] = regexp.MustCompile(entry.Pattern)
+		}
+	}
+
+	var roots []string
+	matched := make([]bool, len(a.config.EntryPoints))
+	for _, key := range sortedSymbolKeys(a.symbols) {
+		symbol := a.symbols[key]
+		if symbol.Kind != "function" && symbol.Kind != "method" {
+			continue
+		}
+		for i, entry := range a.config.EntryPoints {
+			var ok bool
+			if entry.Func != "" {
+				ok = entry.Func == symbol.Package+"."+symbol.keyName()
+			} else {
+				ok = patterns[i].MatchString(symbol.keyName()) &&
+					(entry.Packages == "" || matchPackagePattern(entry.Packages, symbol.Package))
+			}
+			if ok {
+				matched[i] = true
+				roots = append(roots, key)
+				break
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🚪 %d function(s) declared as entry points\n", len(roots))
+		for i, entry := range a.config.EntryPoints {
+			if !matched[i] {
+				fmt.Printf("⚠️  Entry point %s%s matches no function\n", entry.Func, entry.Pattern)
+			}
+		}
+	}
+
+	return roots
+}
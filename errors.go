@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Error codes reported for project loading failures
+const (
+	ErrCodeNoToolchain  = "E_NO_GO_TOOLCHAIN"
+	ErrCodeNoModule     = "E_NO_GO_MOD"
+	ErrCodeNetwork      = "E_NETWORK"
+	ErrCodeDependencies = "E_MODULE_DEPS"
+	ErrCodeNoPackages   = "E_NO_PACKAGES"
+	ErrCodeLoadFailed   = "E_LOAD_FAILED"
+)
+
+// LoadError describes a project loading failure together with a suggested fix
+type LoadError struct {
+	Code       string `json:"code"`
+	Condition  string `json:"condition"`
+	Suggestion string `json:"suggestion"`
+	Detail     string `json:"detail,omitempty"`
+
+	err error
+}
+
+func (e *LoadError) Error() string {
+	msg := fmt.Sprintf("%s (%s)\n  → %s", e.Condition, e.Code, e.Suggestion)
+	if e.Detail != "" {
+		msg += fmt.Sprintf("\n  details: %s", e.Detail)
+	}
+	return msg
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.err
+}
+
+// checkLoadPreconditions detects common environment problems before invoking the go tool
+func checkLoadPreconditions(projectPath string) error {
+	if _, err := exec.LookPath("go"); err != nil {
+		return &LoadError{
+			Code:       ErrCodeNoToolchain,
+			Condition:  "the go command was not found in PATH",
+			Suggestion: "install Go from https://go.dev/dl/ and make sure `go` is on your PATH",
+			err:        err,
+		}
+	}
+
+	if _, err := os.Stat(projectPath); err != nil {
+		return &LoadError{
+			Code:       ErrCodeLoadFailed,
+			Condition:  fmt.Sprintf("project path %s is not accessible", projectPath),
+			Suggestion: "check that the path exists and is readable",
+			err:        err,
+		}
+	}
+
+	if !hasModuleRoot(projectPath) {
+		return &LoadError{
+			Code:       ErrCodeNoModule,
+			Condition:  fmt.Sprintf("no go.mod found in %s or any parent directory", projectPath),
+			Suggestion: "run `go mod init <module-path>` in the project root, or point gorphanage at a directory inside a module",
+		}
+	}
+
+	return nil
+}
+
+// hasModuleRoot reports whether dir or one of its parents contains a go.mod or go.work file
+func hasModuleRoot(dir string) bool {
+	for {
+		for _, name := range []string{"go.mod", "go.work"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// classifyLoadError converts a raw loader error into an actionable LoadError
+func classifyLoadError(err error) *LoadError {
+	var loadErr *LoadError
+	if errors.As(err, &loadErr) {
+		return loadErr
+	}
+
+	msg := err.Error()
+	lower := strings.ToLower(msg)
+
+	switch {
+	case strings.Contains(lower, "executable file not found"):
+		return &LoadError{
+			Code:       ErrCodeNoToolchain,
+			Condition:  "the go command could not be executed",
+			Suggestion: "install Go from https://go.dev/dl/ and make sure `go` is on your PATH",
+			Detail:     msg,
+			err:        err,
+		}
+	case strings.Contains(lower, "go.mod file not found"),
+		strings.Contains(lower, "does not contain main module"),
+		strings.Contains(lower, "cannot find main module"):
+		return &LoadError{
+			Code:       ErrCodeNoModule,
+			Condition:  "the project directory is not part of a Go module",
+			Suggestion: "run `go mod init <module-path>` in the project root, or point gorphanage at a directory inside a module",
+			Detail:     msg,
+			err:        err,
+		}
+	case strings.Contains(lower, "dial tcp"),
+		strings.Contains(lower, "no such host"),
+		strings.Contains(lower, "i/o timeout"),
+		strings.Contains(lower, "connection refused"),
+		strings.Contains(lower, "proxy.golang.org"):
+		return &LoadError{
+			Code:       ErrCodeNetwork,
+			Condition:  "module dependencies could not be downloaded",
+			Suggestion: "check your network connection and GOPROXY settings, or run `go mod download` once while online",
+			Detail:     msg,
+			err:        err,
+		}
+	case strings.Contains(lower, "missing go.sum entry"),
+		strings.Contains(lower, "updates to go.mod needed"),
+		strings.Contains(lower, "no required module provides package"),
+		strings.Contains(lower, "inconsistent vendoring"):
+		return &LoadError{
+			Code:       ErrCodeDependencies,
+			Condition:  "module dependencies are out of date or incomplete",
+			Suggestion: "run `go mod tidy` (or `go mod vendor` when vendoring) in the project root",
+			Detail:     msg,
+			err:        err,
+		}
+	}
+
+	return &LoadError{
+		Code:       ErrCodeLoadFailed,
+		Condition:  "the project could not be loaded",
+		Suggestion: "run `go build ./...` in the project root to see the underlying problem",
+		Detail:     msg,
+		err:        err,
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// isExampleFunction reports whether a symbol is a documentation example from a test file
+func isExampleFunction(symbol *Symbol) bool {
+	return symbol.Kind == "function" &&
+		strings.HasPrefix(symbol.Name, "Example") &&
+		strings.HasSuffix(symbol.File, "_test.go")
+}
+
+// traceExamples marks every symbol reachable from an Example function. Examples reference
+// API deliberately, so symbols they reach are documented rather than dead; they are only
+// loaded with tests included.
+func (a *Analyzer) traceExamples() {
+	g := a.graph
+	a.exampleReached = make([]bool, g.size())
+
+	var queue []int32
+	for key, symbol := range a.symbols {
+		if !isExampleFunction(symbol) {
+			continue
+		}
+		id := g.ids[key]
+		if !a.exampleReached[id] {
+			a.exampleReached[id] = true
+			queue = append(queue, id)
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(queue) > 0 {
+		fmt.Printf("📚 Tracing %d example function(s)\n", len(queue))
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, target := range g.successors(current) {
+			if !a.exampleReached[target] {
+				a.exampleReached[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+}
+
+// isDocsOnly reports whether a symbol is reachable only from Example functions
+func (a *Analyzer) isDocsOnly(symbolKey string) bool {
+	id, ok := a.graph.ids[symbolKey]
+	return ok && int(id) < len(a.exampleReached) && a.exampleReached[id] && !a.isReachable(symbolKey)
+}
+
+// findDocsOnly returns the symbols reachable only from Example functions
+func (a *Analyzer) findDocsOnly() []*Symbol {
+	var docsOnly []*Symbol
+	for key, symbol := range a.symbols {
+		if !a.isTestFunction(symbol.Name) && a.inShard(symbol.Package) && a.isDocsOnly(key) {
+			docsOnly = append(docsOnly, symbol)
+		}
+	}
+
+	sort.Slice(docsOnly, func(i, j int) bool {
+		if docsOnly[i].File != docsOnly[j].File {
+			return docsOnly[i].File < docsOnly[j].File
+		}
+		return docsOnly[i].Start.Line < docsOnly[j].Start.Line
+	})
+	return docsOnly
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// SymbolExplanation describes why a symbol is or is not reachable
+type SymbolExplanation struct {
+	Key          string   `json:"key"`
+	Symbol       *Symbol  `json:"symbol"`
+	Reachable    bool     `json:"reachable"`
+	Path         []string `json:"path,omitempty"`          // entry point first
+	ReferencedBy []string `json:"referenced_by,omitempty"` // project symbols referencing it
+}
+
+// SymbolReferences lists the places a symbol is used
+type SymbolReferences struct {
+	Key        string        `json:"key"`
+	Symbol     *Symbol       `json:"symbol"`
+	References []RefLocation `json:"references"`
+}
+
+// RefLocation is a reference position suitable for output
+type RefLocation struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	From   string `json:"from,omitempty"` // key of the declaration the reference appears in
+}
+
+var (
+	explainJSON bool
+	explainAll  bool
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <symbol> [project-path]",
+	Short: "Explain why a symbol is reachable or orphaned",
+	Long: `Show the chain of references through which a symbol is reachable from an entry
+point, or the symbols referencing an orphan.
+
+Symbols are matched by name, package-qualified name or glob (ParseConf*); the package
+may be given by any trailing part of its import path (legacy.Parse, internal/legacy.Parse
+or example.com/app/internal/legacy.Parse). If nothing matches exactly, names are matched
+fuzzily. When several symbols match, an interactive list is shown to pick one (or use
+--all).`,
+	Example: `  gorphanage explain loadConfig
+  gorphanage explain 'ParseConf*' ./myproject
+  gorphanage explain --all 'internal/legacy.*'`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		analyzer, keys, err := analyzeAndMatch(args)
+		if err != nil {
+			return err
+		}
+
+		var explanations []*SymbolExplanation
+		for _, key := range keys {
+			explanations = append(explanations, analyzer.explainSymbol(key))
+		}
+
+		if analyzer.config.OutputJSON {
+			return printJSON(explanations)
+		}
+
+		for _, explanation := range explanations {
+			analyzer.printExplanation(explanation)
+		}
+		return nil
+	},
+}
+
+var refsCmd = &cobra.Command{
+	Use:   "refs <symbol> [project-path]",
+	Short: "List the references to a symbol",
+	Long: `List every place a symbol is used. Symbols are matched like in explain: by name,
+package-qualified name, glob or fuzzily.`,
+	Example: `  gorphanage refs loadConfig
+  gorphanage refs 'ParseConf*' ./myproject`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		analyzer, keys, err := analyzeAndMatch(args)
+		if err != nil {
+			return err
+		}
+
+		var results []*SymbolReferences
+		for _, key := range keys {
+			results = append(results, analyzer.symbolReferences(key))
+		}
+
+		if analyzer.config.OutputJSON {
+			return printJSON(results)
+		}
+
+		for _, result := range results {
+			symbol := result.Symbol
+			fmt.Printf("🔗 %s.%s (%s) - %d reference(s)\n", symbol.Package, symbol.displayName(), symbol.Kind, len(result.References))
+			for _, ref := range result.References {
+				fmt.Printf("  • %s\n", formatPosition(analyzer.relativePath(ref.File), Position{Line: ref.Line, Column: ref.Column}))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{explainCmd, refsCmd} {
+		cmd.Flags().BoolVar(&explainJSON, "json", false, "output results in JSON format")
+		cmd.Flags().BoolVar(&explainAll, "all", false, "use every matching symbol instead of asking which one")
+		rootCmd.AddCommand(cmd)
+	}
+}
+
+// analyzeAndMatch runs the analysis for explain/refs and resolves the symbol query
+func analyzeAndMatch(args []string) (*Analyzer, []string, error) {
+	projectPath := "."
+	if len(args) > 1 {
+		projectPath = args[1]
+	}
+
+	config, err := configFromViper(projectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.OutputJSON = config.OutputJSON || explainJSON
+
+	analyzer, _, err := analyze(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := analyzer.matchSymbols(args[0])
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("no symbol matches %q", args[0])
+	}
+	if len(keys) > 1 && !explainAll {
+		key, err := analyzer.chooseSymbol(args[0], keys)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = []string{key}
+	}
+
+	return analyzer, keys, nil
+}
+
+// matchSymbols resolves a query to symbol keys. Globs match against the name and the
+// qualified name; plain queries are matched exactly first and fuzzily otherwise.
+func (a *Analyzer) matchSymbols(query string) []string {
+	var matches []string
+
+	if strings.ContainsAny(query, "*?[") {
+		for key, symbol := range a.symbols {
+			for _, name := range symbolNames(symbol) {
+				if matched, _ := path.Match(query, name); matched {
+					matches = append(matches, key)
+					break
+				}
+			}
+		}
+		sort.Strings(matches)
+		return matches
+	}
+
+	for key, symbol := range a.symbols {
+		for _, name := range symbolNames(symbol) {
+			if name == query {
+				matches = append(matches, key)
+				break
+			}
+		}
+	}
+	if len(matches) > 0 {
+		sort.Strings(matches)
+		return matches
+	}
+
+	// Fuzzy: case-insensitive subsequence of the name, substrings ranked first
+	lowerQuery := strings.ToLower(query)
+	score := make(map[string]int)
+	for key, symbol := range a.symbols {
+		name := strings.ToLower(symbol.Name)
+		switch {
+		case strings.Contains(name, lowerQuery):
+			score[key] = 0
+		case isSubsequence(lowerQuery, name):
+			score[key] = 1
+		default:
+			continue
+		}
+		matches = append(matches, key)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if score[matches[i]] != score[matches[j]] {
+			return score[matches[i]] < score[matches[j]]
+		}
+		if len(a.symbols[matches[i]].Name) != len(a.symbols[matches[j]].Name) {
+			return len(a.symbols[matches[i]].Name) < len(a.symbols[matches[j]].Name)
+		}
+		return matches[i] < matches[j]
+	})
+	return matches
+}
+
+// symbolNames returns the names a symbol can be referred to by: Name, and Name qualified
+// by every trailing segment of its package path, from pkg.Name and internal/pkg.Name to
+// example.com/app/internal/pkg.Name; methods also as Type.Method and (*Type).Method
+func symbolNames(symbol *Symbol) []string {
+	names := []string{symbol.Name}
+	for _, name := range []string{symbol.keyName(), symbol.displayName()} {
+		if name != names[len(names)-1] {
+			names = append(names, name)
+		}
+	}
+	packages := []string{symbol.Package}
+	for i := len(symbol.Package) - 1; i >= 0; i-- {
+		if symbol.Package[i] == '/' {
+			packages = append(packages, symbol.Package[i+1:])
+		}
+	}
+	qualified := make([]string, 0, len(packages)*len(names))
+	for _, pkg := range packages {
+		for _, name := range names {
+			qualified = append(qualified, pkg+"."+name)
+		}
+	}
+	return append(names, qualified...)
+}
+
+// isSubsequence reports whether all characters of sub appear in s in order
+func isSubsequence(sub, s string) bool {
+	i := 0
+	for _, r := range s {
+		if i < len(sub) && rune(sub[i]) == r {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+// maxCandidates caps the disambiguation list
+const maxCandidates = 20
+
+// chooseSymbol asks the user to pick one of several matching symbols. Without a terminal
+// the candidates are reported in the error instead.
+func (a *Analyzer) chooseSymbol(query string, keys []string) (string, error) {
+	if len(keys) > maxCandidates {
+		keys = keys[:maxCandidates]
+	}
+
+	var list strings.Builder
+	for i, key := range keys {
+		symbol := a.symbols[key]
+		fmt.Fprintf(&list, "  %2d) %s.%s (%s) - %s\n", i+1, symbol.Package, symbol.displayName(), symbol.Kind,
+			formatPosition(a.relativePath(symbol.File), symbol.Start))
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 || a.config.OutputJSON {
+		return "", fmt.Errorf("%q matches several symbols, use a more specific name or --all:\n%s", query, list.String())
+	}
+
+	fmt.Printf("%q matches several symbols:\n%s", query, list.String())
+	fmt.Printf("Choose [1-%d]: ", len(keys))
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read choice: %w", err)
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(keys) {
+		return "", fmt.Errorf("invalid choice %q", strings.TrimSpace(line))
+	}
+	fmt.Println()
+
+	return keys[choice-1], nil
+}
+
+// explainSymbol builds the reachability explanation of a symbol
+func (a *Analyzer) explainSymbol(key string) *SymbolExplanation {
+	explanation := &SymbolExplanation{
+		Key:       key,
+		Symbol:    a.symbols[key],
+		Reachable: a.isReachable(key),
+		Path:      a.reachabilityPath(key),
+	}
+
+	if id, ok := a.graph.ids[key]; ok {
+		for _, from := range a.referrers(id) {
+			explanation.ReferencedBy = append(explanation.ReferencedBy, a.graph.keys[from])
+		}
+	}
+	sort.Strings(explanation.ReferencedBy)
+
+	return explanation
+}
+
+// symbolReferences collects the references to a symbol
+func (a *Analyzer) symbolReferences(key string) *SymbolReferences {
+	result := &SymbolReferences{
+		Key:        key,
+		Symbol:     a.symbols[key],
+		References: []RefLocation{},
+	}
+	for _, ref := range a.referencesTo(key) {
+		result.References = append(result.References, RefLocation{
+			File:   ref.File,
+			Line:   ref.Position.Line,
+			Column: ref.Position.Column,
+			From:   a.referencingSymbol(ref.Pos),
+		})
+	}
+	sort.Slice(result.References, func(i, j int) bool {
+		ri, rj := result.References[i], result.References[j]
+		if ri.File != rj.File {
+			return ri.File < rj.File
+		}
+		if ri.Line != rj.Line {
+			return ri.Line < rj.Line
+		}
+		return ri.Column < rj.Column
+	})
+	return result
+}
+
+// printExplanation outputs an explanation in human-readable format
+func (a *Analyzer) printExplanation(explanation *SymbolExplanation) {
+	symbol := explanation.Symbol
+	fmt.Printf("🔎 %s.%s (%s) - %s\n", symbol.Package, symbol.displayName(), symbol.Kind,
+		formatPosition(a.relativePath(symbol.File), symbol.Start))
+
+	if explanation.Reachable {
+		fmt.Println("✅ Reachable via:")
+		for i, key := range explanation.Path {
+			fmt.Printf("  %s%s\n", strings.Repeat("  ", i), a.describeKey(key))
+		}
+	} else {
+		fmt.Println("🗑️  Orphaned: not reachable from any entry point")
+	}
+
+	if len(explanation.ReferencedBy) > 0 {
+		fmt.Println("🔗 Referenced by:")
+		for _, key := range explanation.ReferencedBy {
+			status := "reachable"
+			if !a.isReachable(key) {
+				status = "orphaned"
+			}
+			fmt.Printf("  • %s [%s]\n", a.describeKey(key), status)
+		}
+	} else {
+		fmt.Println("🔗 Not referenced by any other symbol")
+	}
+	fmt.Println()
+}
+
+// describeKey formats a symbol key for display
+func (a *Analyzer) describeKey(key string) string {
+	symbol, ok := a.symbols[key]
+	if !ok {
+		return key
+	}
+	return fmt.Sprintf("%s.%s (%s)", symbol.Package, symbol.displayName(), symbol.Kind)
+}
+
+// printJSON writes v as indented JSON
+func printJSON(v any) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+
+	_ "modernc.org/sqlite"
+)
+
+// exportSchema defines the tables written by --export-db
+const exportSchema = `
+CREATE TABLE symbols (
+	key        TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	kind       TEXT NOT NULL,
+	package    TEXT NOT NULL,
+	file       TEXT NOT NULL,
+	start_line INTEGER NOT NULL,
+	start_col  INTEGER NOT NULL,
+	end_line   INTEGER NOT NULL,
+	end_col    INTEGER NOT NULL,
+	exported   INTEGER NOT NULL
+);
+
+CREATE TABLE refs (
+	symbol_key TEXT NOT NULL,
+	file       TEXT NOT NULL,
+	line       INTEGER NOT NULL,
+	col        INTEGER NOT NULL
+);
+
+CREATE TABLE edges (
+	from_key TEXT NOT NULL,
+	to_key   TEXT NOT NULL,
+	kind     TEXT NOT NULL
+);
+
+CREATE TABLE verdicts (
+	symbol_key TEXT PRIMARY KEY,
+	verdict    TEXT NOT NULL
+);
+
+CREATE INDEX refs_symbol ON refs(symbol_key);
+CREATE INDEX edges_from ON edges(from_key);
+CREATE INDEX edges_to ON edges(to_key);
+`
+
+// ExportDB writes symbols, references, edges and verdicts into a fresh SQLite database
+func (a *Analyzer) ExportDB(path string) error {
+	// Always start from an empty database so stale rows never mix with the current run
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove existing database: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(exportSchema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	if err := a.exportSymbols(tx); err != nil {
+		return err
+	}
+	if err := a.exportReferences(tx); err != nil {
+		return err
+	}
+	if err := a.exportEdges(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit database: %w", err)
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("💾 Exported symbol database to %s\n", path)
+	}
+
+	return nil
+}
+
+// exportSymbols writes the symbols and verdicts tables
+func (a *Analyzer) exportSymbols(tx *sql.Tx) error {
+	symbolStmt, err := tx.Prepare(`INSERT INTO symbols VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare symbol insert: %w", err)
+	}
+	defer symbolStmt.Close()
+
+	verdictStmt, err := tx.Prepare(`INSERT INTO verdicts VALUES (?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare verdict insert: %w", err)
+	}
+	defer verdictStmt.Close()
+
+	for _, key := range sortedSymbolKeys(a.symbols) {
+		symbol := a.symbols[key]
+		if _, err := symbolStmt.Exec(key, symbol.Name, symbol.Kind, symbol.Package, symbol.File,
+			symbol.Start.Line, symbol.Start.Column, symbol.End.Line, symbol.End.Column, symbol.Exported); err != nil {
+			return fmt.Errorf("failed to insert symbol %s: %w", key, err)
+		}
+
+		if _, err := verdictStmt.Exec(key, a.symbolVerdict(key)); err != nil {
+			return fmt.Errorf("failed to insert verdict for %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// exportReferences writes the refs table, in a stable order
+func (a *Analyzer) exportReferences(tx *sql.Tx) error {
+	stmt, err := tx.Prepare(`INSERT INTO refs VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare reference insert: %w", err)
+	}
+	defer stmt.Close()
+
+	keys := make([]string, 0, len(a.references))
+	for id := range a.references {
+		keys = append(keys, a.graph.keys[id])
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		refs := a.referencesTo(key)
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].File != refs[j].File {
+				return refs[i].File < refs[j].File
+			}
+			if refs[i].Position.Line != refs[j].Position.Line {
+				return refs[i].Position.Line < refs[j].Position.Line
+			}
+			return refs[i].Position.Column < refs[j].Position.Column
+		})
+		for _, ref := range refs {
+			if _, err := stmt.Exec(key, ref.File, ref.Position.Line, ref.Position.Column); err != nil {
+				return fmt.Errorf("failed to insert reference to %s: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportEdges writes the edges table
+func (a *Analyzer) exportEdges(tx *sql.Tx) error {
+	stmt, err := tx.Prepare(`INSERT INTO edges VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare edge insert: %w", err)
+	}
+	defer stmt.Close()
+
+	edges := a.symbolEdges()
+	for _, from := range sortedSymbolKeys(a.symbols) {
+		for _, edge := range edges[from] {
+			if _, err := stmt.Exec(from, edge.To, edge.Kind); err != nil {
+				return fmt.Errorf("failed to insert edge %s -> %s: %w", from, edge.To, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortedSymbolKeys returns symbol keys in a stable order
+func sortedSymbolKeys(symbols map[string]*Symbol) []string {
+	keys := make([]string, 0, len(symbols))
+	for key := range symbols {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
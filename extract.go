@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// ExtractableCluster is a dead slice of a package, its own types together with the code
+// using them, that depends on nothing but the exported API of importable packages and
+// that nothing else references. Teams archiving dead code rather than deleting it can
+// move it to a separate module as is.
+type ExtractableCluster struct {
+	Package     string   `json:"package"`
+	Symbols     []string `json:"symbols"`
+	Files       []string `json:"files"`
+	SharedFiles []string `json:"shared_files,omitempty"` // files also declaring code outside the cluster, to split
+}
+
+// extractableClusters groups the orphans of each package that reference each other, a
+// method always going with its receiver type, and returns the groups that could be moved
+// to a separate module: with at least one type and one function, no reference from
+// outside the group, and references out of it only to exported symbols of packages a
+// separate module can import.
+func (a *Analyzer) extractableClusters(orphans []*Symbol) []*ExtractableCluster {
+	// Union-find over the graph IDs of the orphans
+	parent := make(map[int32]int32, len(orphans))
+	var find func(id int32) int32
+	find = func(id int32) int32 {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	union := func(x, y int32) { parent[find(x)] = find(y) }
+
+	symbols := make(map[int32]*Symbol, len(orphans))
+	for _, orphan := range orphans {
+		id := a.graph.ids[a.symbolKey(orphan)]
+		parent[id] = id
+		symbols[id] = orphan
+	}
+
+	// Methods of live types cannot move without their type
+	stuck := make(map[int32]bool)
+	for id, symbol := range symbols {
+		if symbol.Kind != "method" {
+			continue
+		}
+		receiver := a.getSymbolKey(symbol.keyPackage(), strings.TrimPrefix(symbol.Receiver, "*"), "type")
+		if typeID, ok := a.graph.ids[receiver]; ok && symbols[typeID] != nil {
+			union(id, typeID)
+		} else {
+			stuck[id] = true
+		}
+	}
+	for id, symbol := range symbols {
+		for _, use := range a.declUses[id] {
+			if target := symbols[use.To]; target != nil && target.Package == symbol.Package {
+				union(id, use.To)
+			}
+		}
+	}
+
+	groups := make(map[int32][]int32)
+	for id := range symbols {
+		groups[find(id)] = append(groups[find(id)], id)
+	}
+
+	mainPackages := make(map[string]bool, len(a.mainPackages))
+	for _, pkg := range a.mainPackages {
+		mainPackages[pkg.PkgPath] = true
+	}
+	importable := func(symbol *Symbol) bool {
+		if symbol.Receiver != "" && !token.IsExported(strings.TrimPrefix(symbol.Receiver, "*")) {
+			return false
+		}
+		return symbol.Exported && !isInternalPackage(symbol.Package) && !mainPackages[symbol.Package]
+	}
+
+	declared := make(map[string]int)
+	for _, symbol := range a.symbols {
+		if symbol.Kind != "field" {
+			declared[symbol.File]++
+		}
+	}
+
+	var clusters []*ExtractableCluster
+	for root, ids := range groups {
+		inGroup := func(id int32) bool { return symbols[id] != nil && find(id) == root }
+		extractable := true
+		hasType, hasFunc := false, false
+		for _, id := range ids {
+			symbol := symbols[id]
+			hasType = hasType || symbol.Kind == "type"
+			hasFunc = hasFunc || symbol.Kind == "function" || symbol.Kind == "method"
+			if stuck[id] {
+				extractable = false
+				break
+			}
+			for _, from := range a.referrers(id) {
+				if !inGroup(from) {
+					extractable = false
+				}
+			}
+			for _, use := range a.declUses[id] {
+				target, ok := a.symbols[a.graph.keys[use.To]]
+				if ok && !inGroup(use.To) && !importable(target) {
+					extractable = false
+				}
+			}
+		}
+		if !extractable || !hasType || !hasFunc {
+			continue
+		}
+
+		cluster := &ExtractableCluster{Package: symbols[ids[0]].Package}
+		inFile := make(map[string]int)
+		for _, id := range ids {
+			symbol := symbols[id]
+			cluster.Symbols = append(cluster.Symbols, a.graph.keys[id])
+			inFile[symbol.File]++
+		}
+		for file, n := range inFile {
+			cluster.Files = append(cluster.Files, file)
+			if declared[file] > n {
+				cluster.SharedFiles = append(cluster.SharedFiles, file)
+			}
+		}
+		sort.Strings(cluster.Symbols)
+		sort.Strings(cluster.Files)
+		sort.Strings(cluster.SharedFiles)
+		clusters = append(clusters, cluster)
+	}
+	sortExtractableClusters(clusters)
+
+	if a.config.Verbose && !a.config.OutputJSON && len(clusters) > 0 {
+		fmt.Printf("🗄️  %d orphan cluster(s) could be extracted to a separate module\n", len(clusters))
+	}
+
+	return clusters
+}
+
+// sortExtractableClusters orders clusters largest first, then by package and symbols
+func sortExtractableClusters(clusters []*ExtractableCluster) {
+	sort.Slice(clusters, func(i, j int) bool {
+		ci, cj := clusters[i], clusters[j]
+		if len(ci.Symbols) != len(cj.Symbols) {
+			return len(ci.Symbols) > len(cj.Symbols)
+		}
+		if ci.Package != cj.Package {
+			return ci.Package < cj.Package
+		}
+		return ci.Symbols[0] < cj.Symbols[0]
+	})
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// fieldUsage tracks the reads and writes of one struct field of the project
+type fieldUsage struct {
+	symbol  *Symbol
+	typeKey string // key of the struct type declaring the field
+	reads   int
+	writes  int
+}
+
+// findFields collects the fields of the project's struct types and counts their reads and
+// writes, with --fields. A field is read when a selector such as x.f appears anywhere but
+// on the left of an assignment; composite literal keys and assignments only write it.
+// Reads are counted module-wide, dead code included. Test variants declare their
+// package's fields again and are merged by key.
+func (a *Analyzer) findFields() {
+	a.fields = make(map[string]*fieldUsage)
+	fieldKeys := make(map[*types.Var]string)
+	for _, pkg := range a.packages {
+		for _, file := range pkg.Syntax {
+			a.declareFields(pkg, file, fieldKeys)
+		}
+	}
+
+	a.wholeStructs = make(map[string]bool)
+	walked := make(map[string]bool)
+	for _, pkg := range a.packages {
+		for _, file := range pkg.Syntax {
+			filename := a.fileSet.Position(file.Package).Filename
+			if walked[filename] {
+				continue
+			}
+			walked[filename] = true
+			a.countFieldUses(pkg, file, fieldKeys)
+		}
+		// Map keys are hashed and compared field by field
+		for _, tv := range pkg.TypesInfo.Types {
+			if m, ok := tv.Type.Underlying().(*types.Map); ok {
+				a.readWhole(m.Key())
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🧱 Tracking reads and writes of %d struct field(s)\n", len(a.fields))
+	}
+}
+
+// declareFields records the named fields of the top-level struct types of a file. Embedded
+// fields are left out: they are used whenever a promoted field or method is.
+func (a *Analyzer) declareFields(pkg *packages.Package, file *ast.File, fieldKeys map[*types.Var]string) {
+	filename := a.fileSet.Position(file.Package).Filename
+	keyPath := a.keyPath(pkg.Types)
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok || typeSpec.Name.Name == "_" || typeSpec.Assign.IsValid() {
+				continue
+			}
+			typeKey := a.getSymbolKey(keyPath, typeSpec.Name.Name, "type")
+
+			for _, field := range structType.Fields.List {
+				// Tagged fields are read by reflection: encoders, ORMs, config loaders
+				if field.Tag != nil {
+					continue
+				}
+				for _, name := range field.Names {
+					obj, ok := pkg.TypesInfo.Defs[name].(*types.Var)
+					if !ok || name.Name == "_" {
+						continue
+					}
+					key := a.getSymbolKey(keyPath, typeSpec.Name.Name+"."+name.Name, "field")
+					fieldKeys[obj] = key
+					if _, exists := a.fields[key]; exists {
+						continue
+					}
+
+					startPos := a.fileSet.Position(name.Pos())
+					endPos := a.fileSet.Position(field.End())
+					a.fields[key] = &fieldUsage{
+						typeKey: typeKey,
+						symbol: &Symbol{
+							Name:      name.Name,
+							Kind:      "field",
+							File:      filename,
+							Position:  startPos,
+							Start:     Position{Line: startPos.Line, Column: startPos.Column},
+							End:       Position{Line: endPos.Line, Column: endPos.Column},
+							Exported:  ast.IsExported(name.Name),
+							Package:   pkg.PkgPath,
+							Module:    moduleOf(pkg),
+							ModuleDir: a.moduleDirs[pkg.Types],
+							Receiver:  typeSpec.Name.Name,
+						},
+					}
+				}
+			}
+		}
+	}
+}
+
+// countFieldUses counts the field reads and writes of a file and records the struct types
+// read as a whole: compared with == or passed as empty interfaces, where reflection may
+// read every field
+func (a *Analyzer) countFieldUses(pkg *packages.Package, file *ast.File, fieldKeys map[*types.Var]string) {
+	usage := func(obj types.Object) *fieldUsage {
+		field, ok := obj.(*types.Var)
+		if !ok || !field.IsField() {
+			return nil
+		}
+		return a.fields[fieldKeys[field.Origin()]]
+	}
+
+	// Selectors assigned to only write their field
+	written := make(map[*ast.SelectorExpr]bool)
+	write := func(expr ast.Expr) {
+		if sel, ok := ast.Unparen(expr).(*ast.SelectorExpr); ok {
+			written[sel] = true
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				write(lhs)
+			}
+		case *ast.IncDecStmt:
+			write(node.X)
+		case *ast.CompositeLit:
+			structType, _ := pkg.TypesInfo.TypeOf(node).Underlying().(*types.Struct)
+			for i, elt := range node.Elts {
+				var field *fieldUsage
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						field = usage(pkg.TypesInfo.Uses[key])
+					}
+				} else if structType != nil && i < structType.NumFields() {
+					field = usage(structType.Field(i)) // T{1, 2}
+				}
+				if field != nil {
+					field.writes++
+				}
+			}
+		case *ast.CallExpr:
+			a.recordReflectedArgs(pkg, node)
+		case *ast.BinaryExpr:
+			if node.Op == token.EQL || node.Op == token.NEQ {
+				a.readWhole(pkg.TypesInfo.TypeOf(node.X))
+			}
+		}
+		return true
+	})
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		selection, ok := pkg.TypesInfo.Selections[sel]
+		if !ok || selection.Kind() != types.FieldVal {
+			return true
+		}
+		if field := usage(selection.Obj()); field != nil {
+			if written[sel] {
+				field.writes++
+			} else {
+				field.reads++
+			}
+		}
+		return true
+	})
+}
+
+// recordReflectedArgs marks the project struct types passed to empty interface parameters,
+// as fmt.Println, json.Marshal and reflect.ValueOf take them
+func (a *Analyzer) recordReflectedArgs(pkg *packages.Package, call *ast.CallExpr) {
+	sig, ok := pkg.TypesInfo.TypeOf(call.Fun).(*types.Signature)
+	if !ok {
+		return
+	}
+	params := sig.Params()
+	for i, arg := range call.Args {
+		var param types.Type
+		switch {
+		case sig.Variadic() && i >= params.Len()-1:
+			param = params.At(params.Len() - 1).Type()
+			if slice, ok := param.(*types.Slice); ok && !call.Ellipsis.IsValid() {
+				param = slice.Elem()
+			}
+		case i < params.Len():
+			param = params.At(i).Type()
+		default:
+			continue
+		}
+		if iface, ok := param.Underlying().(*types.Interface); !ok || !iface.Empty() {
+			continue
+		}
+
+		t := pkg.TypesInfo.TypeOf(arg)
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		a.readWhole(t)
+	}
+}
+
+// readWhole records that every field of a named struct type is read
+func (a *Analyzer) readWhole(t types.Type) {
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		obj := named.Origin().Obj()
+		a.wholeStructs[a.getSymbolKey(a.keyPath(obj.Pkg()), obj.Name(), "type")] = true
+	}
+}
+
+// unreadFields returns the fields of reachable struct types that are never read. Fields of
+// orphaned types go with their type. Types compared, used as map keys or passed to fmt,
+// encoders or reflect as empty interfaces are read as a whole and left out, and so are the
+// exported fields of the public API under module semantics.
+func (a *Analyzer) unreadFields() []*Symbol {
+	var unread []*Symbol
+	for _, field := range a.fields {
+		symbol := field.symbol
+		if field.reads > 0 || !a.inShard(symbol.Package) || !a.isReachable(field.typeKey) || a.wholeStructs[field.typeKey] {
+			continue
+		}
+		if a.semantics == SemanticsModule && symbol.Exported && ast.IsExported(symbol.Receiver) && !isInternalPackage(symbol.Package) {
+			continue
+		}
+		symbol.FieldWrites = field.writes
+		unread = append(unread, symbol)
+	}
+	sort.Slice(unread, func(i, j int) bool { return a.symbolKey(unread[i]) < a.symbolKey(unread[j]) })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(unread) > 0 {
+		fmt.Printf("🧱 %d struct field(s) are never read\n", len(unread))
+	}
+
+	return unread
+}
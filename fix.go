@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// FixReport describes the deletions applied by the fix command
+type FixReport struct {
+	Deleted     []*Symbol `json:"deleted"`
+	Suppressed  []*Symbol `json:"suppressed,omitempty"` // marked wontfix during an interactive review
+	DryRun      bool      `json:"dry_run,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	PullRequest string    `json:"pull_request,omitempty"` // URL of the opened pull or merge request
+
+	reviewed bool // candidates were chosen in an interactive review
+}
+
+var (
+	fixBatchSize   int
+	fixOpenPR      bool
+	fixDryRun      bool
+	fixInteractive bool
+	fixUnsafe      bool
+	fixUndo        bool
+	fixJSON        bool
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix [project-path]",
+	Short: "Delete orphans whose removal is verified safe",
+	Long: `Probes orphans by re-type-checking the project without them, deletes up to
+--batch-size of those that can be removed cleanly together with imports left unused,
+and verifies the result with go build ./... and go test ./... . When verification
+fails, every file is restored.
+
+Orphans to delete with care, whose initializer has side effects such as registering
+something, are left alone unless --fix-unsafe is set: deleting them type-checks but may
+change behavior.
+
+With --open-pr the deletions are committed on a new branch, pushed to origin and
+proposed as a pull request (GitHub, token from GITHUB_TOKEN) or merge request
+(GitLab, token from GITLAB_TOKEN) listing the findings. The working tree must be clean
+and is switched back to the original branch afterwards.
+
+With --interactive each candidate is shown with its source, to be deleted, skipped or
+suppressed (recorded as wontfix in the baseline). The chosen deletions are applied
+together at the end, and nothing is changed when verification fails.
+
+Deletions applied in place are recorded in .gorphanage/undo.json with the original
+content of every edited file. --undo reverts the most recent one, refusing when a file
+was changed since; repeat it to go further back.`,
+	Example: `  gorphanage fix --dry-run .
+  gorphanage fix --interactive .
+  gorphanage fix --undo .
+  gorphanage fix --open-pr --batch-size=20 .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+
+		if fixUndo {
+			if fixOpenPR || fixDryRun || fixInteractive {
+				return fmt.Errorf("--undo cannot be combined with --open-pr, --dry-run or --interactive")
+			}
+			absPath, err := filepath.Abs(projectPath)
+			if err != nil {
+				return fmt.Errorf("failed to resolve project path: %w", err)
+			}
+			cmd.SilenceUsage = true
+			fix, err := undoLastFix(absPath)
+			if err != nil {
+				return err
+			}
+			if fixJSON {
+				// The restored content is already back in the files
+				for _, entry := range fix.Files {
+					entry.Original = nil
+				}
+				return printJSON(fix)
+			}
+			printUndo(fix)
+			return nil
+		}
+
+		if fixBatchSize <= 0 {
+			return fmt.Errorf("invalid --batch-size %d (must be positive)", fixBatchSize)
+		}
+		if fixOpenPR && fixDryRun {
+			return fmt.Errorf("--open-pr and --dry-run cannot be combined")
+		}
+		if fixInteractive {
+			if fixDryRun || fixJSON {
+				return fmt.Errorf("--interactive cannot be combined with --dry-run or --json")
+			}
+			if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+				return fmt.Errorf("--interactive needs a terminal")
+			}
+		}
+
+		config, err := configFromViper(projectPath)
+		if err != nil {
+			return err
+		}
+		config.OutputJSON = config.OutputJSON || fixJSON
+		config.Probe = true
+		config.ProbeSamples = fixBatchSize
+		config.FixUnsafe = fixUnsafe
+
+		cmd.SilenceUsage = true
+
+		var target *pullRequestTarget
+		if fixOpenPR {
+			if target, err = preparePullRequest(config.ProjectPath); err != nil {
+				return err
+			}
+		}
+
+		analyzer, result, err := analyze(config)
+		if err != nil {
+			return err
+		}
+
+		report := &FixReport{Deleted: verifiedFixes(result.OrphanedSymbols, fixBatchSize), DryRun: fixDryRun}
+		if fixInteractive && len(report.Deleted) > 0 {
+			report.reviewed = true
+			if report.Deleted, report.Suppressed, err = analyzer.reviewFixes(report.Deleted, os.Stdin); err != nil {
+				return err
+			}
+		}
+		if len(report.Deleted) > 0 && !fixDryRun {
+			if fixOpenPR {
+				err = analyzer.fixInPullRequest(target, report)
+			} else {
+				err = analyzer.fixInPlace(report.Deleted)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if len(report.Suppressed) > 0 {
+			if err := analyzer.suppressFindings(report.Suppressed); err != nil {
+				return err
+			}
+		}
+
+		if config.OutputJSON {
+			return printJSON(report)
+		}
+		analyzer.printFixReport(report)
+		return nil
+	},
+}
+
+func init() {
+	fixCmd.Flags().IntVar(&fixBatchSize, "batch-size", 20, "maximum number of orphans deleted at once")
+	fixCmd.Flags().BoolVar(&fixOpenPR, "open-pr", false, "commit the deletions on a new branch and open a pull request")
+	fixCmd.Flags().BoolVar(&fixDryRun, "dry-run", false, "list the orphans that would be deleted without changing any file")
+	fixCmd.Flags().BoolVar(&fixUndo, "undo", false, "revert the most recent fix applied in place, from .gorphanage/undo.json")
+	fixCmd.Flags().BoolVar(&fixInteractive, "interactive", false, "review each orphan with its source and choose to delete, skip or suppress it")
+	fixCmd.Flags().BoolVar(&fixUnsafe, "fix-unsafe", false, "also delete orphans whose initializer has side effects, skipped by default")
+	fixCmd.Flags().BoolVar(&fixJSON, "json", false, "output the fix report in JSON format")
+	rootCmd.AddCommand(fixCmd)
+}
+
+// verifiedFixes selects up to limit orphans whose deletion was verified by probing,
+// skipping findings marked wontfix in the baseline
+func verifiedFixes(orphans []*Symbol, limit int) []*Symbol {
+	var fixes []*Symbol
+	for _, orphan := range orphans {
+		if orphan.Confidence == ConfidenceVerified && orphan.State != StateWontfix {
+			fixes = append(fixes, orphan)
+		}
+	}
+
+	sort.Slice(fixes, func(i, j int) bool {
+		if fixes[i].File != fixes[j].File {
+			return fixes[i].File < fixes[j].File
+		}
+		return fixes[i].Start.Line < fixes[j].Start.Line
+	})
+	if len(fixes) > limit {
+		fixes = fixes[:limit]
+	}
+	return fixes
+}
+
+// fixInPlace deletes the symbols in the working tree and verifies the project still builds
+// and passes its tests, restoring every file otherwise
+func (a *Analyzer) fixInPlace(symbols []*Symbol) error {
+	originals, err := a.applyDeletions(symbols)
+	if err != nil {
+		return err
+	}
+
+	if err := a.verifyFixes(originals); err != nil {
+		if restoreErr := restoreFiles(originals); restoreErr != nil {
+			return fmt.Errorf("%w (and restoring files failed: %v)", err, restoreErr)
+		}
+		return err
+	}
+
+	if err := a.recordUndo(symbols, originals); err != nil {
+		return fmt.Errorf("orphans were deleted, but they cannot be undone: %w", err)
+	}
+	return nil
+}
+
+// applyDeletions removes the declarations of the symbols, drops imports they leave unused
+// and returns the original content of every edited file. Only the lines around the cuts
+// change: the rest of a file keeps its layout, gofmt-ed or not.
+func (a *Analyzer) applyDeletions(symbols []*Symbol) (map[string][]byte, error) {
+	byFile := make(map[string][]DeclRange)
+	for _, symbol := range symbols {
+		declRange, ok := a.declarationRange(symbol)
+		if !ok {
+			return nil, fmt.Errorf("failed to locate the declaration of %s.%s", symbol.Package, symbol.displayName())
+		}
+		byFile[declRange.File] = append(byFile[declRange.File], declRange)
+	}
+
+	originals := make(map[string][]byte, len(byFile))
+	for file, ranges := range byFile {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			restoreFiles(originals)
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		edited := applyEdits(content, a.deletionEdits(file, content, ranges))
+		if _, err := parser.ParseFile(token.NewFileSet(), file, edited, parser.ParseComments); err != nil {
+			restoreFiles(originals)
+			return nil, fmt.Errorf("failed to delete from %s: %w", a.relativePath(file), err)
+		}
+
+		originals[file] = content
+		if err := os.WriteFile(file, edited, 0644); err != nil {
+			restoreFiles(originals)
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	return originals, nil
+}
+
+// byteEdit replaces content[Start:End] of a file with NewText
+type byteEdit struct {
+	Start   int
+	End     int
+	NewText []byte
+}
+
+// deletionEdits returns the edits removing the declaration ranges from a file's content,
+// together with the imports they leave unused. The edits refer to the original content,
+// last in the file first, and don't overlap.
+func (a *Analyzer) deletionEdits(file string, content []byte, ranges []DeclRange) []byteEdit {
+	ranges = append(slices.Clone(ranges), a.unusedImports(file, ranges)...)
+
+	// Cut from the end so earlier offsets stay valid
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start > ranges[j].Start })
+	var edits []byteEdit
+	edited := content
+	for i, declRange := range ranges {
+		if i > 0 && declRange.End > ranges[i-1].Start {
+			continue // same declaration as the previous range
+		}
+		start, end, gap := cutSpan(edited, declRange.Start, declRange.End)
+		edited = applyEdits(edited, []byteEdit{{start, end, gap}})
+
+		// Only whitespace separates a cut from the previous one it reaches into
+		if n := len(edits); n > 0 && end > edits[n-1].Start {
+			previous := edits[n-1]
+			edits[n-1] = byteEdit{start, previous.End, append(gap, previous.NewText[end-previous.Start:]...)}
+			continue
+		}
+		edits = append(edits, byteEdit{start, end, gap})
+	}
+	return edits
+}
+
+// applyEdits returns src with the edits applied, given last in the file first
+func applyEdits(src []byte, edits []byteEdit) []byte {
+	edited := append([]byte(nil), src...)
+	for _, edit := range edits {
+		edited = append(edited[:edit.Start], append(append([]byte(nil), edit.NewText...), edited[edit.End:]...)...)
+	}
+	return edited
+}
+
+// cutSpan widens the cut of src[start:end] to the whitespace around it and returns the
+// line breaks to leave instead, as gofmt would: the blank line that separated the cut
+// from its neighbors, if any, none next to a bracket and a single final newline at the
+// end of the file
+func cutSpan(src []byte, start, end int) (int, int, []byte) {
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+	i := start
+	for i > 0 && isSpace(src[i-1]) {
+		i--
+	}
+	j := end
+	for j < len(src) && isSpace(src[j]) {
+		j++
+	}
+
+	before := bytes.Count(src[i:start], []byte("\n"))
+	after := bytes.Count(src[end:j], []byte("\n"))
+	newlines := min(max(before, after), 2)
+	switch {
+	case j == len(src):
+		newlines = 1
+	case i > 0 && (src[i-1] == '(' || src[i-1] == '{') || src[j] == ')' || src[j] == '}':
+		newlines = min(newlines, 1)
+	}
+
+	// The next line keeps its indentation
+	var gap []byte
+	switch {
+	case newlines == 0:
+		gap = append(gap, src[i:start]...)
+	case after > 0:
+		gap = append(bytes.Repeat([]byte("\n"), newlines), src[end+bytes.LastIndexByte(src[end:j], '\n')+1:j]...)
+	default:
+		gap = append(bytes.Repeat([]byte("\n"), newlines), src[i+bytes.LastIndexByte(src[i:start], '\n')+1:start]...)
+	}
+	return i, j, gap
+}
+
+// restoreFiles writes back the original content of edited files
+func restoreFiles(originals map[string][]byte) error {
+	for file, content := range originals {
+		if err := os.WriteFile(file, content, 0644); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// verifyFixes builds and tests the project after deletions, and every other module an
+// edited file belongs to, such as a nested module
+func (a *Analyzer) verifyFixes(edited map[string][]byte) error {
+	dirs := []string{a.config.ProjectPath}
+	for file := range edited {
+		if modFile, ok := findModuleFile(filepath.Dir(file)); ok && !slices.Contains(dirs, filepath.Dir(modFile)) {
+			dirs = append(dirs, filepath.Dir(modFile))
+		}
+	}
+	sort.Strings(dirs[1:])
+
+	tags := "-tags=" + strings.Join(a.config.Tags, ",")
+	for _, dir := range dirs {
+		// Built binaries are discarded rather than written into the module
+		for _, args := range [][]string{{"build", "-o", os.DevNull, tags, "./..."}, {"test", tags, "./..."}} {
+			if a.config.Verbose && !a.config.OutputJSON {
+				fmt.Printf("🔨 Running go %s in %s\n", strings.Join(args, " "), dir)
+			}
+
+			cmd := exec.Command("go", args...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("go %s failed in %s after deleting orphans: %w\n%s", strings.Join(args, " "), a.relativePath(dir), err, strings.TrimSpace(string(out)))
+			}
+		}
+	}
+	return nil
+}
+
+// printFixReport outputs a fix report in human-readable format
+func (a *Analyzer) printFixReport(report *FixReport) {
+	for _, symbol := range report.Suppressed {
+		fmt.Printf("🔕 Suppressed %s.%s (%s) as wontfix\n", symbol.Package, symbol.displayName(), symbol.Kind)
+	}
+	if len(report.Deleted) == 0 {
+		if report.reviewed {
+			fmt.Println("✅ No orphan chosen for deletion")
+		} else {
+			fmt.Println("✅ No orphan could be verified as safe to delete")
+		}
+		return
+	}
+
+	verb := "Deleted"
+	if report.DryRun {
+		verb = "Would delete"
+	}
+	fmt.Printf("\n🧹 %s %d orphan(s):\n", verb, len(report.Deleted))
+	for _, symbol := range report.Deleted {
+		fmt.Printf("  • %s.%s (%s) - %s\n", symbol.Package, symbol.displayName(), symbol.Kind, formatPosition(a.relativePath(symbol.File), symbol.Start))
+	}
+
+	if report.Branch != "" {
+		fmt.Printf("\n🌿 Committed on branch %s\n", report.Branch)
+	}
+	if report.PullRequest != "" {
+		fmt.Printf("🔀 Opened %s\n", report.PullRequest)
+	}
+}
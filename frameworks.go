@@ -0,0 +1,380 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// FrameworkDetector recognizes the slots in which a framework takes code it invokes
+// later, such as request handlers, RPC services or command actions. Functions passed or
+// assigned to such a slot are roots; for a slot of interface type, the methods of the
+// value implementing the interface are. Besides the built-in detectors, an embedding
+// application can plug in its own with WithFrameworkDetectors.
+type FrameworkDetector interface {
+	// Name identifies the framework in verbose output and in the frameworks setting
+	Name() string
+	// HandlerArg reports whether argument i of a call to fn is a handler slot
+	HandlerArg(fn *types.Func, i int) bool
+	// HandlerField reports whether a struct field, named pkg.Type.Field, is a handler slot
+	HandlerField(field string) bool
+}
+
+// ContainerDetector is implemented by the detectors of dependency injection containers,
+// whose handlers are constructors: the container hands their results to other code,
+// which may call any of their methods.
+type ContainerDetector interface {
+	FrameworkDetector
+	// ProvidesResults reports whether the methods of the types registered constructors
+	// return, and of the values registered, are kept as well
+	ProvidesResults() bool
+}
+
+// builtinFrameworkDetectors are the detectors run unless the frameworks setting selects
+// some of them
+var builtinFrameworkDetectors = []FrameworkDetector{
+	httpDetector{}, grpcDetector{}, cobraDetector{},
+	containerDetector{name: "wire", funcs: []string{
+		"github.com/google/wire.Build", "github.com/google/wire.NewSet", "github.com/google/wire.Struct",
+		"github.com/google/wire.Bind", "github.com/google/wire.Value", "github.com/google/wire.InterfaceValue",
+		"github.com/google/wire.FieldsOf",
+	}},
+	containerDetector{name: "fx", funcs: []string{
+		"go.uber.org/fx.Provide", "go.uber.org/fx.Invoke", "go.uber.org/fx.Supply",
+		"go.uber.org/fx.Decorate", "go.uber.org/fx.Replace", "go.uber.org/fx.Annotate",
+	}},
+	containerDetector{name: "dig", funcs: []string{
+		"go.uber.org/dig.Container.Provide", "go.uber.org/dig.Container.Invoke", "go.uber.org/dig.Container.Decorate",
+		"go.uber.org/dig.Scope.Provide", "go.uber.org/dig.Scope.Invoke", "go.uber.org/dig.Scope.Decorate",
+	}},
+}
+
+// httpDetector finds net/http handlers. Any argument taking an http.Handler or a handler
+// function is a slot, which covers http.Handle, ServeMux, third-party routers and
+// middleware alike.
+type httpDetector struct{}
+
+func (httpDetector) Name() string { return "net/http" }
+
+func (httpDetector) HandlerArg(fn *types.Func, i int) bool {
+	return isHTTPHandler(paramType(fn, i))
+}
+
+func (httpDetector) HandlerField(field string) bool {
+	return field == "net/http.Server.Handler"
+}
+
+// isHTTPHandler reports whether a type is http.Handler, http.HandlerFunc or the signature
+// of a handler function
+func isHTTPHandler(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "net/http" {
+		return named.Obj().Name() == "Handler" || named.Obj().Name() == "HandlerFunc"
+	}
+	sig, ok := t.Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() != 2 || sig.Results().Len() != 0 {
+		return false
+	}
+	return types.TypeString(sig.Params().At(0).Type(), nil) == "net/http.ResponseWriter" &&
+		types.TypeString(sig.Params().At(1).Type(), nil) == "*net/http.Request"
+}
+
+// grpcDetector finds gRPC service implementations, handed to the generated
+// Register<Service>Server functions
+type grpcDetector struct{}
+
+func (grpcDetector) Name() string { return "grpc" }
+
+func (grpcDetector) HandlerArg(fn *types.Func, i int) bool {
+	name := fn.Name()
+	if i != 1 || !strings.HasPrefix(name, "Register") || !strings.HasSuffix(name, "Server") {
+		return false
+	}
+	switch types.TypeString(paramType(fn, 0), nil) {
+	case "google.golang.org/grpc.ServiceRegistrar", "*google.golang.org/grpc.Server":
+		return true
+	}
+	return false
+}
+
+func (grpcDetector) HandlerField(string) bool { return false }
+
+// cobraCommand is the package-qualified name of cobra's command type
+const cobraCommand = "github.com/spf13/cobra.Command"
+
+// cobraHandlerFields are the fields of cobra.Command holding functions cobra calls
+var cobraHandlerFields = []string{
+	"Run", "RunE", "PreRun", "PreRunE", "PostRun", "PostRunE",
+	"PersistentPreRun", "PersistentPreRunE", "PersistentPostRun", "PersistentPostRunE",
+	"Args", "ValidArgsFunction",
+}
+
+// cobraDetector finds the actions, hooks and completion functions of cobra commands
+type cobraDetector struct{}
+
+func (cobraDetector) Name() string { return "cobra" }
+
+func (cobraDetector) HandlerArg(fn *types.Func, i int) bool {
+	switch qualifiedFuncName(fn) {
+	case "github.com/spf13/cobra.OnInitialize", "github.com/spf13/cobra.OnFinalize":
+		return true
+	case cobraCommand + ".SetHelpFunc", cobraCommand + ".SetUsageFunc", cobraCommand + ".SetFlagErrorFunc":
+		return i == 0
+	case cobraCommand + ".RegisterFlagCompletionFunc":
+		return i == 1
+	}
+	return false
+}
+
+func (cobraDetector) HandlerField(field string) bool {
+	name, ok := strings.CutPrefix(field, cobraCommand+".")
+	return ok && slices.Contains(cobraHandlerFields, name)
+}
+
+// containerDetector finds the constructors, functions and values registered with a
+// dependency injection container: every argument of its registration functions. They are
+// only referenced as values and called reflectively, or by generated code for wire.
+type containerDetector struct {
+	name  string
+	funcs []string // qualified registration functions and methods
+}
+
+func (d containerDetector) Name() string { return d.name }
+
+func (d containerDetector) HandlerArg(fn *types.Func, i int) bool {
+	return slices.Contains(d.funcs, qualifiedFuncName(fn))
+}
+
+func (containerDetector) HandlerField(string) bool { return false }
+
+func (containerDetector) ProvidesResults() bool { return true }
+
+// validateFramework checks a frameworks entry
+func validateFramework(name string) error {
+	var names []string
+	for _, detector := range builtinFrameworkDetectors {
+		if detector.Name() == name {
+			return nil
+		}
+		names = append(names, detector.Name())
+	}
+	return fmt.Errorf("unknown framework %q (expected %s or none)", name, strings.Join(names, ", "))
+}
+
+// frameworkDetectors returns the selected built-in detectors and the plugged-in ones
+func (a *Analyzer) frameworkDetectors() []FrameworkDetector {
+	var detectors []FrameworkDetector
+	for _, detector := range builtinFrameworkDetectors {
+		if a.config.Frameworks == nil || slices.Contains(a.config.Frameworks, detector.Name()) {
+			detectors = append(detectors, detector)
+		}
+	}
+	return append(detectors, a.config.FrameworkDetectors...)
+}
+
+// paramType returns the type of argument i of a call to fn, or nil
+func paramType(fn *types.Func, i int) types.Type {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return nil
+	}
+	params := sig.Params()
+	if sig.Variadic() && i >= params.Len()-1 {
+		return params.At(params.Len() - 1).Type().(*types.Slice).Elem()
+	}
+	if i >= params.Len() {
+		return nil
+	}
+	return params.At(i).Type()
+}
+
+// findFrameworkRegistrations records the handlers a file registers with a framework,
+// either as a call argument or as the value of a handler field in a composite literal or
+// an assignment
+func (a *Analyzer) findFrameworkRegistrations(pkg *packages.Package, file *ast.File) {
+	detectors := a.frameworkDetectors()
+	if len(detectors) == 0 {
+		return
+	}
+	info := pkg.TypesInfo
+
+	field := func(name string, fieldType types.Type, value ast.Expr) {
+		if name == "" {
+			return
+		}
+		for _, detector := range detectors {
+			if detector.HandlerField(name) {
+				a.keepHandler(info, value, fieldType, detector)
+				return
+			}
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			fn, ok := typeutil.Callee(info, node).(*types.Func)
+			if !ok || node.Ellipsis.IsValid() {
+				return true
+			}
+			for i, arg := range node.Args {
+				for _, detector := range detectors {
+					if detector.HandlerArg(fn, i) {
+						a.keepHandler(info, arg, paramType(fn, i), detector)
+						break
+					}
+				}
+			}
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if v, ok := info.Uses[key].(*types.Var); ok && v.IsField() {
+					field(qualifiedFieldName(info.TypeOf(node), key.Name), v.Type(), kv.Value)
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				sel, ok := lhs.(*ast.SelectorExpr)
+				if !ok || i >= len(node.Rhs) {
+					continue
+				}
+				selection, ok := info.Selections[sel]
+				if !ok || selection.Kind() != types.FieldVal {
+					continue
+				}
+				field(qualifiedFieldName(selection.Recv(), sel.Sel.Name), selection.Type(), node.Rhs[i])
+			}
+		}
+		return true
+	})
+}
+
+// keepHandler records the project functions a handler expression hands to a framework as
+// roots: the function it names, or the methods of the interface slot it implements. For a
+// container, the methods of the constructor results or of the value are kept too.
+// Function literals need nothing: their references belong to the enclosing declaration.
+func (a *Analyzer) keepHandler(info *types.Info, expr ast.Expr, slot types.Type, detector FrameworkDetector) {
+	expr = ast.Unparen(expr)
+	framework := detector.Name()
+	container, _ := detector.(ContainerDetector)
+	providesResults := container != nil && container.ProvidesResults()
+
+	// http.HandlerFunc(f) and other conversions hand over their operand
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 && info.Types[call.Fun].IsType() {
+		a.keepHandler(info, call.Args[0], slot, detector)
+		return
+	}
+
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	}
+	if ident != nil {
+		if fn, ok := info.Uses[ident].(*types.Func); ok {
+			a.keepHandlerFunc(fn, framework)
+			if sig, ok := fn.Type().(*types.Signature); ok && providesResults {
+				for i := 0; i < sig.Results().Len(); i++ {
+					a.keepMethods(sig.Results().At(i).Type(), framework)
+				}
+			}
+			return
+		}
+	}
+
+	if providesResults {
+		if _, isFunc := expr.(*ast.FuncLit); !isFunc {
+			a.keepMethods(info.TypeOf(expr), framework)
+		}
+		return
+	}
+	if slot == nil {
+		return
+	}
+	iface, ok := slot.Underlying().(*types.Interface)
+	t := info.TypeOf(expr)
+	if !ok || t == nil || types.IsInterface(t) {
+		return
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		obj, _, _ := types.LookupFieldOrMethod(t, true, method.Pkg(), method.Name())
+		if fn, ok := obj.(*types.Func); ok {
+			a.keepHandlerFunc(fn, framework)
+		}
+	}
+}
+
+// keepMethods records the methods of a type provided by a container, including those
+// promoted from embedded fields. Interfaces provide no methods of their own.
+func (a *Analyzer) keepMethods(t types.Type, framework string) {
+	if t == nil || types.IsInterface(t) {
+		return
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if _, ok := t.(*types.Named); !ok {
+		return
+	}
+	methods := types.NewMethodSet(types.NewPointer(t))
+	for i := 0; i < methods.Len(); i++ {
+		if fn, ok := methods.At(i).Obj().(*types.Func); ok {
+			a.keepHandlerFunc(fn, framework)
+		}
+	}
+}
+
+// keepHandlerFunc records a project function registered with a framework
+func (a *Analyzer) keepHandlerFunc(fn *types.Func, framework string) {
+	// Methods promoted from an embedded interface have no body to keep
+	if fn.Pkg() == nil || isInterfaceMethod(fn) {
+		return
+	}
+	if id, ok := a.objectID(fn); ok {
+		if _, exists := a.symbols[a.graph.keys[id]]; exists {
+			a.handlers[id] = framework
+		}
+	}
+}
+
+// frameworkRoots returns the keys of functions registered with a framework
+func (a *Analyzer) frameworkRoots() []string {
+	var roots []string
+	perFramework := make(map[string]int)
+	for id, framework := range a.handlers {
+		roots = append(roots, a.graph.keys[id])
+		perFramework[framework]++
+	}
+	sort.Strings(roots)
+
+	if a.config.Verbose && !a.config.OutputJSON && len(roots) > 0 {
+		var counts []string
+		for _, detector := range a.frameworkDetectors() {
+			if n := perFramework[detector.Name()]; n > 0 {
+				counts = append(counts, fmt.Sprintf("%s: %d", detector.Name(), n))
+			}
+		}
+		fmt.Printf("🧩 %d handler(s) registered with frameworks kept alive (%s)\n", len(roots), strings.Join(counts, ", "))
+	}
+
+	return roots
+}
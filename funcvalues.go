@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// recordFuncSinks records where the function values taken in a piece of a declaration are
+// stored directly: a package-level variable they are assigned to or initialize, or a
+// struct field assigned or set in a composite literal. Values passed, returned or stored
+// anywhere else have no sink and may be called.
+func (a *Analyzer) recordFuncSinks(pkg *packages.Package, node ast.Node) {
+	info := pkg.TypesInfo
+	store := func(value ast.Expr, sink string) {
+		if sink == "" {
+			return
+		}
+		if ident := funcValueIdent(info, value); ident != nil {
+			a.funcSinks[ident.Pos()] = sink
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok != token.ASSIGN || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				store(node.Rhs[i], a.storageKey(info, lhs))
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) != len(node.Values) {
+				return true
+			}
+			for i, name := range node.Names {
+				if id, ok := a.objectIDs[info.Defs[name]]; ok {
+					store(node.Values[i], a.graph.keys[id])
+				}
+			}
+		case *ast.CompositeLit:
+			structType, ok := info.TypeOf(node).Underlying().(*types.Struct)
+			if !ok {
+				return true
+			}
+			for i, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						store(kv.Value, a.fieldKey(info.TypeOf(node), key.Name))
+					}
+				} else if i < structType.NumFields() {
+					store(elt, a.fieldKey(info.TypeOf(node), structType.Field(i).Name()))
+				}
+			}
+		}
+		return true
+	})
+}
+
+// funcValueIdent returns the identifier of a package-level function used as a value, as in
+// f or pkg.F, or nil
+func funcValueIdent(info *types.Info, expr ast.Expr) *ast.Ident {
+	var ident *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	case *ast.IndexExpr:
+		return funcValueIdent(info, e.X)
+	case *ast.IndexListExpr:
+		return funcValueIdent(info, e.X)
+	default:
+		return nil
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Type().(*types.Signature).Recv() != nil {
+		return nil
+	}
+	return ident
+}
+
+// storageKey returns the key of the package-level variable or struct field an assignment
+// target stores to directly, or ""
+func (a *Analyzer) storageKey(info *types.Info, lhs ast.Expr) string {
+	switch e := ast.Unparen(lhs).(type) {
+	case *ast.Ident:
+		if id, ok := a.objectIDs[info.Uses[e]]; ok {
+			return a.graph.keys[id]
+		}
+	case *ast.SelectorExpr:
+		selection, ok := info.Selections[e]
+		if !ok {
+			if id, ok := a.objectIDs[info.Uses[e.Sel]]; ok {
+				return a.graph.keys[id] // pkg.Var
+			}
+			return ""
+		}
+		// Promoted fields belong to an embedded struct
+		if selection.Kind() == types.FieldVal && len(selection.Index()) == 1 {
+			return a.fieldKey(selection.Recv(), e.Sel.Name)
+		}
+	}
+	return ""
+}
+
+// fieldKey returns the key of a field of a named struct type, or pointer to one, as
+// findFields declares it
+func (a *Analyzer) fieldKey(structType types.Type, field string) string {
+	if pointer, ok := structType.(*types.Pointer); ok {
+		structType = pointer.Elem()
+	}
+	named, ok := types.Unalias(structType).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return a.getSymbolKey(a.keyPath(named.Obj().Pkg()), named.Obj().Name()+"."+field, "field")
+}
+
+// uncalledFunctions returns the reachable functions whose value is taken but never called:
+// reachable code only stores them in write-only variables or, with --fields, in fields
+// nothing reads, so no call site can ever get them. A single call, or a value passed on or
+// stored anywhere else, keeps a function out.
+func (a *Analyzer) uncalledFunctions(writeOnly, unreadFields []*Symbol) []*Symbol {
+	dead := make(map[string]bool, len(writeOnly)+len(unreadFields))
+	for _, variable := range writeOnly {
+		dead[a.symbolKey(variable)] = true
+	}
+	for _, field := range unreadFields {
+		dead[a.getSymbolKey(field.keyPackage(), field.Receiver+"."+field.Name, "field")] = true
+	}
+	if len(dead) == 0 {
+		return nil
+	}
+
+	// Uses by reachable code, each position once
+	uses := make(map[int32][]fileUse)
+	seen := make(map[token.Pos]bool)
+	add := func(use fileUse) {
+		if !seen[use.Pos] {
+			seen[use.Pos] = true
+			uses[use.To] = append(uses[use.To], use)
+		}
+	}
+	for from, declUses := range a.declUses {
+		if int(from) < len(a.reached) && a.reached[from] != 0 {
+			for _, use := range declUses {
+				add(use)
+			}
+		}
+	}
+	inits := []map[string][]fileUse{a.initUses}
+	if !a.config.IgnoreAssertions {
+		inits = append(inits, a.keepAliveUses)
+	}
+	for _, byPackage := range inits {
+		for _, initUses := range byPackage {
+			for _, use := range initUses {
+				add(use)
+			}
+		}
+	}
+
+	var uncalled []*Symbol
+	for id, fnUses := range uses {
+		key := a.graph.keys[id]
+		symbol, ok := a.symbols[key]
+		// Entry points are called from outside the project
+		if !ok || symbol.Kind != "function" || a.reached[id] <= 1 || !a.inShard(symbol.Package) {
+			continue
+		}
+		if a.semantics == SemanticsModule && symbol.Exported && !isInternalPackage(symbol.Package) {
+			continue
+		}
+
+		var storedIn []string
+		for _, use := range fnUses {
+			sink := a.funcSinks[use.Pos]
+			if use.Kind != useAddressTaken || !dead[sink] {
+				storedIn = nil
+				break
+			}
+			storedIn = append(storedIn, sink)
+		}
+		if len(storedIn) == 0 {
+			continue
+		}
+
+		reported := *symbol
+		reported.StoredIn = mergeStrings(storedIn, nil)
+		uncalled = append(uncalled, &reported)
+	}
+	sort.Slice(uncalled, func(i, j int) bool { return a.symbolKey(uncalled[i]) < a.symbolKey(uncalled[j]) })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(uncalled) > 0 {
+		fmt.Printf("📭 %d function(s) are stored but never called\n", len(uncalled))
+	}
+
+	return uncalled
+}
+
+// printUncalledFunctions lists the functions only stored where nothing reads them
+func (a *Analyzer) printUncalledFunctions(result *AnalysisResult) {
+	if len(result.UncalledFunctions) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📭 Address taken, never called (stored only in write-only variables or unread fields):\n")
+	for _, fn := range result.UncalledFunctions {
+		storage := make([]string, 0, len(fn.StoredIn))
+		for _, key := range fn.StoredIn {
+			storage = append(storage, result.describeKey(key))
+		}
+		fmt.Printf("  📍 %s - %s [stored in %s]\n", fn.Name, formatPosition(a.relativePath(fn.File), fn.Start), strings.Join(storage, ", "))
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// generatedHeaderPattern is the standard marker of generated Go files
+	generatedHeaderPattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+	// stringerTypesPattern extracts the type list from stringer's header
+	stringerTypesPattern = regexp.MustCompile(`stringer .*-type[= ]([\w,]+)`)
+
+	// mockgenSourcePattern matches mockgen's source line in source or reflect mode:
+	// "// Source: foo.go" or "// Source: example.com/pkg (interfaces: Foo,Bar)"
+	mockgenSourcePattern = regexp.MustCompile(`^// Source: (\S+)(?: \(interfaces: ([\w,]+)\))?$`)
+)
+
+// Policies for orphans declared in generated files, selected with --generated
+const (
+	GeneratedInclude  = "include"  // report them with the other orphans (default)
+	GeneratedExclude  = "exclude"  // leave them out of the results
+	GeneratedSeparate = "separate" // list them in their own section
+)
+
+// hasGeneratedHeader reports whether a file carries the standard
+// "// Code generated ... DO NOT EDIT." comment before its package clause
+func hasGeneratedHeader(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if generatedHeaderPattern.MatchString(comment.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generatedApart reports whether an orphan is declared in a generated file that
+// --generated keeps out of the findings
+func (a *Analyzer) generatedApart(symbol *Symbol) bool {
+	return symbol.Generated && (a.config.Generated == GeneratedExclude || a.config.Generated == GeneratedSeparate)
+}
+
+// splitGenerated separates the orphans of generated files from the other orphans as
+// --generated selects: excluded ones are dropped, separate ones are returned apart
+func (a *Analyzer) splitGenerated(orphans []*Symbol) ([]*Symbol, []*Symbol) {
+	var findings, generated []*Symbol
+	dropped := 0
+	for _, orphan := range orphans {
+		switch {
+		case !a.generatedApart(orphan):
+			findings = append(findings, orphan)
+		case a.config.Generated == GeneratedSeparate:
+			generated = append(generated, orphan)
+		default:
+			dropped++
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && dropped > 0 {
+		fmt.Printf("🤖 %d orphan(s) of generated files excluded\n", dropped)
+	}
+
+	return findings, generated
+}
+
+// GeneratedFile describes a generated file and the declarations it was derived from
+type GeneratedFile struct {
+	Path        string
+	Dir         string
+	Package     string
+	Types       []string // types named by the generator (stringer -type, mockgen interfaces)
+	SourceFile  string   // source file the generator read, as written in the header (mockgen source mode)
+	SourcePkg   string   // package the generator read (mockgen reflect mode)
+	IsGenerated bool
+}
+
+// linkGeneratedTwins links orphans to generated files derived from them, since deleting
+// the source also requires regenerating or deleting the derived file
+func (a *Analyzer) linkGeneratedTwins(orphans []*Symbol) error {
+	generated, err := a.findGeneratedFiles()
+	if err != nil {
+		return err
+	}
+
+	linked := 0
+	for _, orphan := range orphans {
+		for _, gen := range generated {
+			if gen.Path != orphan.File && a.derivesFrom(gen, orphan) {
+				orphan.GeneratedTwins = append(orphan.GeneratedTwins, gen.Path)
+			}
+		}
+		if len(orphan.GeneratedTwins) > 0 {
+			linked++
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && linked > 0 {
+		fmt.Printf("🧬 %d orphan(s) have generated twins\n", linked)
+	}
+
+	return nil
+}
+
+// derivesFrom reports whether the generated file was produced from the symbol
+func (a *Analyzer) derivesFrom(g *GeneratedFile, symbol *Symbol) bool {
+	namesSymbol := false
+	for _, name := range g.Types {
+		if name == symbol.Name {
+			namesSymbol = true
+			break
+		}
+	}
+
+	switch {
+	case g.SourcePkg != "":
+		return namesSymbol && g.SourcePkg == symbol.Package
+	case g.SourceFile != "":
+		// mockgen writes the -source argument as given, usually relative to the source
+		// package; only interfaces that actually got a mock count
+		source := filepath.Clean(g.SourceFile)
+		fromSource := symbol.File == source || strings.HasSuffix(symbol.File, string(filepath.Separator)+source)
+		_, mocked := a.symbols[a.getSymbolKey(g.Package, "Mock"+symbol.Name, "type")]
+		return symbol.Kind == "type" && fromSource && mocked
+	default:
+		return namesSymbol && symbol.Kind == "type" && g.Dir == filepath.Dir(symbol.File)
+	}
+}
+
+// findGeneratedFiles returns the generated files of all loaded packages whose generator
+// can be traced back to source declarations
+func (a *Analyzer) findGeneratedFiles() ([]*GeneratedFile, error) {
+	var generated []*GeneratedFile
+	seen := make(map[string]bool)
+	for _, pkg := range a.packages {
+		for _, file := range pkg.GoFiles {
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+
+			gen, err := readGeneratedHeader(file)
+			if err != nil {
+				return nil, err
+			}
+			gen.Package = pkg.PkgPath
+			if gen.IsGenerated && (len(gen.Types) > 0 || gen.SourceFile != "") {
+				generated = append(generated, gen)
+			}
+		}
+	}
+	return generated, nil
+}
+
+// readGeneratedHeader parses the leading comments of a Go file for generator information
+func readGeneratedHeader(path string) (*GeneratedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	gen := &GeneratedFile{Path: path, Dir: filepath.Dir(path)}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+
+		if generatedHeaderPattern.MatchString(line) {
+			gen.IsGenerated = true
+			if match := stringerTypesPattern.FindStringSubmatch(line); match != nil {
+				gen.Types = strings.Split(match[1], ",")
+			}
+			continue
+		}
+
+		if match := mockgenSourcePattern.FindStringSubmatch(line); match != nil {
+			if match[2] != "" {
+				gen.SourcePkg = match[1]
+				gen.Types = strings.Split(match[2], ",")
+			} else if strings.HasSuffix(match[1], ".go") {
+				gen.SourceFile = match[1]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return gen, nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// trackInstantiations records the instantiations of generic symbols in a file. Instantiating
+// with concrete types counts directly; instantiating with the type parameters of an
+// enclosing generic declaration only counts once that declaration is instantiated itself.
+func (a *Analyzer) trackInstantiations(pkg *packages.Package, file *ast.File) {
+	if len(pkg.TypesInfo.Instances) == 0 {
+		return
+	}
+
+	for _, decl := range file.Decls {
+		var name *ast.Ident
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name = d.Name
+		case *ast.GenDecl:
+			if len(d.Specs) == 1 {
+				if spec, ok := d.Specs[0].(*ast.TypeSpec); ok {
+					name = spec.Name
+				}
+			}
+		}
+		enclosing, declared := int32(-1), false
+		if name != nil {
+			enclosing, declared = a.objectIDs[pkg.TypesInfo.Defs[name]]
+		}
+
+		ast.Inspect(decl, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			instance, ok := pkg.TypesInfo.Instances[ident]
+			if !ok {
+				return true
+			}
+			obj := pkg.TypesInfo.Uses[ident]
+			if obj == nil || obj.Pkg() == nil {
+				return true
+			}
+
+			target, ok := a.objectID(obj)
+			switch {
+			case !ok:
+			case !hasTypeParams(instance.TypeArgs):
+				a.instantiated[target] = true
+			case declared && enclosing != target:
+				a.genericDeps[enclosing] = append(a.genericDeps[enclosing], target)
+			}
+			return true
+		})
+	}
+}
+
+// hasTypeParams reports whether any type argument mentions a type parameter
+func hasTypeParams(args *types.TypeList) bool {
+	for i := 0; i < args.Len(); i++ {
+		if mentionsTypeParam(args.At(i), make(map[types.Type]bool)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsTypeParam reports whether a type is or contains a type parameter
+func mentionsTypeParam(t types.Type, seen map[types.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Pointer:
+		return mentionsTypeParam(t.Elem(), seen)
+	case *types.Slice:
+		return mentionsTypeParam(t.Elem(), seen)
+	case *types.Array:
+		return mentionsTypeParam(t.Elem(), seen)
+	case *types.Chan:
+		return mentionsTypeParam(t.Elem(), seen)
+	case *types.Map:
+		return mentionsTypeParam(t.Key(), seen) || mentionsTypeParam(t.Elem(), seen)
+	case *types.Named:
+		args := t.TypeArgs()
+		for i := 0; i < args.Len(); i++ {
+			if mentionsTypeParam(args.At(i), seen) {
+				return true
+			}
+		}
+	case *types.Signature:
+		return mentionsTypeParam(t.Params(), seen) || mentionsTypeParam(t.Results(), seen)
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if mentionsTypeParam(t.At(i).Type(), seen) {
+				return true
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if mentionsTypeParam(t.Field(i).Type(), seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// propagateInstantiations marks generics instantiated through an instantiated generic
+func (a *Analyzer) propagateInstantiations() {
+	var queue []int32
+	for id := range a.instantiated {
+		queue = append(queue, id)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range a.genericDeps[current] {
+			if !a.instantiated[dep] {
+				a.instantiated[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+}
+
+// isInstantiated reports whether a generic symbol has a concrete instantiation
+func (a *Analyzer) isInstantiated(symbolKey string) bool {
+	id, ok := a.graph.ids[symbolKey]
+	return ok && a.instantiated[id]
+}
+
+// isConstraintInterface reports whether a type is an interface with a type set, such as
+// ~int | ~float64, which can only appear in type parameter lists. Like any type, such a
+// constraint is used when a reachable declaration names it, in its type parameters or in
+// another constraint.
+func isConstraintInterface(t types.Type) bool {
+	iface, ok := t.Underlying().(*types.Interface)
+	return ok && !iface.IsMethodSet()
+}
+
+// unusedConstraintPackages returns the project packages that declare nothing but type
+// constraints, none of which is used. Constraints packages, like a copy of
+// golang.org/x/exp/constraints, are judged per constraint: these are the ones whose
+// every constraint is an orphan, so the whole package can go.
+func (a *Analyzer) unusedConstraintPackages(orphans []*Symbol) []string {
+	orphaned := make(map[string]int)
+	for _, orphan := range orphans {
+		if orphan.Constraint {
+			orphaned[orphan.keyPackage()]++
+		}
+	}
+	if len(orphaned) == 0 {
+		return nil
+	}
+
+	// Packages declaring anything besides constraints are kept out
+	declared := make(map[string]int)
+	for _, symbol := range a.symbols {
+		pkgPath := symbol.keyPackage()
+		if _, ok := orphaned[pkgPath]; !ok {
+			continue
+		}
+		if !symbol.Constraint {
+			delete(orphaned, pkgPath)
+			continue
+		}
+		declared[pkgPath]++
+	}
+
+	var unused []string
+	for pkgPath, count := range orphaned {
+		if declared[pkgPath] == count {
+			unused = append(unused, pkgPath)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
@@ -0,0 +1,142 @@
+package main
+
+import "strings"
+
+// symbolGraph is the symbol interning table and the compact, deduplicated symbol graph
+// used for reachability. Symbols are numbered densely; string keys are only built once per
+// symbol and looked up at the output boundary. The edges of all nodes share one backing
+// array: the successors of node i are targets[offsets[i]:offsets[i+1]].
+type symbolGraph struct {
+	ids     map[string]int32
+	refs    map[symbolRef]int32
+	keys    []string
+	offsets []int32
+	targets []int32
+}
+
+// symbolRef identifies a symbol without building its string key
+type symbolRef struct {
+	pkgPath string
+	name    string
+	kind    string
+}
+
+// newSymbolGraph creates an empty graph
+func newSymbolGraph() *symbolGraph {
+	return &symbolGraph{
+		ids:  make(map[string]int32),
+		refs: make(map[symbolRef]int32),
+	}
+}
+
+// symbolID returns the interned ID of a symbol, building its key only the first time
+func (a *Analyzer) symbolID(pkgPath, name, kind string) int32 {
+	ref := symbolRef{pkgPath: pkgPath, name: name, kind: kind}
+	if id, ok := a.graph.refs[ref]; ok {
+		return id
+	}
+	id := a.graph.intern(a.getSymbolKey(pkgPath, name, kind))
+	a.graph.refs[ref] = id
+	return id
+}
+
+// intern returns the ID of a symbol key, numbering it if it is new
+func (g *symbolGraph) intern(key string) int32 {
+	if id, ok := g.ids[key]; ok {
+		return id
+	}
+	id := int32(len(g.keys))
+	g.ids[key] = id
+	g.keys = append(g.keys, key)
+	return id
+}
+
+// successors returns the deduplicated outgoing edges of a node
+func (g *symbolGraph) successors(id int32) []int32 {
+	if int(id)+1 >= len(g.offsets) {
+		return nil
+	}
+	return g.targets[g.offsets[id]:g.offsets[id+1]]
+}
+
+// size returns the number of nodes with edge lists
+func (g *symbolGraph) size() int {
+	return max(len(g.offsets)-1, 0)
+}
+
+// buildGraph compacts the per-declaration references and alias links into the symbol graph.
+// Each symbol gets the distinct symbols used in its own declaration, minus itself.
+func (a *Analyzer) buildGraph() {
+	g := a.graph
+	for _, key := range sortedSymbolKeys(a.symbols) {
+		g.intern(key)
+	}
+	for _, targets := range a.aliasLinks {
+		for _, target := range targets {
+			g.intern(target)
+		}
+	}
+
+	n := len(g.keys)
+	g.offsets = make([]int32, n+1)
+	g.targets = g.targets[:0]
+
+	// stamp[t] == id+1 marks t as already added to the edges of id
+	stamp := make([]int32, n)
+	add := func(id, target int32) {
+		if target != id && stamp[target] != id+1 {
+			stamp[target] = id + 1
+			g.targets = append(g.targets, target)
+		}
+	}
+
+	dispatch := a.dispatchTargets()
+	satisfaction := a.satisfactionTargets()
+	stdlib := a.stdlibTargets()
+
+	for id := int32(0); id < int32(n); id++ {
+		key := g.keys[id]
+		for _, use := range a.declUses[id] {
+			add(id, use.To)
+		}
+		for _, target := range a.aliasLinks[key] {
+			add(id, g.ids[target])
+		}
+		for _, target := range dispatch[id] {
+			add(id, target)
+		}
+		for _, target := range satisfaction[id] {
+			add(id, target)
+		}
+		for _, target := range stdlib[id] {
+			add(id, target)
+		}
+		g.offsets[id+1] = int32(len(g.targets))
+	}
+}
+
+// dispatchTargets links every project type to its methods named like a method of an
+// interface in the loaded packages or their imports: once the type is reachable, such a
+// method may be called through an interface value without being referenced by name.
+// Well-known methods (MarshalJSON, DeepCopyObject, ...) are linked the same way and
+// remembered, since even a call graph does not see who calls them.
+func (a *Analyzer) dispatchTargets() map[int32][]int32 {
+	names := a.interfaceMethodNames()
+	wellKnown := a.wellKnownMethodNames()
+
+	targets := make(map[int32][]int32)
+	for key, symbol := range a.symbols {
+		if symbol.Kind != "method" || !names[symbol.Name] && !wellKnown[symbol.Name] {
+			continue
+		}
+		if wellKnown[symbol.Name] {
+			a.wellKnown[a.graph.ids[key]] = true
+		}
+		typeID, ok := a.graph.ids[a.getSymbolKey(symbol.keyPackage(), strings.TrimPrefix(symbol.Receiver, "*"), "type")]
+		if !ok {
+			continue
+		}
+		targets[typeID] = append(targets[typeID], a.graph.ids[key])
+	}
+	return targets
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// HistoryPoint is the analysis outcome at one historical commit
+type HistoryPoint struct {
+	Commit       string    `json:"commit"`
+	Date         time.Time `json:"date"`
+	TotalSymbols int       `json:"total_symbols"`
+	Orphans      int       `json:"orphans"`
+	Error        string    `json:"error,omitempty"`
+
+	orphanKeys map[string]*Symbol
+}
+
+// DeadSince records when a currently orphaned symbol became (and stayed) dead
+type DeadSince struct {
+	Symbol *Symbol   `json:"symbol"`
+	Commit string    `json:"commit"`
+	Date   time.Time `json:"date"`
+}
+
+// HistoryReport is the dead-code growth timeline
+type HistoryReport struct {
+	Since     string          `json:"since"`
+	Step      string          `json:"step"`
+	Timeline  []*HistoryPoint `json:"timeline"`
+	DeadSince []*DeadSince    `json:"dead_since"`
+}
+
+type historyCommit struct {
+	hash string
+	date time.Time
+}
+
+var (
+	historySince string
+	historyStep  string
+	historyJSON  bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Analyze dead code across git history",
+	Long:  "Run the analysis at historical commits to see how dead code evolved over time",
+}
+
+var historyScanCmd = &cobra.Command{
+	Use:   "scan [project-path]",
+	Short: "Build a dead-code growth timeline from git history",
+	Long: `Checks out historical commits into temporary git worktrees, runs the analysis at
+each point and reports a dead-code growth timeline, including when each currently
+orphaned symbol became dead.`,
+	Example: `  gorphanage history scan --since=v1.0.0 --step=monthly .`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+
+		config, err := configFromViper(projectPath)
+		if err != nil {
+			return err
+		}
+		config.OutputJSON = config.OutputJSON || historyJSON
+
+		report, err := scanHistory(config, historySince, historyStep)
+		if err != nil {
+			return err
+		}
+
+		if config.OutputJSON {
+			jsonData, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(jsonData))
+			return nil
+		}
+
+		printHistory(report)
+		return nil
+	},
+}
+
+func init() {
+	historyScanCmd.Flags().StringVar(&historySince, "since", "", "git revision to start from (default: entire history)")
+	historyScanCmd.Flags().StringVar(&historyStep, "step", "monthly", "sampling step: commit, daily, weekly or monthly")
+	historyScanCmd.Flags().BoolVar(&historyJSON, "json", false, "output results in JSON format")
+
+	historyCmd.AddCommand(historyScanCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// scanHistory analyzes the project at sampled commits between since and HEAD
+func scanHistory(config *Config, since, step string) (*HistoryReport, error) {
+	repoRoot, err := gitOutput(config.ProjectPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("project is not inside a git repository: %w", err)
+	}
+
+	subPath, err := filepath.Rel(repoRoot, config.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path within repository: %w", err)
+	}
+
+	commits, err := listHistoryCommits(repoRoot, since)
+	if err != nil {
+		return nil, err
+	}
+
+	sampled, err := sampleCommits(commits, step)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "gorphanage-history-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	report := &HistoryReport{Since: since, Step: step}
+	for i, commit := range sampled {
+		if !config.OutputJSON {
+			fmt.Fprintf(os.Stderr, "⏳ [%d/%d] %s %s\n", i+1, len(sampled), commit.hash[:12], commit.date.Format("2006-01-02"))
+		}
+		report.Timeline = append(report.Timeline, analyzeAtCommit(config, repoRoot, subPath, workDir, commit))
+	}
+
+	report.DeadSince = computeDeadSince(report.Timeline)
+	return report, nil
+}
+
+// analyzeAtCommit runs the analysis in a temporary worktree checked out at commit
+func analyzeAtCommit(config *Config, repoRoot, subPath, workDir string, commit historyCommit) *HistoryPoint {
+	point := &HistoryPoint{Commit: commit.hash, Date: commit.date}
+
+	worktree := filepath.Join(workDir, commit.hash)
+	if _, err := gitOutput(repoRoot, "worktree", "add", "--detach", worktree, commit.hash); err != nil {
+		point.Error = err.Error()
+		return point
+	}
+	defer gitOutput(repoRoot, "worktree", "remove", "--force", worktree)
+
+	pointConfig := *config
+	pointConfig.ProjectPath = filepath.Join(worktree, subPath)
+	pointConfig.Verbose = false
+	pointConfig.OutputJSON = true
+
+	analyzer := NewAnalyzer(&pointConfig)
+	result, err := analyzer.Analyze()
+	if err != nil {
+		point.Error = err.Error()
+		return point
+	}
+
+	point.TotalSymbols = result.TotalSymbols
+	point.Orphans = len(result.OrphanedSymbols)
+	point.orphanKeys = make(map[string]*Symbol, len(result.OrphanedSymbols))
+	for _, orphan := range result.OrphanedSymbols {
+		// Report paths relative to the project so they are meaningful outside the worktree
+		orphan.File = analyzer.relativePath(orphan.File)
+		point.orphanKeys[analyzer.symbolKey(orphan)] = orphan
+	}
+
+	return point
+}
+
+// computeDeadSince finds, for every symbol orphaned at the last point, the start of its final dead streak
+func computeDeadSince(timeline []*HistoryPoint) []*DeadSince {
+	last := -1
+	for i := len(timeline) - 1; i >= 0; i-- {
+		if timeline[i].Error == "" {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+
+	var deadSince []*DeadSince
+	for key, symbol := range timeline[last].orphanKeys {
+		since := last
+		for i := last - 1; i >= 0; i-- {
+			if timeline[i].Error != "" {
+				continue
+			}
+			if _, orphaned := timeline[i].orphanKeys[key]; !orphaned {
+				break
+			}
+			since = i
+		}
+		deadSince = append(deadSince, &DeadSince{
+			Symbol: symbol,
+			Commit: timeline[since].Commit,
+			Date:   timeline[since].Date,
+		})
+	}
+
+	sort.Slice(deadSince, func(i, j int) bool {
+		if !deadSince[i].Date.Equal(deadSince[j].Date) {
+			return deadSince[i].Date.Before(deadSince[j].Date)
+		}
+		return deadSince[i].Symbol.Name < deadSince[j].Symbol.Name
+	})
+	return deadSince
+}
+
+// listHistoryCommits lists first-parent commits from since (inclusive) to HEAD, oldest first
+func listHistoryCommits(repoRoot, since string) ([]historyCommit, error) {
+	args := []string{"log", "--first-parent", "--reverse", "--format=%H %cI"}
+	if since != "" {
+		args = append(args, since+"..HEAD")
+	}
+
+	out, err := gitOutput(repoRoot, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	var commits []historyCommit
+	if since != "" {
+		first, err := gitOutput(repoRoot, "log", "-1", "--format=%H %cI", since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve --since revision %q: %w", since, err)
+		}
+		out = first + "\n" + out
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		hash, dateStr, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, dateStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse commit date %q: %w", dateStr, err)
+		}
+		commits = append(commits, historyCommit{hash: hash, date: date})
+	}
+
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits found")
+	}
+	return commits, nil
+}
+
+// sampleCommits keeps the last commit of every step period, always including the newest commit
+func sampleCommits(commits []historyCommit, step string) ([]historyCommit, error) {
+	var period func(time.Time) string
+	switch step {
+	case "commit":
+		return commits, nil
+	case "daily":
+		period = func(t time.Time) string { return t.UTC().Format("2006-01-02") }
+	case "weekly":
+		period = func(t time.Time) string {
+			year, week := t.UTC().ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+	case "monthly":
+		period = func(t time.Time) string { return t.UTC().Format("2006-01") }
+	default:
+		return nil, fmt.Errorf("invalid --step %q (expected commit, daily, weekly or monthly)", step)
+	}
+
+	var sampled []historyCommit
+	for i, commit := range commits {
+		if i == len(commits)-1 || period(commit.date) != period(commits[i+1].date) {
+			sampled = append(sampled, commit)
+		}
+	}
+	return sampled, nil
+}
+
+// gitOutput runs a git command in dir and returns its trimmed output
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// printHistory outputs a history report in human-readable format
+func printHistory(report *HistoryReport) {
+	fmt.Printf("\n📈 DEAD CODE TIMELINE\n\n")
+	for _, point := range report.Timeline {
+		if point.Error != "" {
+			fmt.Printf("  %s %s  ⚠️  analysis failed: %s\n", point.Date.Format("2006-01-02"), point.Commit[:12], firstLine(point.Error))
+			continue
+		}
+		fmt.Printf("  %s %s  %5d orphans / %5d symbols\n",
+			point.Date.Format("2006-01-02"), point.Commit[:12], point.Orphans, point.TotalSymbols)
+	}
+
+	if len(report.DeadSince) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🕰️  Dead since:\n")
+	for _, entry := range report.DeadSince {
+		fmt.Printf("  📍 %s (%s) - %s  dead since %s (%s)\n",
+			entry.Symbol.Name, entry.Symbol.Kind, formatPosition(entry.Symbol.File, entry.Symbol.Start),
+			entry.Date.Format("2006-01-02"), entry.Commit[:12])
+	}
+}
+
+// firstLine returns the first line of a possibly multi-line message
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
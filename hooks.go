@@ -0,0 +1,80 @@
+package main
+
+import "time"
+
+// Analysis phases reported to hooks, in order
+const (
+	PhaseLoad         = "load"         // loading and type-checking packages
+	PhaseSymbols      = "symbols"      // collecting declarations
+	PhaseReferences   = "references"   // collecting references
+	PhaseGraph        = "graph"        // building the symbol graph and call graph
+	PhaseReachability = "reachability" // finding roots and tracing what they reach
+	PhaseFindings     = "findings"     // deciding and annotating orphans
+)
+
+// Reasons a symbol is a root of the reachability analysis
+const (
+	RootReasonMain        = "main"                  // main function of a main package
+	RootReasonInit        = "init"                  // init function of a package that runs
+	RootReasonMainExport  = "main-package-export"   // exported symbol of a main package
+	RootReasonPublicAPI   = "public-api"            // exported API of a non-internal package, under module semantics
+	RootReasonInitializer = "package-initializer"   // used by a blank variable initializer of a package that runs
+	RootReasonAssertion   = "interface-assertion"   // method checked by a compile-time interface assertion
+	RootReasonKeepAlive   = "keep-alive-assertion"  // used by a blank variable initialized without effect, var _ = f
+	RootReasonRule        = "root-rule"             // matched by a configured convention root rule
+	RootReasonCallback    = "callback"              // handed to a callback registry
+	RootReasonEntryPoint  = "entry-point"           // declared as an entry point
+	RootReasonFramework   = "framework"             // registered as a handler with a detected framework
+	RootReasonReflection  = "reflection"            // exported method of a type passed to reflect, with --reflection=conservative
+	RootReasonLinkname    = "linkname"              // named by a //go:linkname directive
+	RootReasonAssembly    = "assembly"              // referenced from an assembly file
+	RootReasonCgoExport   = "cgo-export"            // exported to C with //export, or generated by cgo
+	RootReasonAllowlist   = "allowlisted-interface" // implements an allowlisted interface
+)
+
+// Hooks are callbacks an embedding application registers to follow an analysis, e.g. to
+// drive a progress UI or record telemetry. Every hook is optional and runs synchronously
+// on the goroutine calling Analyze, so it should return quickly. Hooks are not carried
+// over to a daemon.
+type Hooks struct {
+	PhaseStart    func(phase string)
+	PhaseEnd      func(phase string, elapsed time.Duration) // not called when the phase fails
+	PackageLoaded func(pkgPath string, files int)           // for every package analyzed
+	RootFound     func(symbol *Symbol, reason string)       // once per root, with the first reason found
+	Finding       func(symbol *Symbol)                      // for every orphan, once its verdict and annotations are final
+}
+
+// startPhase signals the start of an analysis phase and returns the function signaling
+// its end
+func (a *Analyzer) startPhase(phase string) func() {
+	hooks := a.config.Hooks
+	if hooks.PhaseStart != nil {
+		hooks.PhaseStart(phase)
+	}
+	start := time.Now()
+	return func() {
+		if hooks.PhaseEnd != nil {
+			hooks.PhaseEnd(phase, time.Since(start))
+		}
+	}
+}
+
+// emitRoot reports a root to the RootFound hook
+func (a *Analyzer) emitRoot(key, reason string) {
+	if a.config.Hooks.RootFound == nil {
+		return
+	}
+	if symbol, ok := a.symbols[key]; ok {
+		a.config.Hooks.RootFound(symbol, reason)
+	}
+}
+
+// emitFindings reports the orphans to the Finding hook
+func (a *Analyzer) emitFindings(orphans []*Symbol) {
+	if a.config.Hooks.Finding == nil {
+		return
+	}
+	for _, orphan := range orphans {
+		a.config.Hooks.Finding(orphan)
+	}
+}
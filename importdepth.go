@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Orders of the listed orphans, selected with --sort
+const (
+	SortFile  = "file"  // by file and line
+	SortDepth = "depth" // deepest package first, then by file and line
+)
+
+// packageLayer is the position of a project package in the project's import graph
+type packageLayer struct {
+	depth int  // 1 for a top package, 2 for the packages it imports, ...
+	leaf  bool // imports no other project package
+}
+
+// packageLayers returns the layer of every project package, computing them on first use.
+// Depths count from the nearest top package: a main package, or a package no other
+// project package imports, such as the public packages of a library or a package dead as
+// a whole. Dead code deep in shared layers is riskier to delete than at the top.
+func (a *Analyzer) packageLayers() map[string]packageLayer {
+	if a.layers != nil {
+		return a.layers
+	}
+
+	project := make(map[*packages.Package]bool, len(a.packages))
+	for _, pkg := range a.packages {
+		project[pkg] = true
+	}
+	imported := make(map[*packages.Package]bool)
+	for _, pkg := range a.packages {
+		for _, dep := range pkg.Imports {
+			if project[dep] {
+				imported[dep] = true
+			}
+		}
+	}
+
+	a.layers = make(map[string]packageLayer, len(a.packages))
+	var queue []*packages.Package
+	depths := make(map[*packages.Package]int, len(a.packages))
+	for _, pkg := range a.packages {
+		if pkg.Name == "main" || !imported[pkg] {
+			depths[pkg] = 1
+			queue = append(queue, pkg)
+		}
+	}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		leaf := true
+		for _, dep := range pkg.Imports {
+			if !project[dep] {
+				continue
+			}
+			leaf = false
+			if _, seen := depths[dep]; !seen {
+				depths[dep] = depths[pkg] + 1
+				queue = append(queue, dep)
+			}
+		}
+
+		// Several modules may provide the same package path
+		layer, seen := a.layers[pkg.PkgPath]
+		if !seen || depths[pkg] < layer.depth {
+			layer.depth = depths[pkg]
+		}
+		layer.leaf = leaf && (!seen || layer.leaf)
+		a.layers[pkg.PkgPath] = layer
+	}
+	return a.layers
+}
+
+// inDepthRange reports whether the findings of a package are within --min-import-depth
+// and --max-import-depth
+func (a *Analyzer) inDepthRange(pkgPath string) bool {
+	if a.config.MinImportDepth == 0 && a.config.MaxImportDepth == 0 {
+		return true
+	}
+	depth := a.packageLayers()[pkgPath].depth
+	return depth >= a.config.MinImportDepth && (a.config.MaxImportDepth == 0 || depth <= a.config.MaxImportDepth)
+}
+
+// showsDepth reports whether the text output annotates orphans with their import depth
+func (a *Analyzer) showsDepth() bool {
+	return a.config.Sort == SortDepth || a.config.MinImportDepth > 0 || a.config.MaxImportDepth > 0
+}
+
+// validateImportDepths checks the --min-import-depth and --max-import-depth bounds
+func validateImportDepths(minDepth, maxDepth int) error {
+	if minDepth < 0 || maxDepth < 0 {
+		return fmt.Errorf("invalid import depth bounds %d and %d (expected 0 or more)", minDepth, maxDepth)
+	}
+	if maxDepth > 0 && minDepth > maxDepth {
+		return fmt.Errorf("--min-import-depth %d is above --max-import-depth %d", minDepth, maxDepth)
+	}
+	return nil
+}
+
+// sortOrphans orders orphans as selected with --sort, leaving them alone by default
+func sortOrphans(orphans []*Symbol, order string) {
+	if order != SortFile && order != SortDepth {
+		return
+	}
+	sort.SliceStable(orphans, func(i, j int) bool {
+		oi, oj := orphans[i], orphans[j]
+		if order == SortDepth && oi.ImportDepth != oj.ImportDepth {
+			return oi.ImportDepth > oj.ImportDepth
+		}
+		if oi.File != oj.File {
+			return oi.File < oj.File
+		}
+		return oi.Start.Line < oj.Start.Line
+	})
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var introspectJSON bool
+
+// CLISchema describes the command line interface: every command with its flags and
+// defaults, and the config file settings no flag sets. Wrapper scripts and UI generators
+// read it with `gorphanage introspect --json` instead of parsing help texts.
+type CLISchema struct {
+	Version        string         `json:"version"`
+	Commit         string         `json:"commit"`
+	Built          string         `json:"built"`
+	Command        *CommandSchema `json:"command"`
+	ConfigSettings []string       `json:"config_settings"` // config file settings without a flag
+}
+
+// CommandSchema describes a command and, recursively, its subcommands
+type CommandSchema struct {
+	Name           string           `json:"name"`
+	Path           string           `json:"path"` // full invocation, e.g. "gorphanage daemon start"
+	Use            string           `json:"use"`
+	Aliases        []string         `json:"aliases,omitempty"`
+	Short          string           `json:"short,omitempty"`
+	Long           string           `json:"long,omitempty"`
+	Example        string           `json:"example,omitempty"`
+	Hidden         bool             `json:"hidden,omitempty"`
+	Runnable       bool             `json:"runnable"` // false for commands that only group subcommands
+	Flags          []*FlagSchema    `json:"flags,omitempty"`
+	InheritedFlags []string         `json:"inherited_flags,omitempty"` // persistent flags of parent commands, by name
+	Commands       []*CommandSchema `json:"commands,omitempty"`
+}
+
+// FlagSchema describes a flag of a command
+type FlagSchema struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"` // pflag value type: bool, string, int, stringSlice, ...
+	Default    string `json:"default"`
+	Usage      string `json:"usage"`
+	Persistent bool   `json:"persistent,omitempty"` // inherited by subcommands
+	Hidden     bool   `json:"hidden,omitempty"`
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+// introspectCLI builds the schema of the command tree below root
+func introspectCLI(root *cobra.Command) *CLISchema {
+	settings := make([]string, 0, len(profileOnlySettings))
+	for key := range profileOnlySettings {
+		settings = append(settings, key)
+	}
+	sort.Strings(settings)
+
+	return &CLISchema{
+		Version:        version,
+		Commit:         commit,
+		Built:          date,
+		Command:        commandSchema(root),
+		ConfigSettings: settings,
+	}
+}
+
+// commandSchema describes cmd and its subcommands, including the help and version flags
+// cobra only adds when a command runs
+func commandSchema(cmd *cobra.Command) *CommandSchema {
+	cmd.InitDefaultHelpFlag()
+	cmd.InitDefaultVersionFlag()
+
+	schema := &CommandSchema{
+		Name:     cmd.Name(),
+		Path:     cmd.CommandPath(),
+		Use:      cmd.Use,
+		Aliases:  cmd.Aliases,
+		Short:    cmd.Short,
+		Long:     cmd.Long,
+		Example:  cmd.Example,
+		Hidden:   cmd.Hidden,
+		Runnable: cmd.Runnable(),
+	}
+
+	persistent := cmd.PersistentFlags()
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		schema.Flags = append(schema.Flags, &FlagSchema{
+			Name:       flag.Name,
+			Shorthand:  flag.Shorthand,
+			Type:       flag.Value.Type(),
+			Default:    flag.DefValue,
+			Usage:      flag.Usage,
+			Persistent: persistent.Lookup(flag.Name) != nil,
+			Hidden:     flag.Hidden,
+			Deprecated: flag.Deprecated,
+		})
+	})
+	cmd.InheritedFlags().VisitAll(func(flag *pflag.Flag) {
+		schema.InheritedFlags = append(schema.InheritedFlags, flag.Name)
+	})
+
+	for _, sub := range cmd.Commands() {
+		schema.Commands = append(schema.Commands, commandSchema(sub))
+	}
+	return schema
+}
+
+// printCommandSchema prints the command tree with the flags of each command
+func printCommandSchema(schema *CommandSchema, depth int) {
+	indent := strings.Repeat("  ", depth)
+	hidden := ""
+	if schema.Hidden {
+		hidden = " (hidden)"
+	}
+	fmt.Printf("%s%s%s - %s\n", indent, schema.Path, hidden, schema.Short)
+	for _, flag := range schema.Flags {
+		name := "--" + flag.Name
+		if flag.Shorthand != "" {
+			name = "-" + flag.Shorthand + ", " + name
+		}
+		fmt.Printf("%s    %s %s (default %q)\n", indent, name, flag.Type, flag.Default)
+	}
+	for _, sub := range schema.Commands {
+		printCommandSchema(sub, depth+1)
+	}
+}
+
+var introspectCmd = &cobra.Command{
+	Use:   "introspect",
+	Short: "Describe the command line interface",
+	Long: `Describes every command with its flags, their types and defaults, and lists the config
+file settings no flag sets. With --json the schema is machine-readable, so wrapper
+scripts and UI generators can follow the command line as it grows.`,
+	Example: `  gorphanage introspect
+  gorphanage introspect --json | jq '.command.flags[].name'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema := introspectCLI(cmd.Root())
+		if introspectJSON {
+			return printJSON(schema)
+		}
+
+		fmt.Printf("Gorphanage %s\n\n", schema.Version)
+		printCommandSchema(schema.Command, 0)
+		fmt.Printf("\nConfig file settings without a flag: %s\n", strings.Join(schema.ConfigSettings, ", "))
+		return nil
+	},
+}
+
+func init() {
+	introspectCmd.Flags().BoolVar(&introspectJSON, "json", false, "output the schema in JSON format")
+	rootCmd.AddCommand(introspectCmd)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ReachableSymbol is an entry of the reachable-symbol inventory
+type ReachableSymbol struct {
+	Key string `json:"key"`
+	*Symbol
+	Root  string   `json:"root"`  // entry point the symbol was first reached from
+	Chain []string `json:"chain"` // symbols from the root to the symbol, both included
+}
+
+// ListReachable writes every reachable project symbol with the chain of symbols through
+// which it was reached from its root, as input for binary-size and attack-surface tooling
+func (a *Analyzer) ListReachable(path string) error {
+	inventory := []ReachableSymbol{}
+	for _, key := range sortedSymbolKeys(a.symbols) {
+		chain := a.reachabilityPath(key)
+		if chain == nil {
+			continue
+		}
+		inventory = append(inventory, ReachableSymbol{
+			Key:    key,
+			Symbol: a.symbols[key],
+			Root:   chain[0],
+			Chain:  chain,
+		})
+	}
+
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reachable symbols: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write reachable symbols: %w", err)
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🗺️  Listed %d reachable symbols in %s\n", len(inventory), path)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+)
+
+// isKeepAlive reports whether a blank variable declaration is a keep-alive assertion:
+// its values call nothing but conversions and builtins, so initializing it has no effect
+// and its references only keep their targets compiled in, as in var _ = legacyFunc or
+// var _ io.Writer = (*T)(nil). Function literals don't run and may call anything.
+func isKeepAlive(info *types.Info, spec *ast.ValueSpec) bool {
+	keepAlive := true
+	for _, value := range spec.Values {
+		ast.Inspect(value, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.CallExpr:
+				if tv, ok := info.Types[node.Fun]; !ok || !tv.IsType() && !tv.IsBuiltin() {
+					keepAlive = false
+				}
+			}
+			return keepAlive
+		})
+	}
+	return keepAlive
+}
+
+// recordKeepAlive records the position of the first keep-alive assertion rooting a symbol
+func (a *Analyzer) recordKeepAlive(id int32, pos token.Pos) {
+	if _, ok := a.keepAlivePos[id]; !ok {
+		a.keepAlivePos[id] = pos
+	}
+}
+
+// traceKeepAlives finds the symbols whose verdict hangs on keep-alive assertions: reachable
+// only through them or, with --ignore-assertions, orphaned only because they are ignored.
+// Each is mapped to the assertion root it is reached from.
+func (a *Analyzer) traceKeepAlives(entryPoints []int32) {
+	a.keptAlive = make(map[int32]int32)
+	if len(a.keepAliveRoots) == 0 {
+		return
+	}
+
+	with, without := a.reached, a.reached
+	if a.config.IgnoreAssertions {
+		with = a.reachFrom(append(slices.Clone(entryPoints), a.keepAliveRoots...))
+	} else {
+		keepAlive := make(map[int32]bool, len(a.keepAliveRoots))
+		for _, id := range a.keepAliveRoots {
+			keepAlive[id] = true
+		}
+		roots := slices.DeleteFunc(slices.Clone(entryPoints), func(id int32) bool { return keepAlive[id] })
+		without = a.reachFrom(roots)
+	}
+
+	for id := range with {
+		if with[id] == 0 || without[id] != 0 {
+			continue
+		}
+		root := int32(id)
+		for with[root] > 1 {
+			root = with[root] - 2
+		}
+		a.keptAlive[int32(id)] = root
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🩹 %d symbol(s) kept alive only by blank assertions\n", len(a.keptAlive))
+	}
+}
+
+// reachFrom runs a sequential BFS from roots, recording reached symbols like traverse does
+func (a *Analyzer) reachFrom(roots []int32) []int32 {
+	reached := make([]int32, a.graph.size())
+	var queue []int32
+	for _, id := range roots {
+		if reached[id] == 0 {
+			reached[id] = 1
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, target := range a.graph.successors(current) {
+			if reached[target] == 0 {
+				reached[target] = current + 2
+				queue = append(queue, target)
+			}
+		}
+	}
+	return reached
+}
+
+// keptBy returns the position of the keep-alive assertion a symbol hangs on, or nil
+func (a *Analyzer) keptBy(id int32) *RefLocation {
+	root, ok := a.keptAlive[id]
+	if !ok {
+		return nil
+	}
+	pos, ok := a.keepAlivePos[root]
+	if !ok {
+		return nil
+	}
+	position := a.fileSet.Position(pos)
+	return &RefLocation{File: position.Filename, Line: position.Line, Column: position.Column}
+}
+
+// isKeptAlive reports whether a symbol's verdict hangs on keep-alive assertions
+func (a *Analyzer) isKeptAlive(symbolKey string) bool {
+	_, ok := a.keptAlive[a.graph.ids[symbolKey]]
+	return ok
+}
+
+// keptByAssertion returns the project symbols reachable only through keep-alive
+// assertions, each with the assertion keeping it. With --ignore-assertions they are
+// reported as orphans instead.
+func (a *Analyzer) keptByAssertion() []*Symbol {
+	if a.config.IgnoreAssertions {
+		return nil
+	}
+
+	var kept []*Symbol
+	for id := range a.keptAlive {
+		symbol, ok := a.symbols[a.graph.keys[id]]
+		if !ok || a.isTestFunction(symbol.Name) || !a.inShard(symbol.Package) {
+			continue
+		}
+		reported := *symbol
+		reported.KeptBy = a.keptBy(id)
+		kept = append(kept, &reported)
+	}
+	sortOrphans(kept, SortFile)
+	return kept
+}
+
+// printKeptByAssertion lists the symbols only keep-alive assertions keep reachable
+func (a *Analyzer) printKeptByAssertion(result *AnalysisResult) {
+	if len(result.KeptByAssertion) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🩹 Kept by assertion (reachable only through var _ declarations; --ignore-assertions reports them as orphans):\n")
+	for _, symbol := range result.KeptByAssertion {
+		fmt.Printf("  📍 %s (%s) - %s", symbol.displayName(), symbol.Kind, formatPosition(a.relativePath(symbol.File), symbol.Start))
+		if symbol.KeptBy != nil {
+			fmt.Printf(" [kept by %s]", formatPosition(a.relativePath(symbol.KeptBy.File), Position{Line: symbol.KeptBy.Line, Column: symbol.KeptBy.Column}))
+		}
+		fmt.Println()
+	}
+}
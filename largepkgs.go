@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// SkippedPackage is a package left out of the analysis by --skip-packages-over
+type SkippedPackage struct {
+	Package string `json:"package"`
+	Symbols int    `json:"symbols"` // top-level declarations it would have added
+}
+
+// maxPrintedLargePackages is the number of largest packages printed in verbose mode
+const maxPrintedLargePackages = 5
+
+// countDeclarations counts the top-level named declarations of a file: the symbols it adds
+// to the analysis, struct fields aside
+func countDeclarations(file *ast.File) int {
+	count := 0
+	for _, decl := range file.Decls {
+		switch node := decl.(type) {
+		case *ast.FuncDecl:
+			count++
+		case *ast.GenDecl:
+			for _, spec := range node.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					count++
+				case *ast.ValueSpec:
+					count += len(spec.Names)
+				}
+			}
+		}
+	}
+	return count
+}
+
+// skipLargePackages counts the symbols of every loaded package and, with
+// --skip-packages-over, leaves out the packages declaring more, such as huge generated
+// enums, before they weigh on the rest of the analysis. Like an excluded package, a
+// skipped one is neither reported on nor followed: what only it uses may be reported.
+func (a *Analyzer) skipLargePackages() {
+	if a.config.SkipPackagesOver <= 0 && !(a.config.Verbose && !a.config.OutputJSON) {
+		return
+	}
+
+	// Test variants share the files of their package and count under its path
+	counts := make(map[string]int)
+	for _, pkg := range a.packages {
+		count := 0
+		for _, file := range pkg.Syntax {
+			count += countDeclarations(file)
+		}
+		counts[pkg.PkgPath] = max(counts[pkg.PkgPath], count)
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		largest := make([]string, 0, len(counts))
+		for pkgPath := range counts {
+			largest = append(largest, pkgPath)
+		}
+		sort.Slice(largest, func(i, j int) bool {
+			if counts[largest[i]] != counts[largest[j]] {
+				return counts[largest[i]] > counts[largest[j]]
+			}
+			return largest[i] < largest[j]
+		})
+		fmt.Printf("📊 Largest packages by symbol count:\n")
+		for _, pkgPath := range largest[:min(len(largest), maxPrintedLargePackages)] {
+			fmt.Printf("    %s - %d symbol(s)\n", pkgPath, counts[pkgPath])
+		}
+	}
+	if a.config.SkipPackagesOver <= 0 {
+		return
+	}
+
+	var kept []*packages.Package
+	skipped := make(map[string]bool)
+	for _, pkg := range a.packages {
+		count := counts[pkg.PkgPath]
+		if count <= a.config.SkipPackagesOver {
+			kept = append(kept, pkg)
+			continue
+		}
+		if !skipped[pkg.PkgPath] {
+			skipped[pkg.PkgPath] = true
+			a.skippedPackages = append(a.skippedPackages, &SkippedPackage{Package: pkg.PkgPath, Symbols: count})
+			if a.config.Verbose && !a.config.OutputJSON {
+				fmt.Printf("⚠️  Skipping package %s: %d symbols, over --skip-packages-over=%d\n", pkg.PkgPath, count, a.config.SkipPackagesOver)
+			}
+		}
+	}
+	a.packages = kept
+	sortSkippedPackages(a.skippedPackages)
+}
+
+// sortSkippedPackages orders skipped packages by path
+func sortSkippedPackages(skipped []*SkippedPackage) {
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].Package < skipped[j].Package })
+}
+
+// printSkippedPackages warns about the packages --skip-packages-over left out
+func (a *Analyzer) printSkippedPackages(result *AnalysisResult) {
+	if len(result.SkippedPackages) == 0 {
+		return
+	}
+
+	fmt.Printf("\n⚠️  Skipped packages (over --skip-packages-over; not analyzed, so orphans only they use may be false positives):\n")
+	for _, pkg := range result.SkippedPackages {
+		fmt.Printf("  • %s - %d symbol(s)\n", pkg.Package, pkg.Symbols)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	// asmSymbolRef matches a Go symbol in assembly: ·name for the file's own package, or
+	// path∕to∕pkg·name for another one, with the middle dot and division slash Go's
+	// assembler uses for the dot and slashes of qualified names. Static symbols (name<>)
+	// are local to the assembly file.
+	asmSymbolRef = regexp.MustCompile(`([\pL\pN_.\x{2215}]*)\x{00B7}([\pL\pN_]+)(<>)?`)
+
+	// asmHeaderRef matches the names go_asm.h defines for a package: const_name for a
+	// constant, Type__size for the size of a type and Type_field for a field offset
+	asmHeaderRef = regexp.MustCompile(`\b(?:const_([\pL\pN_]+)|([\pL][\pL\pN]*)_[\pL\pN_]+)\b`)
+)
+
+// findLinknames records the symbols named by the //go:linkname directives of a file. A
+// local function or variable pushed with //go:linkname local [target] is referenced by
+// the linker from another package, usually the runtime or a dependency; a project
+// symbol pulled in as the target of another package's directive is referenced from there.
+// Neither reference shows in Go source.
+func (a *Analyzer) findLinknames(pkg *packages.Package, file *ast.File) {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			directive, ok := strings.CutPrefix(comment.Text, "//go:linkname ")
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(directive)
+			if len(fields) == 0 {
+				continue
+			}
+			if obj := pkg.Types.Scope().Lookup(fields[0]); obj != nil {
+				if id, ok := a.objectID(obj); ok {
+					a.linknamed[id] = true
+				}
+			}
+			if len(fields) > 1 {
+				if id, ok := a.linknameTarget(fields[1]); ok {
+					a.linknamed[id] = true
+				}
+			}
+		}
+	}
+}
+
+// linknameTarget resolves the target of a //go:linkname directive, importpath.name or
+// importpath.Type.Method (also written importpath.(*Type).Method), to a project symbol
+func (a *Analyzer) linknameTarget(target string) (int32, bool) {
+	slash := strings.LastIndex(target, "/")
+	dot := strings.Index(target[slash+1:], ".")
+	if dot < 0 {
+		return 0, false
+	}
+	pkgPath, name := target[:slash+1+dot], target[slash+2+dot:]
+	if !a.projectPkgs[pkgPath] {
+		return 0, false
+	}
+
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+	kinds := []string{"function", "variable"}
+	if strings.Contains(name, ".") {
+		kinds = []string{"method"}
+	}
+	for _, kind := range kinds {
+		if id, ok := a.graph.ids[a.getSymbolKey(pkgPath, name, kind)]; ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// findAssemblyReferences records the Go symbols the assembly files of a package reference,
+// such as CALL ·helper(SB) or MOVQ $·table(SB), AX, and the constants and types whose
+// values, sizes or field offsets they use through go_asm.h. The symbol an instruction
+// block defines (TEXT ·name(SB)) is not a reference: its Go declaration is used only if
+// Go code calls it.
+func (a *Analyzer) findAssemblyReferences(pkg *packages.Package) error {
+	keyPath := a.keyPath(pkg.Types)
+	keep := func(pkgPath, name string, kinds ...string) {
+		for _, kind := range kinds {
+			if id, ok := a.graph.ids[a.getSymbolKey(pkgPath, name, kind)]; ok {
+				a.assemblyRefs[id] = true
+			}
+		}
+	}
+
+	for _, path := range pkg.OtherFiles {
+		if filepath.Ext(path) != ".s" || a.walkedFiles[path] {
+			continue
+		}
+		a.walkedFiles[path] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read assembly file: %w", err)
+		}
+		header := bytes.Contains(data, []byte(`"go_asm.h"`))
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line, _, _ := strings.Cut(scanner.Text(), "//")
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			defines := strings.HasPrefix(line, "TEXT") || strings.HasPrefix(line, "GLOBL") || strings.HasPrefix(line, "DATA")
+			for i, match := range asmSymbolRef.FindAllStringSubmatch(line, -1) {
+				if match[3] != "" || (defines && i == 0) {
+					continue
+				}
+				pkgPath := keyPath
+				if match[1] != "" {
+					pkgPath = strings.ReplaceAll(match[1], "∕", "/")
+				}
+				keep(pkgPath, match[2], "function", "variable")
+			}
+
+			if !header {
+				continue
+			}
+			for _, match := range asmHeaderRef.FindAllStringSubmatch(line, -1) {
+				if match[1] != "" {
+					keep(keyPath, match[1], "constant")
+				} else {
+					keep(keyPath, match[2], "type")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// linknameRoots returns the keys of the symbols named by //go:linkname directives
+func (a *Analyzer) linknameRoots() []string {
+	return a.linkedRoots(a.linknamed, "🔗 %d symbol(s) named by //go:linkname directives kept alive\n")
+}
+
+// assemblyRoots returns the keys of the symbols referenced from assembly files
+func (a *Analyzer) assemblyRoots() []string {
+	return a.linkedRoots(a.assemblyRefs, "⚙️  %d symbol(s) referenced from assembly kept alive\n")
+}
+
+// linkedRoots returns the sorted keys of ids, reporting their number in verbose mode
+func (a *Analyzer) linkedRoots(ids map[int32]bool, message string) []string {
+	var roots []string
+	for id := range ids {
+		roots = append(roots, a.graph.keys[id])
+	}
+	sort.Strings(roots)
+
+	if a.config.Verbose && !a.config.OutputJSON && len(roots) > 0 {
+		fmt.Printf(message, len(roots))
+	}
+
+	return roots
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,11 +18,61 @@ var (
 	date    = "unknown"
 
 	// CLI flags
-	outputsJSON  bool
-	verbose      bool
-	configFile   string
-	exclude      []string
-	includeTests bool
+	outputsJSON     bool
+	versionJSON     bool
+	verbose         bool
+	configFile      string
+	profileName     string
+	exclude         []string
+	includeTests    bool
+	includeReplaced bool
+	includeTestdata bool
+	includeTools    bool
+	entryFuncs      []string
+	frameworks      []string
+	exportDB        string
+	coverProfile    string
+	importFindings  string
+	pprofProfiles   []string
+	dumpGraph       string
+	listReachable   string
+	sizeEstimate    bool
+	withReferences  bool
+	codeActions     bool
+	writeTodos      bool
+	postURL         string
+	baselineFile    string
+	failOn          string
+	failOnNew       bool
+	probe           bool
+	probeSamples    int
+	byAuthor        bool
+	stream          bool
+	semantics       string
+	roots           string
+	precision       string
+	reflection      string
+	ignoreAsserts   bool
+	mainUnexported  string
+	generatedPolicy string
+	shard           string
+	buildTags       []string
+	platforms       []string
+	buildMatrix     []string
+	perTarget       bool
+	recursive       bool
+	useDaemon       bool
+	componentsFile  string
+	fields          bool
+	results         bool
+	wrappers        bool
+	suggestUnexport bool
+	wrapperCallers  int
+	maxFindings     int
+	skipOver        int
+	minImportDepth  int
+	maxImportDepth  int
+	sortOrder       string
 )
 
 func main() {
@@ -54,6 +105,9 @@ genuinely unreachable, helping you clean up dead code with confidence.`,
   # Verbose output with detailed progress
   gorphanage --verbose .`,
 	Args: cobra.ExactArgs(1),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyProfile(cmd, viper.GetString("profile"))
+	},
 	RunE: runAnalysis,
 }
 
@@ -63,19 +117,118 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default is $HOME/.gorphanage.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "apply a named profile from the config file's profiles section")
 
 	// Analysis flags
 	rootCmd.Flags().BoolVar(&outputsJSON, "json", false, "output results in JSON format")
 	rootCmd.Flags().StringSliceVarP(&exclude, "exclude", "e", []string{}, "exclude packages matching these patterns")
 	rootCmd.Flags().BoolVar(&includeTests, "include-tests", false, "include test files in analysis")
+	rootCmd.Flags().StringVar(&exportDB, "export-db", "", "write symbols, references, edges and verdicts to a SQLite database")
+	rootCmd.Flags().StringVar(&baselineFile, "baseline", "", "baseline file with finding states (default is <project>/"+DefaultBaselineFile+" if present)")
+	rootCmd.Flags().StringVar(&failOn, "fail-on", "none", "exit non-zero when findings exist: none, new or any")
+	rootCmd.Flags().BoolVar(&failOnNew, "fail-on-new", false, "report only the orphans missing from the baseline and exit non-zero when there are any (see gorphanage baseline write)")
+	rootCmd.Flags().BoolVar(&probe, "probe", false, "verify a sample of orphans by re-type-checking the project without them")
+	rootCmd.Flags().IntVar(&probeSamples, "probe-samples", 20, "maximum number of orphans verified by --probe")
+	rootCmd.Flags().BoolVar(&byAuthor, "by-author", false, "break orphans down by the author who last touched them (heuristic, uses git blame)")
+	rootCmd.Flags().StringVar(&componentsFile, "components", "", "component manifest grouping results by directory prefixes (default is <project>/"+DefaultComponentsFile+" if present, else git submodules)")
+	rootCmd.Flags().StringVar(&semantics, "semantics", SemanticsAuto, "root semantics: binary (reachable from main packages), module (exported API is used) or auto")
+	rootCmd.Flags().StringVar(&roots, "roots", "", "roots by name: exported (the exported API of non-internal packages, for libraries; same as --semantics module), main (same as --semantics binary) or auto")
+	rootCmd.Flags().StringVar(&precision, "precision", PrecisionReferences, "how calls are resolved: references (any reference keeps a function), rta (SSA call graph, slower) or vta (RTA refined by type propagation, slowest)")
+	rootCmd.Flags().StringVar(&reflection, "reflection", ReflectionNone, "how reflection is accounted for: none, or conservative (keep exported methods of types passed to reflect, lower the confidence of exported methods in packages importing it)")
+	rootCmd.Flags().BoolVar(&ignoreAsserts, "ignore-assertions", false, "treat keep-alive assertions (var _ = f, var _ I = (*T)(nil)) as no references, reporting what only they keep alive as orphans")
+	rootCmd.Flags().StringVar(&mainUnexported, "main-unexported", MainUnexportedReport, "unexported symbols of main packages that main() never reaches: report them as findings, or group them apart as helpers kept on purpose")
+	rootCmd.Flags().StringVar(&generatedPolicy, "generated", GeneratedInclude, "orphans of files with a \"Code generated ... DO NOT EDIT.\" header: include them with the other orphans, exclude them, or list them in a separate section")
+	rootCmd.Flags().StringVar(&shard, "shard", "", "report only the packages of shard index/count, e.g. 3/8, while still loading the whole project")
+	rootCmd.Flags().IntVar(&maxFindings, "max-findings", 0, "list at most this many orphans in detail, keeping totals and per-package counts complete (0 lists all)")
+	rootCmd.Flags().IntVar(&skipOver, "skip-packages-over", 0, "leave out packages declaring more than this many symbols, such as huge generated enums, with a warning in the results (0 for no limit)")
+	rootCmd.Flags().IntVar(&minImportDepth, "min-import-depth", 0, "report only orphans of packages at least this deep in the import graph (1 is a main or other top package)")
+	rootCmd.Flags().IntVar(&maxImportDepth, "max-import-depth", 0, "report only orphans of packages at most this deep in the import graph (0 for no limit)")
+	rootCmd.Flags().StringVar(&sortOrder, "sort", "", "order of the listed orphans: file, or depth (deepest package first)")
+	rootCmd.Flags().BoolVar(&stream, "stream", false, "print the findings of each package as soon as its verdicts are final (text output)")
+	rootCmd.Flags().StringVar(&dumpGraph, "dump-graph", "", "write every symbol and edge of the symbol graph to a JSON lines file")
+	rootCmd.Flags().StringVar(&listReachable, "list-reachable", "", "write every reachable symbol with its chain from a root to a JSON file")
+	rootCmd.Flags().BoolVar(&writeTodos, "write-todos", false, "write a DEADCODE.md checklist of its orphans into each package directory")
+	rootCmd.Flags().StringVar(&postURL, "post", "", "POST the JSON result to this URL after the run, retrying with backoff; "+PostTokenEnv+" sets a bearer token")
+	rootCmd.Flags().BoolVar(&sizeEstimate, "size-estimate", false, "estimate the binary size of orphan clusters and rank them by shipped-size impact")
+	rootCmd.Flags().BoolVar(&fields, "fields", false, "report struct fields that are never read (tagged fields and types passed to reflection are left out)")
+	rootCmd.Flags().BoolVar(&results, "results", false, "report function results that every call site ignores (functions used as values and interface methods are left out)")
+	rootCmd.Flags().BoolVar(&wrappers, "wrappers", false, "report trivial wrappers: functions whose body is only a type conversion or field copy, with few callers")
+	rootCmd.Flags().BoolVar(&suggestUnexport, "suggest-unexport", false, "report exported symbols only referenced from their own package, which could be unexported")
+	rootCmd.Flags().IntVar(&wrapperCallers, "wrapper-max-callers", 1, "maximum number of callers of a function reported by --wrappers")
+	rootCmd.Flags().BoolVar(&withReferences, "with-references", false, "list every reachable symbol's references with their position and referencing symbol in the JSON output")
+	rootCmd.Flags().BoolVar(&codeActions, "code-actions", false, "attach editor quick fixes to every orphan in the JSON output: LSP edits deleting its declaration, and the command marking it wontfix in the baseline")
+	rootCmd.Flags().StringVar(&coverProfile, "coverprofile", "", "annotate orphans with coverage from a Go coverage profile")
+	rootCmd.Flags().StringVar(&importFindings, "import-findings", "", "cross-check orphans with the unused findings of staticcheck -f json or golangci-lint JSON output, reporting agreements and disagreements")
+	rootCmd.Flags().StringSliceVar(&pprofProfiles, "pprof", []string{}, "flag orphans that appear in these CPU/heap pprof profiles")
+	rootCmd.Flags().StringSliceVar(&buildTags, "tags", []string{}, "build tags to load packages with, like go build -tags, e.g. integration,e2e")
+	rootCmd.Flags().StringSliceVar(&platforms, "platforms", []string{}, "os/arch platforms build constraints must be satisfiable on (default: every known platform)")
+	rootCmd.Flags().StringSliceVar(&buildMatrix, "build-matrix", []string{}, "analyze each os/arch[+tag...] configuration, e.g. linux/amd64,windows/amd64+integration, and report only symbols orphaned in all of them")
+	rootCmd.Flags().BoolVar(&perTarget, "per-target-report", false, "with --build-matrix, also list the orphans of each configuration with the configurations they are dead, reachable or not built on")
+	rootCmd.Flags().BoolVar(&recursive, "recursive", false, "analyze every module below the project path on its own and merge the results into one report broken down by module")
+	rootCmd.Flags().BoolVar(&includeReplaced, "include-replaced", false, "analyze modules replaced with local directories as project code")
+	rootCmd.Flags().BoolVar(&includeTestdata, "include-testdata", false, "analyze packages below testdata directories, skipped by default")
+	rootCmd.Flags().BoolVar(&includeTools, "include-tools", false, "analyze packages below internal/tools, skipped by default")
+	rootCmd.Flags().StringSliceVar(&entryFuncs, "entry-func", []string{}, "treat these functions or methods (pkg/path.Func or pkg/path.Type.Method) as roots, e.g. framework handlers")
+	rootCmd.Flags().StringSliceVar(&frameworks, "frameworks", []string{}, "framework detectors keeping registered handlers alive: net/http, grpc, cobra, wire, fx, dig or none (default: all)")
+	rootCmd.Flags().BoolVar(&useDaemon, "daemon", false, "run the analysis in a background daemon that keeps the project loaded, starting it if needed")
 
 	// Bind flags to viper
 	viper.BindPFlag("json", rootCmd.Flags().Lookup("json"))
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
 	viper.BindPFlag("exclude", rootCmd.Flags().Lookup("exclude"))
 	viper.BindPFlag("include-tests", rootCmd.Flags().Lookup("include-tests"))
+	viper.BindPFlag("include-replaced", rootCmd.Flags().Lookup("include-replaced"))
+	viper.BindPFlag("include-testdata", rootCmd.Flags().Lookup("include-testdata"))
+	viper.BindPFlag("include-tools", rootCmd.Flags().Lookup("include-tools"))
+	viper.BindPFlag("entry-func", rootCmd.Flags().Lookup("entry-func"))
+	viper.BindPFlag("frameworks", rootCmd.Flags().Lookup("frameworks"))
+	viper.BindPFlag("export-db", rootCmd.Flags().Lookup("export-db"))
+	viper.BindPFlag("dump-graph", rootCmd.Flags().Lookup("dump-graph"))
+	viper.BindPFlag("list-reachable", rootCmd.Flags().Lookup("list-reachable"))
+	viper.BindPFlag("size-estimate", rootCmd.Flags().Lookup("size-estimate"))
+	viper.BindPFlag("with-references", rootCmd.Flags().Lookup("with-references"))
+	viper.BindPFlag("code-actions", rootCmd.Flags().Lookup("code-actions"))
+	viper.BindPFlag("fields", rootCmd.Flags().Lookup("fields"))
+	viper.BindPFlag("results", rootCmd.Flags().Lookup("results"))
+	viper.BindPFlag("wrappers", rootCmd.Flags().Lookup("wrappers"))
+	viper.BindPFlag("suggest-unexport", rootCmd.Flags().Lookup("suggest-unexport"))
+	viper.BindPFlag("wrapper-max-callers", rootCmd.Flags().Lookup("wrapper-max-callers"))
+	viper.BindPFlag("write-todos", rootCmd.Flags().Lookup("write-todos"))
+	viper.BindPFlag("post", rootCmd.Flags().Lookup("post"))
+	viper.BindPFlag("baseline", rootCmd.Flags().Lookup("baseline"))
+	viper.BindPFlag("fail-on", rootCmd.Flags().Lookup("fail-on"))
+	viper.BindPFlag("fail-on-new", rootCmd.Flags().Lookup("fail-on-new"))
+	viper.BindPFlag("probe", rootCmd.Flags().Lookup("probe"))
+	viper.BindPFlag("probe-samples", rootCmd.Flags().Lookup("probe-samples"))
+	viper.BindPFlag("by-author", rootCmd.Flags().Lookup("by-author"))
+	viper.BindPFlag("components", rootCmd.Flags().Lookup("components"))
+	viper.BindPFlag("semantics", rootCmd.Flags().Lookup("semantics"))
+	viper.BindPFlag("roots", rootCmd.Flags().Lookup("roots"))
+	viper.BindPFlag("precision", rootCmd.Flags().Lookup("precision"))
+	viper.BindPFlag("reflection", rootCmd.Flags().Lookup("reflection"))
+	viper.BindPFlag("ignore-assertions", rootCmd.Flags().Lookup("ignore-assertions"))
+	viper.BindPFlag("main-unexported", rootCmd.Flags().Lookup("main-unexported"))
+	viper.BindPFlag("generated", rootCmd.Flags().Lookup("generated"))
+	viper.BindPFlag("shard", rootCmd.Flags().Lookup("shard"))
+	viper.BindPFlag("stream", rootCmd.Flags().Lookup("stream"))
+	viper.BindPFlag("max-findings", rootCmd.Flags().Lookup("max-findings"))
+	viper.BindPFlag("skip-packages-over", rootCmd.Flags().Lookup("skip-packages-over"))
+	viper.BindPFlag("min-import-depth", rootCmd.Flags().Lookup("min-import-depth"))
+	viper.BindPFlag("max-import-depth", rootCmd.Flags().Lookup("max-import-depth"))
+	viper.BindPFlag("sort", rootCmd.Flags().Lookup("sort"))
+	viper.BindPFlag("coverprofile", rootCmd.Flags().Lookup("coverprofile"))
+	viper.BindPFlag("import-findings", rootCmd.Flags().Lookup("import-findings"))
+	viper.BindPFlag("pprof", rootCmd.Flags().Lookup("pprof"))
+	viper.BindPFlag("tags", rootCmd.Flags().Lookup("tags"))
+	viper.BindPFlag("platforms", rootCmd.Flags().Lookup("platforms"))
+	viper.BindPFlag("build-matrix", rootCmd.Flags().Lookup("build-matrix"))
+	viper.BindPFlag("per-target-report", rootCmd.Flags().Lookup("per-target-report"))
+	viper.BindPFlag("recursive", rootCmd.Flags().Lookup("recursive"))
+	viper.BindPFlag("daemon", rootCmd.Flags().Lookup("daemon"))
 
 	// Add subcommands
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "output version information in JSON format")
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
 }
@@ -108,23 +261,23 @@ func initConfig() {
 }
 
 func runAnalysis(cmd *cobra.Command, args []string) error {
-	projectPath := args[0]
-
-	// Resolve absolute path
-	absPath, err := filepath.Abs(projectPath)
+	config, err := configFromViper(args[0])
 	if err != nil {
-		return fmt.Errorf("failed to resolve project path: %w", err)
+		return err
 	}
 
-	// Create config from flags and viper settings
-	config := &Config{
-		ProjectPath:  absPath,
-		OutputJSON:   viper.GetBool("json"),
-		Verbose:      viper.GetBool("verbose"),
-		Exclude:      viper.GetStringSlice("exclude"),
-		IncludeTests: viper.GetBool("include-tests"),
+	switch config.FailOn {
+	case "none", "new", "any":
+	default:
+		return fmt.Errorf("invalid --fail-on %q (expected none, new or any)", config.FailOn)
+	}
+	if flag := recursiveIncompatible(config); flag != "" {
+		return fmt.Errorf("%s cannot be combined with --recursive: no single analysis holds every module", flag)
 	}
 
+	// Usage help is noise once the arguments are known to be valid
+	cmd.SilenceUsage = true
+
 	if config.Verbose && !config.OutputJSON {
 		fmt.Printf("🔍 Analyzing project at: %s\n", config.ProjectPath)
 		if len(config.Exclude) > 0 {
@@ -133,24 +286,357 @@ func runAnalysis(cmd *cobra.Command, args []string) error {
 		if config.IncludeTests {
 			fmt.Printf("🧪 Including test files in analysis\n")
 		}
+		if config.IncludeReplaced {
+			fmt.Printf("🔗 Including locally replaced modules in analysis\n")
+		}
 	}
 
-	// Create and run analyzer
-	analyzer := NewAnalyzer(config)
-	result, err := analyzer.Analyze()
+	if config.Daemon {
+		if flag := daemonIncompatible(config); flag != "" {
+			if config.Verbose && !config.OutputJSON {
+				fmt.Printf("👻 %s needs a local analysis, not using the daemon\n", flag)
+			}
+		} else {
+			return runDaemonAnalysis(config)
+		}
+	}
+
+	analyzer, result, err := analyze(config)
 	if err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		return err
+	}
+	if config.FailOnNew {
+		onlyNewFindings(result)
+	}
+
+	if config.ExportDB != "" {
+		if err := analyzer.ExportDB(config.ExportDB); err != nil {
+			return fmt.Errorf("exporting symbol database: %w", err)
+		}
+	}
+
+	if config.DumpGraph != "" {
+		if err := analyzer.DumpGraph(config.DumpGraph); err != nil {
+			return fmt.Errorf("dumping symbol graph: %w", err)
+		}
+	}
+
+	if config.ListReachable != "" {
+		if err := analyzer.ListReachable(config.ListReachable); err != nil {
+			return fmt.Errorf("listing reachable symbols: %w", err)
+		}
+	}
+
+	if config.WriteTodos {
+		if err := analyzer.WriteTodos(result); err != nil {
+			return fmt.Errorf("writing todo lists: %w", err)
+		}
 	}
 
 	// Output results
 	if config.OutputJSON {
-		return outputJSON(result)
+		if err := outputJSON(truncateResult(result, config.MaxFindings)); err != nil {
+			return err
+		}
+	} else {
+		analyzer.PrintResults(result)
 	}
 
-	analyzer.PrintResults(result)
+	// A stable one-line summary that scripts can grep regardless of the output format
+	printSummaryLine(os.Stderr, result)
+
+	if err := postRun(config, result); err != nil {
+		return err
+	}
+
+	return checkFailOn(config.FailOn, result)
+}
+
+// checkFailOn turns findings into an error according to the --fail-on policy
+func checkFailOn(failOn string, result *AnalysisResult) error {
+	switch failOn {
+	case "any":
+		if len(result.OrphanedSymbols) > 0 {
+			return fmt.Errorf("%d orphaned symbol(s) found", len(result.OrphanedSymbols))
+		}
+	case "new":
+		if newCount := countNew(result); newCount > 0 {
+			return fmt.Errorf("%d new orphaned symbol(s) found", newCount)
+		}
+	}
 	return nil
 }
 
+// configFromViper creates the analysis config from flags, config file and environment
+func configFromViper(projectPath string) (*Config, error) {
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path: %w", err)
+	}
+	absPath = normalizePath(absPath)
+
+	var rootRules []RootRule
+	if err := viper.UnmarshalKey("root-rules", &rootRules); err != nil {
+		return nil, fmt.Errorf("invalid root-rules configuration: %w", err)
+	}
+	for i, rule := range rootRules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid root-rules entry %d: %w", i+1, err)
+		}
+	}
+
+	var callbackRegistries []CallbackRegistry
+	if err := viper.UnmarshalKey("callback-registries", &callbackRegistries); err != nil {
+		return nil, fmt.Errorf("invalid callback-registries configuration: %w", err)
+	}
+	for i, registry := range callbackRegistries {
+		if err := registry.validate(); err != nil {
+			return nil, fmt.Errorf("invalid callback-registries entry %d: %w", i+1, err)
+		}
+	}
+
+	var stringRegistries []StringRegistry
+	if err := viper.UnmarshalKey("string-registries", &stringRegistries); err != nil {
+		return nil, fmt.Errorf("invalid string-registries configuration: %w", err)
+	}
+	for i, registry := range stringRegistries {
+		if err := registry.validate(); err != nil {
+			return nil, fmt.Errorf("invalid string-registries entry %d: %w", i+1, err)
+		}
+	}
+
+	var entryPoints []EntryPoint
+	if err := viper.UnmarshalKey("entry-points", &entryPoints); err != nil {
+		return nil, fmt.Errorf("invalid entry-points configuration: %w", err)
+	}
+	for i, entry := range entryPoints {
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("invalid entry-points entry %d: %w", i+1, err)
+		}
+	}
+	for _, name := range viper.GetStringSlice("entry-func") {
+		entry := EntryPoint{Func: name}
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("invalid --entry-func: %w", err)
+		}
+		entryPoints = append(entryPoints, entry)
+	}
+
+	// nil runs every built-in detector, "none" alone runs none
+	var frameworks []string
+	if names := viper.GetStringSlice("frameworks"); len(names) > 0 {
+		frameworks = []string{}
+		for _, name := range names {
+			if name == "none" {
+				continue
+			}
+			if err := validateFramework(name); err != nil {
+				return nil, fmt.Errorf("invalid --frameworks: %w", err)
+			}
+			frameworks = append(frameworks, name)
+		}
+	}
+
+	interfaceAllowlist := viper.GetStringSlice("interface-allowlist")
+	for _, name := range interfaceAllowlist {
+		if err := validateAllowlistedInterface(name); err != nil {
+			return nil, fmt.Errorf("invalid interface-allowlist entry: %w", err)
+		}
+	}
+
+	wellKnownMethods := viper.GetStringSlice("well-known-methods")
+	for _, name := range wellKnownMethods {
+		if err := validateWellKnownMethod(name); err != nil {
+			return nil, fmt.Errorf("invalid well-known-methods entry: %w", err)
+		}
+	}
+
+	semantics := viper.GetString("semantics")
+	switch semantics {
+	case SemanticsAuto, SemanticsBinary, SemanticsModule:
+	default:
+		return nil, fmt.Errorf("invalid --semantics %q (expected binary, module or auto)", semantics)
+	}
+	if roots := viper.GetString("roots"); roots != "" {
+		rootSemantics, ok := rootModes[roots]
+		if !ok {
+			return nil, fmt.Errorf("invalid --roots %q (expected exported, main or auto)", roots)
+		}
+		if semantics != SemanticsAuto && semantics != rootSemantics {
+			return nil, fmt.Errorf("--roots %s contradicts --semantics %s", roots, semantics)
+		}
+		semantics = rootSemantics
+	}
+
+	switch viper.GetString("precision") {
+	case PrecisionReferences, PrecisionRTA, PrecisionVTA:
+	default:
+		return nil, fmt.Errorf("invalid --precision %q (expected references, rta or vta)", viper.GetString("precision"))
+	}
+
+	switch viper.GetString("reflection") {
+	case ReflectionNone, ReflectionConservative:
+	default:
+		return nil, fmt.Errorf("invalid --reflection %q (expected none or conservative)", viper.GetString("reflection"))
+	}
+
+	switch viper.GetString("main-unexported") {
+	case MainUnexportedReport, MainUnexportedGroup:
+	default:
+		return nil, fmt.Errorf("invalid --main-unexported %q (expected report or group)", viper.GetString("main-unexported"))
+	}
+
+	switch viper.GetString("generated") {
+	case GeneratedInclude, GeneratedExclude, GeneratedSeparate:
+	default:
+		return nil, fmt.Errorf("invalid --generated %q (expected include, exclude or separate)", viper.GetString("generated"))
+	}
+
+	for _, tag := range viper.GetStringSlice("tags") {
+		if err := validateBuildTag(tag); err != nil {
+			return nil, err
+		}
+	}
+	for _, platform := range viper.GetStringSlice("platforms") {
+		if err := validatePlatform(platform); err != nil {
+			return nil, err
+		}
+	}
+	for _, spec := range viper.GetStringSlice("build-matrix") {
+		if _, err := parseBuildTarget(spec); err != nil {
+			return nil, err
+		}
+	}
+	if len(viper.GetStringSlice("build-matrix")) > 0 && viper.GetBool("stream") {
+		return nil, fmt.Errorf("--stream cannot be combined with --build-matrix: orphans are known once every configuration is analyzed")
+	}
+	if viper.GetBool("per-target-report") && len(viper.GetStringSlice("build-matrix")) == 0 {
+		return nil, fmt.Errorf("--per-target-report requires --build-matrix")
+	}
+
+	failOnPolicy := viper.GetString("fail-on")
+	if viper.GetBool("fail-on-new") {
+		if failOnPolicy != "none" && failOnPolicy != "new" {
+			return nil, fmt.Errorf("--fail-on-new cannot be combined with --fail-on %s", failOnPolicy)
+		}
+		if viper.GetBool("stream") && !viper.GetBool("json") {
+			return nil, fmt.Errorf("--fail-on-new cannot be combined with --stream: findings are printed before the baseline is applied")
+		}
+		failOnPolicy = "new"
+	}
+
+	if endpoint := viper.GetString("post"); endpoint != "" {
+		if err := validatePostURL(endpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if viper.GetInt("max-findings") < 0 {
+		return nil, fmt.Errorf("invalid --max-findings %d (expected 0 or more)", viper.GetInt("max-findings"))
+	}
+	if viper.GetInt("skip-packages-over") < 0 {
+		return nil, fmt.Errorf("invalid --skip-packages-over %d (expected 0 or more)", viper.GetInt("skip-packages-over"))
+	}
+	if viper.GetInt("wrapper-max-callers") < 1 {
+		return nil, fmt.Errorf("invalid --wrapper-max-callers %d (expected 1 or more)", viper.GetInt("wrapper-max-callers"))
+	}
+	if err := validateImportDepths(viper.GetInt("min-import-depth"), viper.GetInt("max-import-depth")); err != nil {
+		return nil, err
+	}
+	switch viper.GetString("sort") {
+	case "", SortFile, SortDepth:
+	default:
+		return nil, fmt.Errorf("invalid --sort %q (expected file or depth)", viper.GetString("sort"))
+	}
+
+	var shardIndex, shardCount int
+	if spec := viper.GetString("shard"); spec != "" {
+		if shardIndex, shardCount, err = parseShard(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Config{
+		ProjectPath:        absPath,
+		OutputJSON:         viper.GetBool("json"),
+		Verbose:            viper.GetBool("verbose"),
+		Exclude:            viper.GetStringSlice("exclude"),
+		IncludeTests:       viper.GetBool("include-tests"),
+		IncludeReplaced:    viper.GetBool("include-replaced"),
+		IncludeTestdata:    viper.GetBool("include-testdata"),
+		IncludeTools:       viper.GetBool("include-tools"),
+		ExportDB:           viper.GetString("export-db"),
+		DumpGraph:          viper.GetString("dump-graph"),
+		ListReachable:      viper.GetString("list-reachable"),
+		SizeEstimate:       viper.GetBool("size-estimate"),
+		WithReferences:     viper.GetBool("with-references"),
+		CodeActions:        viper.GetBool("code-actions"),
+		Fields:             viper.GetBool("fields"),
+		Results:            viper.GetBool("results"),
+		Wrappers:           viper.GetBool("wrappers"),
+		SuggestUnexport:    viper.GetBool("suggest-unexport"),
+		WrapperMaxCallers:  viper.GetInt("wrapper-max-callers"),
+		WriteTodos:         viper.GetBool("write-todos"),
+		Post:               viper.GetString("post"),
+		CoverProfile:       viper.GetString("coverprofile"),
+		ImportFindings:     viper.GetString("import-findings"),
+		PprofProfiles:      viper.GetStringSlice("pprof"),
+		BaselineFile:       viper.GetString("baseline"),
+		FailOn:             failOnPolicy,
+		FailOnNew:          viper.GetBool("fail-on-new"),
+		Probe:              viper.GetBool("probe"),
+		ProbeSamples:       viper.GetInt("probe-samples"),
+		RootRules:          rootRules,
+		CallbackRegistries: callbackRegistries,
+		StringRegistries:   stringRegistries,
+		EntryPoints:        entryPoints,
+		InterfaceAllowlist: interfaceAllowlist,
+		WellKnownMethods:   wellKnownMethods,
+		Frameworks:         frameworks,
+		ByAuthor:           viper.GetBool("by-author"),
+		ComponentsFile:     viper.GetString("components"),
+		Stream:             viper.GetBool("stream"),
+		MaxFindings:        viper.GetInt("max-findings"),
+		SkipPackagesOver:   viper.GetInt("skip-packages-over"),
+		MinImportDepth:     viper.GetInt("min-import-depth"),
+		MaxImportDepth:     viper.GetInt("max-import-depth"),
+		Sort:               viper.GetString("sort"),
+		Semantics:          semantics,
+		Precision:          viper.GetString("precision"),
+		Reflection:         viper.GetString("reflection"),
+		IgnoreAssertions:   viper.GetBool("ignore-assertions"),
+		MainUnexported:     viper.GetString("main-unexported"),
+		Generated:          viper.GetString("generated"),
+		Tags:               viper.GetStringSlice("tags"),
+		Platforms:          viper.GetStringSlice("platforms"),
+		BuildMatrix:        viper.GetStringSlice("build-matrix"),
+		PerTargetReport:    viper.GetBool("per-target-report"),
+		Recursive:          viper.GetBool("recursive"),
+		ShardIndex:         shardIndex,
+		ShardCount:         shardCount,
+		Daemon:             viper.GetBool("daemon"),
+	}, nil
+}
+
+// analyze creates and runs an analyzer, surfacing load failures as structured errors
+func analyze(config *Config) (*Analyzer, *AnalysisResult, error) {
+	analyzer := NewAnalyzer(config)
+	result, err := analyzer.Analyze()
+	if err != nil {
+		var loadErr *LoadError
+		if errors.As(err, &loadErr) {
+			if config.OutputJSON {
+				if jsonErr := outputJSONError(loadErr); jsonErr != nil {
+					return nil, nil, jsonErr
+				}
+			}
+			return nil, nil, loadErr
+		}
+		return nil, nil, fmt.Errorf("analysis failed: %w", err)
+	}
+	return analyzer, result, nil
+}
+
 func outputJSON(result *AnalysisResult) error {
 	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -160,16 +646,37 @@ func outputJSON(result *AnalysisResult) error {
 	return nil
 }
 
+// outputJSONError writes a structured error document so tooling can react to error codes
+func outputJSONError(loadErr *LoadError) error {
+	jsonData, err := json.MarshalIndent(struct {
+		Error *LoadError `json:"error"`
+	}{loadErr}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
 // Version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
 	Long:  "Print detailed version information including build metadata",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if versionJSON {
+			return printJSON(struct {
+				Version   string `json:"version"`
+				Commit    string `json:"commit"`
+				Built     string `json:"built"`
+				GoVersion string `json:"go_version"`
+			}{version, commit, date, getGoVersion()})
+		}
 		fmt.Printf("Gorphanage %s\n", version)
 		fmt.Printf("Commit: %s\n", commit)
 		fmt.Printf("Built: %s\n", date)
 		fmt.Printf("Go version: %s\n", getGoVersion())
+		return nil
 	},
 }
 
@@ -187,10 +694,61 @@ var configShowCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Current configuration:")
 		fmt.Printf("Config file: %s\n", viper.ConfigFileUsed())
+		fmt.Printf("Profile: %s\n", viper.GetString("profile"))
 		fmt.Printf("JSON output: %v\n", viper.GetBool("json"))
 		fmt.Printf("Verbose: %v\n", viper.GetBool("verbose"))
 		fmt.Printf("Exclude patterns: %v\n", viper.GetStringSlice("exclude"))
 		fmt.Printf("Include tests: %v\n", viper.GetBool("include-tests"))
+		fmt.Printf("Include replaced modules: %v\n", viper.GetBool("include-replaced"))
+		fmt.Printf("Include testdata: %v\n", viper.GetBool("include-testdata"))
+		fmt.Printf("Include tools: %v\n", viper.GetBool("include-tools"))
+		fmt.Printf("Export database: %s\n", viper.GetString("export-db"))
+		fmt.Printf("Dump graph: %s\n", viper.GetString("dump-graph"))
+		fmt.Printf("List reachable: %s\n", viper.GetString("list-reachable"))
+		fmt.Printf("Size estimate: %v\n", viper.GetBool("size-estimate"))
+		fmt.Printf("Write todos: %v\n", viper.GetBool("write-todos"))
+		fmt.Printf("With references: %v\n", viper.GetBool("with-references"))
+		fmt.Printf("Code actions: %v\n", viper.GetBool("code-actions"))
+		fmt.Printf("Fields: %v\n", viper.GetBool("fields"))
+		fmt.Printf("Results: %v\n", viper.GetBool("results"))
+		fmt.Printf("Wrappers: %v (max callers: %d)\n", viper.GetBool("wrappers"), viper.GetInt("wrapper-max-callers"))
+		fmt.Printf("Suggest unexport: %v\n", viper.GetBool("suggest-unexport"))
+		fmt.Printf("Baseline: %s\n", viper.GetString("baseline"))
+		fmt.Printf("Fail on: %s\n", viper.GetString("fail-on"))
+		fmt.Printf("Fail on new: %v\n", viper.GetBool("fail-on-new"))
+		fmt.Printf("Probe: %v (samples: %d)\n", viper.GetBool("probe"), viper.GetInt("probe-samples"))
+		fmt.Printf("Root rules: %v\n", viper.Get("root-rules"))
+		fmt.Printf("Callback registries: %v\n", viper.Get("callback-registries"))
+		fmt.Printf("String registries: %v\n", viper.Get("string-registries"))
+		fmt.Printf("Entry points: %v %v\n", viper.Get("entry-points"), viper.GetStringSlice("entry-func"))
+		fmt.Printf("Interface allowlist: %v\n", viper.GetStringSlice("interface-allowlist"))
+		fmt.Printf("Well-known methods: %v\n", viper.GetStringSlice("well-known-methods"))
+		fmt.Printf("Frameworks: %v\n", viper.GetStringSlice("frameworks"))
+		fmt.Printf("By author: %v\n", viper.GetBool("by-author"))
+		fmt.Printf("Components: %s\n", viper.GetString("components"))
+		fmt.Printf("Stream: %v\n", viper.GetBool("stream"))
+		fmt.Printf("Max findings: %d\n", viper.GetInt("max-findings"))
+		fmt.Printf("Skip packages over: %d\n", viper.GetInt("skip-packages-over"))
+		fmt.Printf("Import depth: %d to %d (0 for no limit)\n", viper.GetInt("min-import-depth"), viper.GetInt("max-import-depth"))
+		fmt.Printf("Sort: %s\n", viper.GetString("sort"))
+		fmt.Printf("Semantics: %s\n", viper.GetString("semantics"))
+		fmt.Printf("Roots: %s\n", viper.GetString("roots"))
+		fmt.Printf("Precision: %s\n", viper.GetString("precision"))
+		fmt.Printf("Reflection: %s\n", viper.GetString("reflection"))
+		fmt.Printf("Ignore assertions: %v\n", viper.GetBool("ignore-assertions"))
+		fmt.Printf("Main unexported: %s\n", viper.GetString("main-unexported"))
+		fmt.Printf("Generated: %s\n", viper.GetString("generated"))
+		fmt.Printf("Shard: %s\n", viper.GetString("shard"))
+		fmt.Printf("Coverage profile: %s\n", viper.GetString("coverprofile"))
+		fmt.Printf("Imported findings: %s\n", viper.GetString("import-findings"))
+		fmt.Printf("Post: %s\n", viper.GetString("post"))
+		fmt.Printf("Pprof profiles: %v\n", viper.GetStringSlice("pprof"))
+		fmt.Printf("Tags: %v\n", viper.GetStringSlice("tags"))
+		fmt.Printf("Platforms: %v\n", viper.GetStringSlice("platforms"))
+		fmt.Printf("Build matrix: %v\n", viper.GetStringSlice("build-matrix"))
+		fmt.Printf("Per-target report: %v\n", viper.GetBool("per-target-report"))
+		fmt.Printf("Recursive: %v\n", viper.GetBool("recursive"))
+		fmt.Printf("Daemon: %v\n", viper.GetBool("daemon"))
 	},
 }
 
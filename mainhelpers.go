@@ -0,0 +1,46 @@
+package main
+
+import "fmt"
+
+// Treatments of the unexported orphans of main packages, selected with --main-unexported
+const (
+	MainUnexportedReport = "report" // report them like any other orphan (default)
+	MainUnexportedGroup  = "group"  // list them apart as main package helpers
+)
+
+// isMainHelper reports whether an orphan is an unexported symbol of a main package that
+// --main-unexported group lists apart, such as a scratch helper kept in a cmd package
+func (a *Analyzer) isMainHelper(symbol *Symbol) bool {
+	if a.config.MainUnexported != MainUnexportedGroup || symbol.Exported {
+		return false
+	}
+	for _, pkg := range a.mainPackages {
+		if symbol.keyPackage() == a.keyPath(pkg.Types) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitMainHelpers separates the main package helpers from the other orphans, which
+// remain the findings
+func (a *Analyzer) splitMainHelpers(orphans []*Symbol) ([]*Symbol, []*Symbol) {
+	if a.config.MainUnexported != MainUnexportedGroup {
+		return orphans, nil
+	}
+
+	var findings, helpers []*Symbol
+	for _, orphan := range orphans {
+		if a.isMainHelper(orphan) {
+			helpers = append(helpers, orphan)
+		} else {
+			findings = append(findings, orphan)
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(helpers) > 0 {
+		fmt.Printf("🧰 %d unexported orphan(s) of main packages listed apart as helpers\n", len(helpers))
+	}
+
+	return findings, helpers
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Verdicts of an orphan in each configuration of a build matrix
+const (
+	TargetOrphaned  = "orphaned"  // built and unreachable in this configuration
+	TargetNotBuilt  = "not-built" // excluded from this configuration by its build constraints
+	TargetReachable = "reachable" // used in this configuration, with --per-target-report
+)
+
+// BuildTarget is one configuration of a --build-matrix: a platform and the build tags set
+// for it
+type BuildTarget struct {
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+// parseBuildTarget parses a build matrix entry of the form os/arch[+tag...]
+func parseBuildTarget(spec string) (*BuildTarget, error) {
+	platform, tags, _ := strings.Cut(spec, "+")
+	goos, goarch, _ := strings.Cut(platform, "/")
+	if validatePlatform(platform) != nil || strings.HasSuffix(spec, "+") || strings.Contains(spec, "++") {
+		return nil, fmt.Errorf("invalid --build-matrix entry %q (expected os/arch[+tag...], e.g. linux/amd64+integration)", spec)
+	}
+
+	target := &BuildTarget{GOOS: goos, GOARCH: goarch}
+	if tags != "" {
+		target.Tags = strings.Split(tags, "+")
+	}
+	for _, tag := range target.Tags {
+		if err := validateBuildTag(tag); err != nil {
+			return nil, fmt.Errorf("invalid --build-matrix entry %q: %w", spec, err)
+		}
+	}
+	return target, nil
+}
+
+// String returns the target in the form it is given on the command line
+func (t *BuildTarget) String() string {
+	return strings.Join(append([]string{t.GOOS + "/" + t.GOARCH}, t.Tags...), "+")
+}
+
+// env returns the environment loading packages for the target
+func (t *BuildTarget) env(environ []string) []string {
+	return append(environ, "GOOS="+t.GOOS, "GOARCH="+t.GOARCH)
+}
+
+// TargetVerdict is the verdict on an orphan in one configuration of the build matrix
+type TargetVerdict struct {
+	Target  string `json:"target"`
+	Verdict string `json:"verdict"`
+}
+
+// TargetSummary is the breakdown of one configuration of the build matrix: what its
+// analysis alone would report, and how many of those orphans another configuration uses
+type TargetSummary struct {
+	Target             string `json:"target"`
+	Symbols            int    `json:"symbols"`
+	Reachable          int    `json:"reachable"`
+	Orphans            int    `json:"orphans"`             // orphans of this configuration alone
+	ReachableElsewhere int    `json:"reachable_elsewhere"` // of those, reachable in another configuration
+
+	Findings []*Symbol `json:"findings,omitempty"` // orphans of this configuration alone, with --per-target-report
+}
+
+// analyzeMatrix analyzes the project once per --build-matrix configuration and reports
+// only the symbols orphaned in every configuration building them. A symbol used on a
+// single platform, or only declared in files of other platforms, is not dead code. The
+// other sections of the result are those of the first configuration. With
+// --per-target-report, each configuration also lists its own orphans with their verdict
+// on every configuration.
+func (a *Analyzer) analyzeMatrix() (*AnalysisResult, error) {
+	targets := make([]*BuildTarget, len(a.config.BuildMatrix))
+	for i, spec := range a.config.BuildMatrix {
+		target, err := parseBuildTarget(spec)
+		if err != nil {
+			return nil, err
+		}
+		targets[i] = target
+	}
+
+	analyzers := make([]*Analyzer, len(targets))
+	results := make([]*AnalysisResult, len(targets))
+	for i, target := range targets {
+		if a.config.Verbose && !a.config.OutputJSON {
+			fmt.Printf("🎯 Analyzing build configuration %s (%d/%d)\n", target, i+1, len(targets))
+		}
+
+		// The first configuration is analyzed in place, so that the analyzer holds a
+		// complete analysis afterwards, e.g. for --export-db
+		analyzer := a
+		if i > 0 {
+			analyzer = &Analyzer{config: a.config, moduleOnly: a.moduleOnly}
+		}
+		result, err := analyzer.analyzeBuild(target)
+		if err != nil {
+			return nil, fmt.Errorf("build configuration %s: %w", target, err)
+		}
+		analyzers[i], results[i] = analyzer, result
+	}
+
+	var orphans []*Symbol
+	var summaries []*TargetSummary
+	var files []*OrphanedFile
+	reported, dead := make(map[string]bool), make(map[string]bool)
+	for i, result := range results {
+		summary := &TargetSummary{
+			Target:    targets[i].String(),
+			Symbols:   result.TotalSymbols,
+			Reachable: result.ReachableSymbols,
+			Orphans:   len(result.OrphanedSymbols),
+		}
+		summaries = append(summaries, summary)
+
+		for _, orphan := range result.OrphanedSymbols {
+			key := analyzers[i].symbolKey(orphan)
+			verdicts, used := targetVerdicts(key, analyzers, targets)
+			if a.config.PerTargetReport {
+				finding := *orphan
+				finding.Targets = verdicts
+				summary.Findings = append(summary.Findings, &finding)
+			}
+			if used {
+				summary.ReachableElsewhere++
+				continue
+			}
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+
+			combined := *orphan
+			combined.Targets = verdicts
+			orphans = append(orphans, &combined)
+		}
+		sortOrphans(summary.Findings, SortFile)
+
+		// A file stays dead whole only if every configuration building it leaves all its
+		// declarations orphaned
+		for _, file := range result.OrphanedFiles {
+			if !dead[file.File] && !slices.ContainsFunc(file.Symbols, func(key string) bool { return !reported[key] }) {
+				dead[file.File] = true
+				files = append(files, file)
+			}
+		}
+	}
+	sortOrphans(orphans, a.config.Sort)
+	sort.Slice(files, func(i, j int) bool { return files[i].File < files[j].File })
+
+	// Dead clusters follow the references of the first configuration; those including a
+	// symbol another configuration uses don't hold
+	var clusters []*DeadCluster
+	for _, cluster := range results[0].DeadClusters {
+		if !slices.ContainsFunc(append(slices.Clone(cluster.Root), cluster.Unlocks...), func(key string) bool { return !reported[key] }) {
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	// Symbols count once however many configurations build them
+	symbols, reachable := make(map[string]bool), make(map[string]bool)
+	for _, analyzer := range analyzers {
+		for key, symbol := range analyzer.symbols {
+			if !analyzer.inShard(symbol.Package) {
+				continue
+			}
+			symbols[key] = true
+			if analyzer.isReachable(key) {
+				reachable[key] = true
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🎯 %d symbol(s) orphaned in every build configuration\n", len(orphans))
+	}
+
+	result := results[0]
+	result.TotalSymbols = len(symbols)
+	result.ReachableSymbols = len(reachable)
+	result.OrphanedSymbols = orphans
+	result.OrphanedFiles = files
+	result.DeadClusters = clusters
+	result.BuildMatrix = summaries
+	if result.StateCounts != nil {
+		result.StateCounts = make(map[string]int)
+		for _, orphan := range orphans {
+			result.StateCounts[orphan.State]++
+		}
+	}
+	a.emitFindings(orphans)
+	return result, nil
+}
+
+// targetVerdicts returns the verdict on a symbol in every configuration, and whether any
+// configuration reaches it
+func targetVerdicts(key string, analyzers []*Analyzer, targets []*BuildTarget) ([]*TargetVerdict, bool) {
+	verdicts := make([]*TargetVerdict, 0, len(targets))
+	used := false
+	for i, analyzer := range analyzers {
+		verdict := TargetOrphaned
+		if _, built := analyzer.symbols[key]; !built {
+			verdict = TargetNotBuilt
+		} else if analyzer.isReachable(key) {
+			verdict = TargetReachable
+			used = true
+		}
+		verdicts = append(verdicts, &TargetVerdict{Target: targets[i].String(), Verdict: verdict})
+	}
+	return verdicts, used
+}
+
+// targetsWith lists the build matrix configurations giving a symbol a verdict
+func targetsWith(symbol *Symbol, verdict string) []string {
+	var targets []string
+	for _, target := range symbol.Targets {
+		if target.Verdict == verdict {
+			targets = append(targets, target.Target)
+		}
+	}
+	return targets
+}
+
+// printBuildMatrix prints the breakdown of the build matrix configurations
+func (a *Analyzer) printBuildMatrix(result *AnalysisResult) {
+	if len(result.BuildMatrix) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🎯 Build matrix (orphans are reported only when dead in every configuration):\n")
+	for _, summary := range result.BuildMatrix {
+		fmt.Printf("  • %s - %d symbol(s), %d reachable, %d orphan(s) on its own, %d of them used by another configuration\n",
+			summary.Target, summary.Symbols, summary.Reachable, summary.Orphans, summary.ReachableElsewhere)
+	}
+
+	if !a.config.PerTargetReport {
+		return
+	}
+	for _, summary := range result.BuildMatrix {
+		fmt.Printf("\n🎯 Orphaned on %s (%d):\n", summary.Target, len(summary.Findings))
+		for _, finding := range summary.Findings {
+			a.printOrphan(finding)
+		}
+	}
+	fmt.Printf("\n🎯 Dead on every configuration: %d, listed above with the orphans\n", len(result.OrphanedSymbols))
+}
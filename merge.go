@@ -0,0 +1,351 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeJSON bool
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <result.json>...",
+	Short: "Merge JSON results of sharded analyses",
+	Long: `Combines the JSON results of analyses that each reported a subset of the project's
+packages, such as sharded CI jobs, into a single result. Findings are deduplicated by
+fingerprint and the aggregate statistics are recomputed.`,
+	Example: `  gorphanage merge shard-1.json shard-2.json shard-3.json --json > result.json`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var merged *AnalysisResult
+		for _, path := range args {
+			result, err := readResult(path)
+			if err != nil {
+				return err
+			}
+			if merged == nil {
+				merged = result
+				continue
+			}
+			if merged, err = MergeResults(merged, result); err != nil {
+				return fmt.Errorf("merging %s: %w", path, err)
+			}
+		}
+
+		cmd.SilenceUsage = true
+
+		if mergeJSON {
+			return outputJSON(merged)
+		}
+		New(WithProjectPath(merged.ProjectPath)).PrintResults(merged)
+		return nil
+	},
+}
+
+func init() {
+	mergeCmd.Flags().BoolVar(&mergeJSON, "json", false, "output the merged result in JSON format")
+	rootCmd.AddCommand(mergeCmd)
+}
+
+// readResult reads an analysis result written with --json
+func readResult(path string) (*AnalysisResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result: %w", err)
+	}
+
+	var result AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse result %s: %w", path, err)
+	}
+	return &result, nil
+}
+
+// MergeResults combines the results of two analyses of disjoint package subsets of the same
+// project. Findings reported by both are kept once, by fingerprint; symbol counts are summed
+// and the per-state and per-author aggregates are recomputed from the merged findings.
+func MergeResults(a, b *AnalysisResult) (*AnalysisResult, error) {
+	if a.Semantics != b.Semantics {
+		return nil, fmt.Errorf("cannot merge %s semantics with %s semantics", a.Semantics, b.Semantics)
+	}
+	// Results written before precision modes existed were resolved from references
+	precisionA, precisionB := cmp.Or(a.Precision, PrecisionReferences), cmp.Or(b.Precision, PrecisionReferences)
+	if precisionA != precisionB {
+		return nil, fmt.Errorf("cannot merge %s precision with %s precision", precisionA, precisionB)
+	}
+	if a.IncludedTests != b.IncludedTests {
+		return nil, fmt.Errorf("cannot merge results with and without tests")
+	}
+
+	merged := &AnalysisResult{
+		ProjectPath:      a.ProjectPath,
+		TotalSymbols:     a.TotalSymbols + b.TotalSymbols,
+		ReachableSymbols: a.ReachableSymbols + b.ReachableSymbols,
+		MainPackages:     max(a.MainPackages, b.MainPackages),
+		Semantics:        a.Semantics,
+		Precision:        precisionA,
+		OrphanedSymbols:  mergeSymbols(a.OrphanedSymbols, b.OrphanedSymbols),
+		ExcludedPackages: mergeStrings(a.ExcludedPackages, b.ExcludedPackages),
+		SkippedPackages:  mergeSkippedPackages(a.SkippedPackages, b.SkippedPackages),
+		IncludedTests:    a.IncludedTests,
+		SuggestedRoots:   mergeStrings(a.SuggestedRoots, b.SuggestedRoots),
+		ByAuthor:         mergeAuthors(a.ByAuthor, b.ByAuthor),
+		ByComponent:      mergeComponents(a.ByComponent, b.ByComponent),
+
+		DocsOnlySymbols: mergeSymbols(a.DocsOnlySymbols, b.DocsOnlySymbols),
+
+		UnusedConstraintPackages: mergeStrings(a.UnusedConstraintPackages, b.UnusedConstraintPackages),
+		OrphanedPackages:         mergeOrphanedPackages(a.OrphanedPackages, b.OrphanedPackages),
+		UnreadFields:             mergeSymbols(a.UnreadFields, b.UnreadFields),
+		UnusedResults:            mergeSymbols(a.UnusedResults, b.UnusedResults),
+		TrivialWrappers:          mergeSymbols(a.TrivialWrappers, b.TrivialWrappers),
+		UnexportCandidates:       mergeSymbols(a.UnexportCandidates, b.UnexportCandidates),
+		MainHelpers:              mergeSymbols(a.MainHelpers, b.MainHelpers),
+		GeneratedOrphans:         mergeSymbols(a.GeneratedOrphans, b.GeneratedOrphans),
+		ToolComparison:           mergeToolComparisons(a.ToolComparison, b.ToolComparison),
+		BuildMatrix:              mergeTargetSummaries(a.BuildMatrix, b.BuildMatrix),
+		WriteOnlyVariables:       mergeSymbols(a.WriteOnlyVariables, b.WriteOnlyVariables),
+		KeptByAssertion:          mergeSymbols(a.KeptByAssertion, b.KeptByAssertion),
+		UncalledFunctions:        mergeSymbols(a.UncalledFunctions, b.UncalledFunctions),
+		Modules:                  append(append([]*ModuleSummary(nil), a.Modules...), b.Modules...),
+	}
+
+	// Findings reported twice were counted in both totals
+	duplicates := len(a.OrphanedSymbols) + len(b.OrphanedSymbols) - len(merged.OrphanedSymbols)
+	merged.TotalSymbols -= duplicates
+
+	merged.BaselinedOrphans = a.BaselinedOrphans + b.BaselinedOrphans
+	if a.StateCounts != nil || b.StateCounts != nil {
+		merged.StateCounts = make(map[string]int)
+		for _, orphan := range merged.OrphanedSymbols {
+			merged.StateCounts[orphan.State]++
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, narrowing := range append(append([]*InterfaceNarrowing(nil), a.InterfaceNarrowings...), b.InterfaceNarrowings...) {
+		if !seen[narrowing.Constructor] {
+			seen[narrowing.Constructor] = true
+			merged.InterfaceNarrowings = append(merged.InterfaceNarrowings, narrowing)
+		}
+	}
+	sort.Slice(merged.InterfaceNarrowings, func(i, j int) bool {
+		return merged.InterfaceNarrowings[i].Constructor < merged.InterfaceNarrowings[j].Constructor
+	})
+
+	files := make(map[string]bool)
+	for _, file := range append(append([]*ObsoleteFile(nil), a.ObsoleteFiles...), b.ObsoleteFiles...) {
+		if !files[file.File] {
+			files[file.File] = true
+			merged.ObsoleteFiles = append(merged.ObsoleteFiles, file)
+		}
+	}
+	sort.Slice(merged.ObsoleteFiles, func(i, j int) bool { return merged.ObsoleteFiles[i].File < merged.ObsoleteFiles[j].File })
+
+	dead := make(map[string]bool)
+	for _, file := range append(append([]*OrphanedFile(nil), a.OrphanedFiles...), b.OrphanedFiles...) {
+		if !dead[file.File] {
+			dead[file.File] = true
+			merged.OrphanedFiles = append(merged.OrphanedFiles, file)
+		}
+	}
+	sort.Slice(merged.OrphanedFiles, func(i, j int) bool { return merged.OrphanedFiles[i].File < merged.OrphanedFiles[j].File })
+
+	// Clusters spanning shards stay split: the results carry no edges to rejoin them
+	clusters := make(map[string]bool)
+	for _, cluster := range append(append([]*SizeCluster(nil), a.SizeClusters...), b.SizeClusters...) {
+		if !clusters[cluster.Symbols[0]] {
+			clusters[cluster.Symbols[0]] = true
+			merged.SizeClusters = append(merged.SizeClusters, cluster)
+		}
+	}
+	sortSizeClusters(merged.SizeClusters)
+
+	// Like size clusters, dead clusters spanning shards stay split
+	roots := make(map[string]bool)
+	for _, cluster := range append(append([]*DeadCluster(nil), a.DeadClusters...), b.DeadClusters...) {
+		if !roots[cluster.Root[0]] {
+			roots[cluster.Root[0]] = true
+			merged.DeadClusters = append(merged.DeadClusters, cluster)
+		}
+	}
+	sortDeadClusters(merged.DeadClusters)
+
+	// Extractable clusters never span packages, so each comes from one shard
+	merged.ExtractableClusters = append(append([]*ExtractableCluster(nil), a.ExtractableClusters...), b.ExtractableClusters...)
+	sortExtractableClusters(merged.ExtractableClusters)
+
+	for _, labels := range []map[string]string{a.Labels, b.Labels} {
+		for key, label := range labels {
+			if merged.Labels == nil {
+				merged.Labels = make(map[string]string)
+			}
+			merged.Labels[key] = label
+		}
+	}
+
+	// Shards report disjoint packages, so each symbol's references come from one result
+	merged.References = append(append([]*SymbolReferences(nil), a.References...), b.References...)
+	sort.Slice(merged.References, func(i, j int) bool { return merged.References[i].Key < merged.References[j].Key })
+
+	return merged, nil
+}
+
+// mergeSymbols combines two symbol lists, keeping one symbol per fingerprint
+func mergeSymbols(a, b []*Symbol) []*Symbol {
+	var merged []*Symbol
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, symbol := range append(append([]*Symbol(nil), a...), b...) {
+		fp := fingerprint(symbol)
+		if !seen[fp] {
+			seen[fp] = true
+			merged = append(merged, symbol)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return fingerprint(merged[i]) < fingerprint(merged[j]) })
+	return merged
+}
+
+// mergeToolComparisons combines the cross-checks of two shards. Both compared the same
+// imported findings, so findings matching no symbol are the same in each.
+func mergeToolComparisons(a, b *ToolComparison) *ToolComparison {
+	if a == nil || b == nil {
+		return cmp.Or(a, b)
+	}
+
+	merged := &ToolComparison{
+		Source:         a.Source,
+		Agreed:         mergeStrings(a.Agreed, b.Agreed),
+		GorphanageOnly: mergeStrings(a.GorphanageOnly, b.GorphanageOnly),
+		Unmatched:      a.Unmatched,
+	}
+	if merged.Agreed == nil {
+		merged.Agreed = []string{}
+	}
+	seen := make(map[string]bool)
+	for _, finding := range append(append([]*ImportedFinding(nil), a.ToolOnly...), b.ToolOnly...) {
+		if !seen[finding.Symbol] {
+			seen[finding.Symbol] = true
+			merged.ToolOnly = append(merged.ToolOnly, finding)
+		}
+	}
+	return merged
+}
+
+// mergeStrings returns the sorted union of two string lists
+func mergeStrings(a, b []string) []string {
+	var merged []string
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, s := range append(append([]string(nil), a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			merged = append(merged, s)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// mergeComponents sums the per-component summaries of two results, keeping the order in
+// which components first appear
+func mergeComponents(a, b []ComponentSummary) []ComponentSummary {
+	var merged []ComponentSummary
+	index := make(map[string]int)
+	for _, summary := range append(append([]ComponentSummary(nil), a...), b...) {
+		i, ok := index[summary.Component]
+		if !ok {
+			index[summary.Component] = len(merged)
+			merged = append(merged, ComponentSummary{Component: summary.Component})
+			i = len(merged) - 1
+		}
+		merged[i].Symbols += summary.Symbols
+		merged[i].Reachable += summary.Reachable
+		merged[i].Orphans += summary.Orphans
+		merged[i].Exported += summary.Exported
+		merged[i].New += summary.New
+	}
+	return merged
+}
+
+// mergeOrphanedPackages combines two lists of orphaned packages, keeping one per package
+func mergeOrphanedPackages(a, b []*OrphanedPackage) []*OrphanedPackage {
+	var merged []*OrphanedPackage
+	seen := make(map[string]bool)
+	for _, pkg := range append(append([]*OrphanedPackage(nil), a...), b...) {
+		if !seen[pkg.Package+"@"+pkg.Dir] {
+			seen[pkg.Package+"@"+pkg.Dir] = true
+			merged = append(merged, pkg)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Dir < merged[j].Dir })
+	return merged
+}
+
+// mergeSkippedPackages combines the packages two results skipped, each listed once
+func mergeSkippedPackages(a, b []*SkippedPackage) []*SkippedPackage {
+	var merged []*SkippedPackage
+	seen := make(map[string]bool)
+	for _, pkg := range append(append([]*SkippedPackage(nil), a...), b...) {
+		if !seen[pkg.Package] {
+			seen[pkg.Package] = true
+			merged = append(merged, pkg)
+		}
+	}
+	sortSkippedPackages(merged)
+	return merged
+}
+
+// mergeTargetSummaries sums the build matrix breakdowns of two results, by configuration
+func mergeTargetSummaries(a, b []*TargetSummary) []*TargetSummary {
+	var merged []*TargetSummary
+	index := make(map[string]*TargetSummary)
+	for _, summary := range append(append([]*TargetSummary(nil), a...), b...) {
+		total, ok := index[summary.Target]
+		if !ok {
+			total = &TargetSummary{Target: summary.Target}
+			index[summary.Target] = total
+			merged = append(merged, total)
+		}
+		total.Symbols += summary.Symbols
+		total.Reachable += summary.Reachable
+		total.Orphans += summary.Orphans
+		total.ReachableElsewhere += summary.ReachableElsewhere
+		total.Findings = append(total.Findings, summary.Findings...)
+	}
+	return merged
+}
+
+// mergeAuthors sums the per-author summaries of two results
+func mergeAuthors(a, b []AuthorSummary) []AuthorSummary {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	totals := make(map[string]*AuthorSummary)
+	var merged []AuthorSummary
+	for _, summary := range append(append([]AuthorSummary(nil), a...), b...) {
+		total, ok := totals[summary.Author]
+		if !ok {
+			total = &AuthorSummary{Author: summary.Author}
+			totals[summary.Author] = total
+		}
+		total.Symbols += summary.Symbols
+		total.Lines += summary.Lines
+	}
+	for _, total := range totals {
+		merged = append(merged, *total)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Lines != merged[j].Lines {
+			return merged[i].Lines > merged[j].Lines
+		}
+		return merged[i].Author < merged[j].Author
+	})
+	return merged
+}
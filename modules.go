@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+// LocalReplacement is a module replaced by a directory on disk
+type LocalReplacement struct {
+	ModulePath string
+	Dir        string
+}
+
+// findModuleFile returns the path of the nearest go.mod at or above dir
+func findModuleFile(dir string) (string, bool) {
+	for {
+		candidate := filepath.Join(dir, "go.mod")
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// findWorkspaceModules returns the directories of the modules used by the go.work file at
+// the root of the project, or nil when the project is not a workspace or GOWORK=off
+// disables workspaces
+func findWorkspaceModules(projectPath string) ([]string, error) {
+	if os.Getenv("GOWORK") == "off" {
+		return nil, nil
+	}
+	path := filepath.Join(projectPath, "go.work")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.work: %w", err)
+	}
+
+	work, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	var dirs []string
+	for _, use := range work.Use {
+		dir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectPath, dir)
+		}
+		dirs = append(dirs, normalizePath(dir))
+	}
+	return dirs, nil
+}
+
+// findNestedModules lists directories below projectPath that contain their own go.mod.
+// Vendor, testdata and hidden directories are skipped like the go command does. Symlinked
+// directories are followed, unlike by the go command, and a directory reached through
+// several links is scanned once.
+func findNestedModules(projectPath string) ([]string, error) {
+	var nested []string
+	visited := make(map[string]bool) // real paths of the directories scanned
+
+	// scan walks the real directory of dir, reporting modules under dir
+	var scan func(dir, real string) error
+	scan = func(dir, real string) error {
+		return filepath.WalkDir(real, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if visited[path] {
+				return filepath.SkipDir
+			}
+			name := d.Name()
+			skipped := name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+			reported := filepath.Join(dir, strings.TrimPrefix(path, real))
+
+			// WalkDir doesn't follow symlinks: scan the directories they point to on their own
+			if d.Type()&fs.ModeSymlink != 0 {
+				target, err := filepath.EvalSymlinks(path)
+				if err != nil || skipped {
+					return nil
+				}
+				if info, err := os.Stat(target); err != nil || !info.IsDir() || visited[target] {
+					return nil
+				}
+				return scan(reported, target)
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			visited[path] = true
+			if path == real && dir == projectPath {
+				return nil
+			}
+			if skipped && path != real {
+				return filepath.SkipDir
+			}
+
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				nested = append(nested, reported)
+			}
+			return nil
+		})
+	}
+
+	real, err := filepath.EvalSymlinks(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for nested modules: %w", err)
+	}
+	if err := scan(projectPath, real); err != nil {
+		return nil, fmt.Errorf("failed to scan for nested modules: %w", err)
+	}
+	return nested, nil
+}
+
+// moduleOf returns the module path of a package, if known
+func moduleOf(pkg *packages.Package) string {
+	if pkg.Module != nil {
+		return pkg.Module.Path
+	}
+	return ""
+}
+
+// qualifiedPackagePath returns the package path symbol keys use for a package whose import
+// path is shared by several project modules, such as a vendored fork or a replaced module
+// kept next to the original: the module path and its directory relative to the project
+// qualify the import path
+func qualifiedPackagePath(pkgPath, modulePath, moduleDir string) string {
+	return modulePath + "@" + moduleDir + ":" + pkgPath
+}
+
+// assignKeyPaths decides the package path in the symbol keys of every loaded package. The
+// first module providing an import path keeps it unqualified, so keys of unambiguous
+// packages do not change; other modules providing the same path are qualified.
+func (a *Analyzer) assignKeyPaths() {
+	owners := make(map[string]string) // import path -> module directory providing it first
+	for _, pkg := range a.packages {
+		dir := ""
+		if pkg.Module != nil {
+			dir = pkg.Module.Dir
+		}
+		owner, ok := owners[pkg.PkgPath]
+		if !ok {
+			owners[pkg.PkgPath] = dir
+			owner = dir
+		}
+		if owner == dir || pkg.Module == nil {
+			a.keyPaths[pkg.Types] = pkg.PkgPath
+			continue
+		}
+
+		moduleDir := a.relativePath(pkg.Module.Dir)
+		a.keyPaths[pkg.Types] = qualifiedPackagePath(pkg.PkgPath, pkg.Module.Path, moduleDir)
+		a.moduleDirs[pkg.Types] = moduleDir
+		if a.config.Verbose && !a.config.OutputJSON {
+			fmt.Printf("🔀 Package %s is provided by several modules; keying the copy in %s by module\n", pkg.PkgPath, moduleDir)
+		}
+	}
+}
+
+// keyPath returns the package path used in the symbol keys of a package
+func (a *Analyzer) keyPath(pkg *types.Package) string {
+	if path, ok := a.keyPaths[pkg]; ok {
+		return path
+	}
+	return pkg.Path()
+}
+
+// findLocalReplacements lists the replace directives of the project's go.mod that point at local directories
+func findLocalReplacements(projectPath string) ([]LocalReplacement, error) {
+	gomod, ok := findModuleFile(projectPath)
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", gomod, err)
+	}
+
+	mf, err := modfile.Parse(gomod, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", gomod, err)
+	}
+
+	var replacements []LocalReplacement
+	for _, rep := range mf.Replace {
+		// Local replacements have a directory path and never a version
+		if rep.New.Version != "" || !modfile.IsDirectoryPath(rep.New.Path) {
+			continue
+		}
+
+		dir := rep.New.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(gomod), dir)
+		}
+
+		replacements = append(replacements, LocalReplacement{
+			ModulePath: rep.Old.Path,
+			Dir:        filepath.Clean(dir),
+		})
+	}
+
+	return replacements, nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// InterfaceNarrowing is a constructor returning a narrow interface around a concrete type
+// whose extra exported methods are never called: the "fat struct behind a narrow interface"
+type InterfaceNarrowing struct {
+	Constructor    string           `json:"constructor"`
+	Interface      string           `json:"interface"`
+	Concrete       string           `json:"concrete"`
+	UnusedMethods  []NarrowedMethod `json:"unused_methods"`
+	constructorKey string
+}
+
+// NarrowedMethod is an exported method hidden behind a constructor's interface
+type NarrowedMethod struct {
+	Name  string   `json:"name"`
+	File  string   `json:"file"`
+	Start Position `json:"start"`
+}
+
+// methodKey identifies a method by its receiver's base type: pkg.Type.Method
+func methodKey(fn *types.Func) (string, bool) {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return "", false
+	}
+
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return "", false
+	}
+	named = named.Origin()
+
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name(), true
+}
+
+// recordMethodUse remembers that a concrete or interface method is referenced
+func (a *Analyzer) recordMethodUse(obj types.Object) {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return
+	}
+	if key, ok := methodKey(fn.Origin()); ok {
+		a.usedMethods[key] = true
+	}
+}
+
+// findInterfaceNarrowing finds constructors returning an interface around a single project
+// type and the exported methods of that type that nothing calls. Methods named like any
+// interface method in the loaded packages or their imports are kept, since they may be
+// called dynamically through that interface.
+func (a *Analyzer) findInterfaceNarrowing() {
+	interfaceMethods := a.interfaceMethodNames()
+
+	projectPkgs := make(map[string]bool, len(a.packages))
+	for _, pkg := range a.packages {
+		projectPkgs[pkg.PkgPath] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, pkg := range a.packages {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || fn.Body == nil || fn.Type.Results == nil || len(fn.Type.Results.List) == 0 {
+					continue
+				}
+
+				key := a.getSymbolKey(a.keyPath(pkg.Types), fn.Name.Name, "function")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				iface := pkg.TypesInfo.TypeOf(fn.Type.Results.List[0].Type)
+				if iface == nil || !types.IsInterface(iface) {
+					continue
+				}
+
+				concrete := returnedConcreteType(pkg.TypesInfo, fn.Body)
+				if concrete == nil || concrete.Obj().Pkg() == nil || !projectPkgs[concrete.Obj().Pkg().Path()] {
+					continue
+				}
+
+				narrowing := &InterfaceNarrowing{
+					Constructor:    pkg.PkgPath + "." + fn.Name.Name,
+					Interface:      types.TypeString(iface, nil),
+					Concrete:       types.TypeString(concrete, nil),
+					constructorKey: key,
+				}
+				narrowing.UnusedMethods = a.unusedExtraMethods(concrete, iface.Underlying().(*types.Interface), interfaceMethods)
+				if len(narrowing.UnusedMethods) > 0 {
+					a.narrowings = append(a.narrowings, narrowing)
+				}
+			}
+		}
+	}
+}
+
+// returnedConcreteType returns the single named concrete type a function body returns as
+// its first result, or nil if it returns several or none
+func returnedConcreteType(info *types.Info, body *ast.BlockStmt) *types.Named {
+	var concrete *types.Named
+	ambiguous := false
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) == 0 {
+				return true
+			}
+			t := info.TypeOf(node.Results[0])
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			named, ok := t.(*types.Named)
+			if !ok || types.IsInterface(named) {
+				// nil and interface values carry no concrete type
+				if t != types.Typ[types.UntypedNil] {
+					ambiguous = true
+				}
+				return true
+			}
+			if concrete != nil && concrete.Origin() != named.Origin() {
+				ambiguous = true
+			}
+			concrete = named.Origin()
+		}
+		return true
+	})
+
+	if ambiguous {
+		return nil
+	}
+	return concrete
+}
+
+// unusedExtraMethods lists exported methods declared on the concrete type that are not part
+// of the interface and never referenced
+func (a *Analyzer) unusedExtraMethods(concrete *types.Named, iface *types.Interface, interfaceMethods map[string]bool) []NarrowedMethod {
+	var unused []NarrowedMethod
+	for i := 0; i < concrete.NumMethods(); i++ {
+		fn := concrete.Method(i)
+		if !fn.Exported() || interfaceMethods[fn.Name()] {
+			continue
+		}
+		if obj, _, _ := types.LookupFieldOrMethod(iface, false, nil, fn.Name()); obj != nil {
+			continue
+		}
+		if key, ok := methodKey(fn); !ok || a.usedMethods[key] {
+			continue
+		}
+
+		pos := a.fileSet.Position(fn.Pos())
+		unused = append(unused, NarrowedMethod{
+			Name:  fn.Name(),
+			File:  pos.Filename,
+			Start: Position{Line: pos.Line, Column: pos.Column},
+		})
+	}
+
+	sort.Slice(unused, func(i, j int) bool { return unused[i].Name < unused[j].Name })
+	return unused
+}
+
+// interfaceMethodNames collects the method names of every interface declared in the loaded
+// packages and the packages they import
+func (a *Analyzer) interfaceMethodNames() map[string]bool {
+	names := make(map[string]bool)
+	visited := make(map[*types.Package]bool)
+
+	collect := func(pkg *types.Package) {
+		if pkg == nil || visited[pkg] {
+			return
+		}
+		visited[pkg] = true
+
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			if iface, ok := typeName.Type().Underlying().(*types.Interface); ok {
+				for i := 0; i < iface.NumMethods(); i++ {
+					names[iface.Method(i).Name()] = true
+				}
+			}
+		}
+	}
+
+	for _, pkg := range a.packages {
+		collect(pkg.Types)
+		for _, imported := range pkg.Types.Imports() {
+			collect(imported)
+		}
+	}
+
+	return names
+}
+
+// reachableNarrowings returns the interface narrowings whose constructor is reachable
+func (a *Analyzer) reachableNarrowings() []*InterfaceNarrowing {
+	var narrowings []*InterfaceNarrowing
+	for _, narrowing := range a.narrowings {
+		if a.isReachable(narrowing.constructorKey) && a.inShard(a.symbols[narrowing.constructorKey].Package) {
+			narrowings = append(narrowings, narrowing)
+		}
+	}
+	sort.Slice(narrowings, func(i, j int) bool { return narrowings[i].Constructor < narrowings[j].Constructor })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(narrowings) > 0 {
+		fmt.Printf("🪶 %d constructor(s) hide unused methods behind a narrow interface\n", len(narrowings))
+	}
+
+	return narrowings
+}
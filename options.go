@@ -0,0 +1,284 @@
+package main
+
+import "slices"
+
+// Option configures an analyzer created with New
+type Option func(*Config)
+
+// New creates an analyzer from functional options. The analyzer owns its configuration:
+// nothing passed in can change it afterwards, and it can run several analyses in a row.
+func New(opts ...Option) *Analyzer {
+	config := &Config{ProjectPath: ".", ProbeSamples: 20, WrapperMaxCallers: 1, Semantics: SemanticsAuto, Precision: PrecisionReferences}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return &Analyzer{config: config}
+}
+
+// WithConfig starts from a copy of an existing configuration
+func WithConfig(config *Config) Option {
+	return func(c *Config) {
+		*c = *config
+		c.Exclude = append([]string(nil), config.Exclude...)
+		c.PprofProfiles = append([]string(nil), config.PprofProfiles...)
+		c.RootRules = append([]RootRule(nil), config.RootRules...)
+		c.CallbackRegistries = append([]CallbackRegistry(nil), config.CallbackRegistries...)
+		c.StringRegistries = append([]StringRegistry(nil), config.StringRegistries...)
+		c.EntryPoints = append([]EntryPoint(nil), config.EntryPoints...)
+		c.InterfaceAllowlist = append([]string(nil), config.InterfaceAllowlist...)
+		c.WellKnownMethods = append([]string(nil), config.WellKnownMethods...)
+		c.Frameworks = slices.Clone(config.Frameworks)
+		c.FrameworkDetectors = append([]FrameworkDetector(nil), config.FrameworkDetectors...)
+		c.Tags = append([]string(nil), config.Tags...)
+		c.Platforms = append([]string(nil), config.Platforms...)
+		c.BuildMatrix = append([]string(nil), config.BuildMatrix...)
+	}
+}
+
+// WithProjectPath sets the project directory to analyze
+func WithProjectPath(path string) Option {
+	return func(c *Config) { c.ProjectPath = path }
+}
+
+// WithTests includes test files in the analysis
+func WithTests() Option {
+	return func(c *Config) { c.IncludeTests = true }
+}
+
+// WithExclude adds package patterns to exclude from the analysis
+func WithExclude(patterns ...string) Option {
+	return func(c *Config) { c.Exclude = append(c.Exclude, patterns...) }
+}
+
+// WithReplaced also analyzes modules replaced by local directories
+func WithReplaced() Option {
+	return func(c *Config) { c.IncludeReplaced = true }
+}
+
+// WithTestdata analyzes packages below testdata directories
+func WithTestdata() Option {
+	return func(c *Config) { c.IncludeTestdata = true }
+}
+
+// WithTools analyzes packages below internal/tools
+func WithTools() Option {
+	return func(c *Config) { c.IncludeTools = true }
+}
+
+// WithPrecision selects how calls are resolved: references, rta or vta
+func WithPrecision(precision string) Option {
+	return func(c *Config) { c.Precision = precision }
+}
+
+// WithSemantics selects the analysis semantics: auto, binary or module
+func WithSemantics(semantics string) Option {
+	return func(c *Config) { c.Semantics = semantics }
+}
+
+// WithIgnoreAssertions treats keep-alive assertions as no references
+func WithIgnoreAssertions() Option {
+	return func(c *Config) { c.IgnoreAssertions = true }
+}
+
+// WithReflection selects how reflection is accounted for: none or conservative
+func WithReflection(mode string) Option {
+	return func(c *Config) { c.Reflection = mode }
+}
+
+// WithMainUnexported selects how unexported orphans of main packages are reported: report,
+// or group to list them apart from the findings
+func WithMainUnexported(mode string) Option {
+	return func(c *Config) { c.MainUnexported = mode }
+}
+
+// WithGenerated selects how orphans of generated files are reported: include, exclude or
+// separate
+func WithGenerated(policy string) Option {
+	return func(c *Config) { c.Generated = policy }
+}
+
+// WithRootRules adds convention root rules
+func WithRootRules(rules ...RootRule) Option {
+	return func(c *Config) { c.RootRules = append(c.RootRules, rules...) }
+}
+
+// WithCallbackRegistries adds APIs whose function arguments or fields are callbacks
+func WithCallbackRegistries(registries ...CallbackRegistry) Option {
+	return func(c *Config) { c.CallbackRegistries = append(c.CallbackRegistries, registries...) }
+}
+
+// WithStringRegistries adds APIs that look symbols up by a string name
+func WithStringRegistries(registries ...StringRegistry) Option {
+	return func(c *Config) { c.StringRegistries = append(c.StringRegistries, registries...) }
+}
+
+// WithEntryPoints adds functions invoked from outside the analysis, treated as roots
+func WithEntryPoints(entries ...EntryPoint) Option {
+	return func(c *Config) { c.EntryPoints = append(c.EntryPoints, entries...) }
+}
+
+// WithInterfaceAllowlist keeps the methods implementing these pkg.Interface contracts
+func WithInterfaceAllowlist(interfaces ...string) Option {
+	return func(c *Config) { c.InterfaceAllowlist = append(c.InterfaceAllowlist, interfaces...) }
+}
+
+// WithWellKnownMethods keeps methods with these names whenever their receiver type is
+// reachable, in addition to the built-in names
+func WithWellKnownMethods(names ...string) Option {
+	return func(c *Config) { c.WellKnownMethods = append(c.WellKnownMethods, names...) }
+}
+
+// WithFrameworks runs only these built-in framework detectors (net/http, grpc, cobra, wire,
+// fx, dig); with no names, none of them runs
+func WithFrameworks(names ...string) Option {
+	return func(c *Config) { c.Frameworks = append([]string{}, names...) }
+}
+
+// WithFrameworkDetectors plugs in detectors for frameworks the built-in ones don't cover
+func WithFrameworkDetectors(detectors ...FrameworkDetector) Option {
+	return func(c *Config) { c.FrameworkDetectors = append(c.FrameworkDetectors, detectors...) }
+}
+
+// WithBaseline applies finding states from a baseline file
+func WithBaseline(path string) Option {
+	return func(c *Config) { c.BaselineFile = path }
+}
+
+// WithCoverProfile cross-references orphans with a coverage profile
+func WithCoverProfile(path string) Option {
+	return func(c *Config) { c.CoverProfile = path }
+}
+
+// WithPprofProfiles cross-checks orphans with runtime profiles
+func WithPprofProfiles(paths ...string) Option {
+	return func(c *Config) { c.PprofProfiles = append(c.PprofProfiles, paths...) }
+}
+
+// WithImportFindings cross-checks orphans with the unused findings of staticcheck or
+// golangci-lint in a JSON file
+func WithImportFindings(path string) Option {
+	return func(c *Config) { c.ImportFindings = path }
+}
+
+// WithProbe verifies up to samples orphans by type-checking the project without them
+func WithProbe(samples int) Option {
+	return func(c *Config) {
+		c.Probe = true
+		c.ProbeSamples = samples
+	}
+}
+
+// WithByAuthor summarizes orphans by last author
+func WithByAuthor() Option {
+	return func(c *Config) { c.ByAuthor = true }
+}
+
+// WithComponents groups results by the components of a component manifest
+func WithComponents(path string) Option {
+	return func(c *Config) { c.ComponentsFile = path }
+}
+
+// WithFields reports struct fields that are never read
+func WithFields() Option {
+	return func(c *Config) { c.Fields = true }
+}
+
+// WithResults reports function results that every caller ignores
+func WithResults() Option {
+	return func(c *Config) { c.Results = true }
+}
+
+// WithWrappers reports functions whose body is only a type conversion or field copy and
+// that at most maxCallers declarations reference
+func WithWrappers(maxCallers int) Option {
+	return func(c *Config) {
+		c.Wrappers = true
+		c.WrapperMaxCallers = maxCallers
+	}
+}
+
+// WithSuggestUnexport reports exported symbols that only their own package references
+func WithSuggestUnexport() Option {
+	return func(c *Config) { c.SuggestUnexport = true }
+}
+
+// WithTags loads packages with these build tags, like go build -tags
+func WithTags(tags ...string) Option {
+	return func(c *Config) { c.Tags = append(c.Tags, tags...) }
+}
+
+// WithPlatforms sets the os/arch platforms build constraints must be satisfiable on
+func WithPlatforms(platforms ...string) Option {
+	return func(c *Config) { c.Platforms = append(c.Platforms, platforms...) }
+}
+
+// WithPerTargetReport lists the orphans of each build matrix configuration on its own
+func WithPerTargetReport() Option {
+	return func(c *Config) { c.PerTargetReport = true }
+}
+
+// WithRecursive analyzes every module below the project on its own and merges the results
+func WithRecursive() Option {
+	return func(c *Config) { c.Recursive = true }
+}
+
+// WithBuildMatrix analyzes the project once per os/arch[+tag...] configuration and reports
+// only the symbols orphaned in all of them
+func WithBuildMatrix(targets ...string) Option {
+	return func(c *Config) { c.BuildMatrix = append(c.BuildMatrix, targets...) }
+}
+
+// WithHooks registers callbacks following the analysis' phases, packages, roots and
+// findings
+func WithHooks(hooks Hooks) Option {
+	return func(c *Config) { c.Hooks = hooks }
+}
+
+// WithSkipPackagesOver leaves out the packages declaring more than n symbols, such as huge
+// generated enums
+func WithSkipPackagesOver(n int) Option {
+	return func(c *Config) { c.SkipPackagesOver = n }
+}
+
+// WithShard reports only the packages of shard index (1-based) out of count
+func WithShard(index, count int) Option {
+	return func(c *Config) {
+		c.ShardIndex = index
+		c.ShardCount = count
+	}
+}
+
+// WithImportDepths reports only orphans of packages between these depths of the import
+// graph, where a main or other top package is at depth 1; a max of 0 sets no limit
+func WithImportDepths(minDepth, maxDepth int) Option {
+	return func(c *Config) {
+		c.MinImportDepth = minDepth
+		c.MaxImportDepth = maxDepth
+	}
+}
+
+// WithSort orders the orphans of the result: file, or depth for the deepest packages first
+func WithSort(order string) Option {
+	return func(c *Config) { c.Sort = order }
+}
+
+// WithSizeEstimate estimates the binary size of orphan clusters
+func WithSizeEstimate() Option {
+	return func(c *Config) { c.SizeEstimate = true }
+}
+
+// WithReferenceList lists the references to every reachable symbol in the result
+func WithReferenceList() Option {
+	return func(c *Config) { c.WithReferences = true }
+}
+
+// WithCodeActions attaches editor quick fixes to every orphan in the result
+func WithCodeActions() Option {
+	return func(c *Config) { c.CodeActions = true }
+}
+
+// WithVerbose prints progress while analyzing
+func WithVerbose() Option {
+	return func(c *Config) { c.Verbose = true }
+}
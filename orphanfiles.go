@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// OrphanedFile is a file every top-level declaration of which is orphaned. It is reported
+// as one finding: the file can be deleted whole.
+type OrphanedFile struct {
+	File    string   `json:"file"`
+	Package string   `json:"package"`
+	Lines   int      `json:"lines"`
+	Symbols []string `json:"symbols"` // keys of the orphans it declares
+}
+
+// fileDecls counts the top-level declarations of a file
+type fileDecls struct {
+	pkg      string
+	lines    int
+	declared int  // named declarations, each name of a group counting once
+	effects  bool // a blank variable initializer may have effects
+}
+
+// recordFileDecls counts the top-level declarations of a file for orphanedFiles, which
+// runs once the syntax trees are released
+func (a *Analyzer) recordFileDecls(pkg *packages.Package, file *ast.File, filename string) {
+	decls := &fileDecls{pkg: pkg.PkgPath}
+	if tokenFile := a.fileSet.File(file.Pos()); tokenFile != nil {
+		decls.lines = tokenFile.LineCount()
+	}
+	for _, decl := range file.Decls {
+		switch node := decl.(type) {
+		case *ast.FuncDecl:
+			if node.Name.Name != "_" {
+				decls.declared++
+			}
+		case *ast.GenDecl:
+			for _, spec := range node.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					decls.declared++
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Name != "_" {
+							decls.declared++
+						}
+					}
+					if isBlankSpec(spec) && !isKeepAlive(pkg.TypesInfo, spec) {
+						decls.effects = true
+					}
+				}
+			}
+		}
+	}
+	a.fileDecls[filename] = decls
+}
+
+// orphanedFiles returns the files declaring nothing but orphans. A blank declaration whose
+// initializer may have effects keeps its file, and so does a declaration the analysis has
+// no symbol for, such as one of several build variants.
+func (a *Analyzer) orphanedFiles(orphans []*Symbol) []*OrphanedFile {
+	inFile := make(map[string][]string)
+	for _, orphan := range orphans {
+		if orphan.Kind != "field" {
+			inFile[orphan.File] = append(inFile[orphan.File], a.symbolKey(orphan))
+		}
+	}
+
+	var orphaned []*OrphanedFile
+	for filename, keys := range inFile {
+		decls, ok := a.fileDecls[filename]
+		if !ok || decls.effects || decls.declared != len(keys) {
+			continue
+		}
+		sort.Strings(keys)
+		orphaned = append(orphaned, &OrphanedFile{
+			File:    filename,
+			Package: decls.pkg,
+			Lines:   decls.lines,
+			Symbols: keys,
+		})
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].File < orphaned[j].File })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(orphaned) > 0 {
+		fmt.Printf("🪦 %d file(s) declare nothing but orphans\n", len(orphaned))
+	}
+	return orphaned
+}
+
+// isBlankSpec reports whether a value spec declares only blank identifiers
+func isBlankSpec(spec *ast.ValueSpec) bool {
+	for _, name := range spec.Names {
+		if name.Name != "_" {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutOrphanedFiles returns the orphans not declared in a file reported whole
+func withoutOrphanedFiles(orphans []*Symbol, files []*OrphanedFile) []*Symbol {
+	if len(files) == 0 {
+		return orphans
+	}
+	dead := make(map[string]bool, len(files))
+	for _, file := range files {
+		dead[file.File] = true
+	}
+	var rest []*Symbol
+	for _, orphan := range orphans {
+		if !dead[orphan.File] {
+			rest = append(rest, orphan)
+		}
+	}
+	return rest
+}
+
+// printOrphanedFiles prints the files declaring nothing but orphans, one finding each
+func (a *Analyzer) printOrphanedFiles(result *AnalysisResult) {
+	if len(result.OrphanedFiles) == 0 {
+		return
+	}
+
+	fmt.Printf("=== Whole files ===\n")
+	for _, file := range result.OrphanedFiles {
+		fmt.Printf("  🪦 %s - whole file is dead (%d lines, %d symbol(s))\n", a.relativePath(file.File), file.Lines, len(file.Symbols))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// OrphanedPackage is a project package that no main package imports, directly or not. It
+// is dead as a whole, whatever its declarations reference among themselves.
+type OrphanedPackage struct {
+	Package    string   `json:"package"`
+	Dir        string   `json:"dir"`
+	Symbols    int      `json:"symbols"`
+	ImportedBy []string `json:"imported_by,omitempty"` // project packages importing it, orphaned as well
+}
+
+// orphanedPackages returns the project packages that no main package imports, directly or
+// not, ignoring test files. Under module semantics importers outside the project may use
+// any package, so none is reported.
+func (a *Analyzer) orphanedPackages() []*OrphanedPackage {
+	if a.semantics != SemanticsBinary || len(a.mainPackages) == 0 {
+		return nil
+	}
+
+	project := make(map[*packages.Package]bool, len(a.packages))
+	for _, pkg := range a.packages {
+		project[pkg] = true
+	}
+
+	linked := make(map[string]bool)
+	visited := make(map[*packages.Package]bool)
+	var link func(pkg *packages.Package)
+	link = func(pkg *packages.Package) {
+		if !project[pkg] || visited[pkg] {
+			return
+		}
+		visited[pkg] = true
+		linked[a.keyPath(pkg.Types)] = true
+		for _, imported := range pkg.Imports {
+			link(imported)
+		}
+	}
+	for _, pkg := range a.mainPackages {
+		if !isTestVariant(pkg) {
+			link(pkg)
+		}
+	}
+
+	importers := make(map[string][]string)
+	for _, pkg := range a.packages {
+		if isTestVariant(pkg) {
+			continue
+		}
+		for _, imported := range pkg.Imports {
+			if project[imported] {
+				keyPath := a.keyPath(imported.Types)
+				importers[keyPath] = append(importers[keyPath], pkg.PkgPath)
+			}
+		}
+	}
+
+	symbols := make(map[string]int)
+	for _, symbol := range a.symbols {
+		symbols[symbol.keyPackage()]++
+	}
+
+	var orphaned []*OrphanedPackage
+	for _, pkg := range a.packages {
+		keyPath := a.keyPath(pkg.Types)
+		if linked[keyPath] || pkg.Name == "main" || isTestVariant(pkg) || len(pkg.GoFiles) == 0 || !a.inShard(pkg.PkgPath) {
+			continue
+		}
+		importedBy := importers[keyPath]
+		sort.Strings(importedBy)
+		orphaned = append(orphaned, &OrphanedPackage{
+			Package:    pkg.PkgPath,
+			Dir:        a.relativePath(filepath.Dir(pkg.GoFiles[0])),
+			Symbols:    symbols[keyPath],
+			ImportedBy: importedBy,
+		})
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].Dir < orphaned[j].Dir })
+	return orphaned
+}
+
+// isTestVariant reports whether a package is built for tests: a package compiled with its
+// _test.go files, an external test package or a generated test main
+func isTestVariant(pkg *packages.Package) bool {
+	return pkg.ID != pkg.PkgPath || strings.HasSuffix(pkg.PkgPath, "_test") || strings.HasSuffix(pkg.PkgPath, ".test")
+}
+
+// printOrphanedPackages lists the packages no main package imports
+func (a *Analyzer) printOrphanedPackages(result *AnalysisResult) {
+	if len(result.OrphanedPackages) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📦 Orphaned packages (never imported from a main package; delete them whole):\n")
+	for _, pkg := range result.OrphanedPackages {
+		fmt.Printf("  • %s (%s) - %d symbol(s)", pkg.Package, pkg.Dir, pkg.Symbols)
+		if len(pkg.ImportedBy) > 0 {
+			fmt.Printf(", imported only by %s", strings.Join(pkg.ImportedBy, ", "))
+		}
+		fmt.Println()
+	}
+}
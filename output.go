@@ -2,54 +2,456 @@ package main
 
 import (
 	"fmt"
-	"path/filepath"
+	"io"
+	"slices"
 	"strings"
 )
 
 // PrintResults outputs the analysis results in human-readable format
 func (a *Analyzer) PrintResults(result *AnalysisResult) {
+	if len(result.OrphanedSymbols) == 0 && result.BaselinedOrphans > 0 {
+		fmt.Println("\n✅ No new orphaned code found!")
+		fmt.Printf("%d orphaned symbol(s) recorded in the baseline are not listed.\n", result.BaselinedOrphans)
+		a.printSections(result)
+		return
+	}
 	if len(result.OrphanedSymbols) == 0 {
 		fmt.Println("\n✅ No orphaned code found!")
 		fmt.Println("All symbols are reachable from main package entry points.")
+		a.printSections(result)
+		return
+	}
+
+	// Findings were already printed per package while the analysis ran
+	if a.config.Stream {
+		fmt.Printf("Found %d symbols that are NOT reachable from any main package.\n\n", len(result.OrphanedSymbols))
+		if a.config.MaxFindings > 0 && a.streamed > a.config.MaxFindings {
+			printOverflow(result.OrphanedSymbols, a.config.MaxFindings, a.config.MaxFindings)
+		}
+		a.printOrphanedFiles(result)
+		a.printSummary(result)
+		a.printSections(result)
 		return
 	}
 
 	fmt.Printf("\n🗑️  ORPHANED CODE ANALYSIS\n")
 	fmt.Printf("Found %d symbols that are NOT reachable from any main package:\n\n", len(result.OrphanedSymbols))
 
-	// Group by kind
+	orphans, truncated := shownFindings(result.OrphanedSymbols, a.config.MaxFindings)
+
+	// Files declaring nothing but orphans are reported whole instead of symbol by symbol
+	a.printOrphanedFiles(result)
+	orphans = withoutOrphanedFiles(orphans, result.OrphanedFiles)
+
+	if len(result.ByComponent) > 0 {
+		// Components first, in manifest order, then by kind within each
+		byComponent := make(map[string][]*Symbol)
+		for _, orphan := range orphans {
+			byComponent[orphan.Component] = append(byComponent[orphan.Component], orphan)
+		}
+		for _, summary := range result.ByComponent {
+			if orphans := byComponent[summary.Component]; len(orphans) > 0 {
+				fmt.Printf("━━━ Component: %s ━━━\n", summary.Component)
+				a.printKindGroups(orphans)
+			}
+		}
+	} else {
+		a.printKindGroups(orphans)
+	}
+	if truncated {
+		printOverflow(result.OrphanedSymbols, len(orphans), a.config.MaxFindings)
+	}
+
+	a.printSummary(result)
+	a.printSections(result)
+}
+
+// printKindGroups prints orphans grouped by kind
+func (a *Analyzer) printKindGroups(orphans []*Symbol) {
 	kindGroups := make(map[string][]*Symbol)
-	for _, orphan := range result.OrphanedSymbols {
+	for _, orphan := range orphans {
 		kindGroups[orphan.Kind] = append(kindGroups[orphan.Kind], orphan)
 	}
 
 	for kind, symbols := range kindGroups {
 		fmt.Printf("=== %s%s ===\n", strings.ToUpper(kind[:1]), kind[1:]+"s")
 		for _, symbol := range symbols {
-			relPath, err := filepath.Rel(a.config.ProjectPath, symbol.File)
-			if err != nil {
-				relPath = symbol.File
+			a.printOrphan(symbol)
+		}
+		fmt.Println()
+	}
+}
+
+// printSections prints the findings reported next to orphaned symbols
+func (a *Analyzer) printSections(result *AnalysisResult) {
+	a.printSkippedPackages(result)
+	a.printNarrowings(result)
+	a.printDocsOnly(result)
+	a.printObsoleteFiles(result)
+	a.printBuildMatrix(result)
+	a.printModules(result)
+	a.printOrphanedPackages(result)
+	a.printConstraintPackages(result)
+	a.printUnreadFields(result)
+	a.printUnusedResults(result)
+	a.printTrivialWrappers(result)
+	a.printUnexportCandidates(result)
+	a.printMainHelpers(result)
+	a.printGeneratedOrphans(result)
+	a.printToolComparison(result)
+	a.printWriteOnlyVariables(result)
+	a.printUncalledFunctions(result)
+	a.printKeptByAssertion(result)
+	a.printExtractableClusters(result)
+	a.printDeadClusters(result)
+	a.printSizeClusters(result)
+}
+
+// printExtractableClusters lists the orphan clusters that could be moved to a separate
+// module instead of being deleted
+func (a *Analyzer) printExtractableClusters(result *AnalysisResult) {
+	if len(result.ExtractableClusters) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🗄️  Orphan clusters extractable to a separate module (to archive instead of delete):\n")
+	for _, cluster := range result.ExtractableClusters {
+		names := make([]string, 0, len(cluster.Symbols))
+		for _, key := range cluster.Symbols {
+			names = append(names, result.describeKey(key))
+		}
+		fmt.Printf("  • %s - %d symbol(s): %s\n", cluster.Package, len(cluster.Symbols), strings.Join(names, ", "))
+		for _, file := range cluster.Files {
+			note := ""
+			if slices.Contains(cluster.SharedFiles, file) {
+				note = " (split: also declares code outside the cluster)"
 			}
+			fmt.Printf("    📄 %s%s\n", a.relativePath(file), note)
+		}
+	}
+}
 
-			exportStatus := "private"
-			if symbol.Exported {
-				exportStatus = "exported"
+// labelKeys returns the display names of the symbol keys listed by the sections of a
+// result rather than as symbols: clusters, cross-checked findings and function storage
+func (a *Analyzer) labelKeys(result *AnalysisResult) map[string]string {
+	labels := make(map[string]string)
+	label := func(keys ...string) {
+		for _, key := range keys {
+			if _, ok := a.symbols[key]; ok {
+				labels[key] = a.describeKey(key)
 			}
+		}
+	}
+	for _, cluster := range result.DeadClusters {
+		label(cluster.Root...)
+		label(cluster.Unlocks...)
+	}
+	for _, cluster := range result.ExtractableClusters {
+		label(cluster.Symbols...)
+	}
+	for _, cluster := range result.SizeClusters {
+		label(cluster.Symbols...)
+	}
+	for _, fn := range result.UncalledFunctions {
+		label(fn.StoredIn...)
+	}
+	if comparison := result.ToolComparison; comparison != nil {
+		for _, finding := range comparison.ToolOnly {
+			label(finding.ReachableVia...)
+		}
+		label(comparison.GorphanageOnly...)
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// describeKey formats a symbol key listed by a section of the result for display
+func (r *AnalysisResult) describeKey(key string) string {
+	if label, ok := r.Labels[key]; ok {
+		return label
+	}
+	return key
+}
+
+// maxPrintedClusters is the number of largest orphan clusters printed
+const maxPrintedClusters = 10
+
+// printSizeClusters lists the orphan clusters with the largest estimated binary size
+func (a *Analyzer) printSizeClusters(result *AnalysisResult) {
+	if len(result.SizeClusters) == 0 {
+		return
+	}
 
-			fmt.Printf("  📍 %s (%s) - %s\n",
-				symbol.Name,
-				exportStatus,
-				formatPosition(relPath, symbol.Start))
+	fmt.Printf("\n📦 Largest orphan clusters by estimated binary size (rough):\n")
+	for i, cluster := range result.SizeClusters {
+		if i == maxPrintedClusters {
+			fmt.Printf("  … %d more cluster(s)\n", len(result.SizeClusters)-i)
+			break
+		}
+		names := make([]string, 0, len(cluster.Symbols))
+		for _, key := range cluster.Symbols {
+			names = append(names, result.describeKey(key))
+		}
+		fmt.Printf("  • %s - %d symbol(s): %s\n", formatBytes(cluster.EstimatedBytes), len(cluster.Symbols), strings.Join(names, ", "))
+	}
+}
+
+// printObsoleteFiles lists files whose build constraints can never be satisfied
+func (a *Analyzer) printObsoleteFiles(result *AnalysisResult) {
+	if len(result.ObsoleteFiles) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🚧 Files whose build constraints can never be satisfied (dead in their entirety):\n")
+	for _, file := range result.ObsoleteFiles {
+		relPath := a.relativePath(file.File)
+		fmt.Printf("  📍 %s - build constraint %q: %s\n", relPath, file.Constraint, file.Reason)
+	}
+}
+
+// printUnreadFields lists struct fields that are never read
+func (a *Analyzer) printUnreadFields(result *AnalysisResult) {
+	if len(result.UnreadFields) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🧱 Struct fields never read (state that is only written, or not at all):\n")
+	for _, field := range result.UnreadFields {
+		relPath := a.relativePath(field.File)
+		writes := "never written"
+		if field.FieldWrites > 0 {
+			writes = fmt.Sprintf("written %d time(s)", field.FieldWrites)
+		}
+		fmt.Printf("  📍 %s - %s [%s]\n", field.displayName(), formatPosition(relPath, field.Start), writes)
+	}
+}
+
+// printUnusedResults lists functions with results that every caller ignores
+func (a *Analyzer) printUnusedResults(result *AnalysisResult) {
+	if len(result.UnusedResults) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📤 Results no caller uses (signatures that can be simplified):\n")
+	for _, fn := range result.UnusedResults {
+		relPath := a.relativePath(fn.File)
+		fmt.Printf("  📍 %s - %s [%s ignored by %d call site(s)]\n",
+			fn.displayName(), formatPosition(relPath, fn.Start), strings.Join(fn.IgnoredResults, ", "), fn.CallSites)
+	}
+}
+
+// printTrivialWrappers lists functions only converting or copying their operands, to
+// inline at their few call sites
+func (a *Analyzer) printTrivialWrappers(result *AnalysisResult) {
+	if len(result.TrivialWrappers) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🎁 Trivial wrappers (to inline at their call site):\n")
+	for _, fn := range result.TrivialWrappers {
+		relPath := a.relativePath(fn.File)
+		fmt.Printf("  📍 %s - %s [%s, %d caller(s)]\n", fn.displayName(), formatPosition(relPath, fn.Start), fn.Wraps, fn.Callers)
+	}
+}
+
+// printMainHelpers lists the unexported orphans of main packages kept apart from the
+// findings with --main-unexported group
+func (a *Analyzer) printMainHelpers(result *AnalysisResult) {
+	if len(result.MainHelpers) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🧰 Unexported helpers of main packages that main() never reaches (not counted as findings):\n")
+	for _, helper := range result.MainHelpers {
+		relPath := a.relativePath(helper.File)
+		fmt.Printf("  📍 %s (%s) - %s\n", helper.displayName(), helper.Kind, formatPosition(relPath, helper.Start))
+	}
+}
+
+// printGeneratedOrphans lists the orphans of generated files kept apart from the findings
+// with --generated separate
+func (a *Analyzer) printGeneratedOrphans(result *AnalysisResult) {
+	if len(result.GeneratedOrphans) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🤖 Orphans in generated files (regenerate or drop the generator input rather than edit):\n")
+	for _, orphan := range result.GeneratedOrphans {
+		relPath := a.relativePath(orphan.File)
+		fmt.Printf("  📍 %s (%s) - %s\n", orphan.displayName(), orphan.Kind, formatPosition(relPath, orphan.Start))
+	}
+}
+
+// printToolComparison reports how the findings imported with --import-findings compare
+// with the orphans
+func (a *Analyzer) printToolComparison(result *AnalysisResult) {
+	comparison := result.ToolComparison
+	if comparison == nil {
+		return
+	}
+
+	fmt.Printf("\n🤝 Cross-check with %s: %d agreed, %d reported by the other tool only, %d by gorphanage only\n",
+		comparison.Source, len(comparison.Agreed), len(comparison.ToolOnly), len(comparison.GorphanageOnly))
+	for _, finding := range comparison.ToolOnly {
+		fmt.Printf("  ⚖️  %s:%d %s (%s), reachable for gorphanage", finding.File, finding.Line, finding.Message, finding.Tool)
+		if len(finding.ReachableVia) > 1 {
+			fmt.Printf(" via %s", result.describeKey(finding.ReachableVia[len(finding.ReachableVia)-2]))
 		}
 		fmt.Println()
 	}
+	for _, key := range comparison.GorphanageOnly {
+		fmt.Printf("  🔎 %s: orphan the other tool doesn't report\n", result.describeKey(key))
+	}
+	for _, finding := range comparison.Unmatched {
+		fmt.Printf("  ❓ %s:%d %s (%s): no analyzed symbol there\n", finding.File, finding.Line, finding.Message, finding.Tool)
+	}
+}
 
-	a.printSummary(result)
+// printWriteOnlyVariables lists package-level variables that are assigned but never read,
+// with their assignments
+func (a *Analyzer) printWriteOnlyVariables(result *AnalysisResult) {
+	if len(result.WriteOnlyVariables) == 0 {
+		return
+	}
+
+	fmt.Printf("\n✏️  Write-only variables (assigned but never read by reachable code):\n")
+	for _, variable := range result.WriteOnlyVariables {
+		relPath := a.relativePath(variable.File)
+		fmt.Printf("  📍 %s - %s\n", variable.Name, formatPosition(relPath, variable.Start))
+		for _, write := range variable.WrittenAt {
+			fmt.Printf("      ✏️  written at %s\n", formatPosition(a.relativePath(write.File), Position{Line: write.Line, Column: write.Column}))
+		}
+	}
+}
+
+// printConstraintPackages lists packages of type constraints none of which is used
+func (a *Analyzer) printConstraintPackages(result *AnalysisResult) {
+	if len(result.UnusedConstraintPackages) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🧬 Constraint packages with no constraint in use (delete the package):\n")
+	for _, pkgPath := range result.UnusedConstraintPackages {
+		fmt.Printf("  • %s\n", pkgPath)
+	}
+}
+
+// printNarrowings lists exported methods hidden behind a constructor's narrow interface
+func (a *Analyzer) printNarrowings(result *AnalysisResult) {
+	if len(result.InterfaceNarrowings) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🪶 Fat structs behind narrow interfaces (exported methods never called):\n")
+	for _, narrowing := range result.InterfaceNarrowings {
+		fmt.Printf("  • %s returns %s as %s\n", narrowing.Constructor, narrowing.Concrete, narrowing.Interface)
+		for _, method := range narrowing.UnusedMethods {
+			relPath := a.relativePath(method.File)
+			fmt.Printf("      📍 %s - %s\n", method.Name, formatPosition(relPath, method.Start))
+		}
+	}
+}
+
+// printDocsOnly lists symbols that are only used by documentation examples
+func (a *Analyzer) printDocsOnly(result *AnalysisResult) {
+	if len(result.DocsOnlySymbols) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📚 Docs-only symbols (reachable only from Example functions):\n")
+	for _, symbol := range result.DocsOnlySymbols {
+		relPath := a.relativePath(symbol.File)
+		fmt.Printf("  📍 %s - %s\n", symbol.displayName(), formatPosition(relPath, symbol.Start))
+	}
+}
+
+// printOrphan prints a single finding with its annotations
+func (a *Analyzer) printOrphan(symbol *Symbol) {
+	relPath := a.relativePath(symbol.File)
+
+	exportStatus := "private"
+	if symbol.Exported {
+		exportStatus = "exported"
+	}
+
+	annotation := ""
+	switch {
+	case symbol.RuntimeObserved:
+		annotation = " [seen in runtime profile - false positive]"
+	case symbol.Coverage == CoverageCovered:
+		annotation = " [covered at runtime - verify before deleting]"
+	case symbol.Coverage == CoverageUncovered:
+		annotation = " [never covered]"
+	}
+	if symbol.Deadness == DeadnessSoft {
+		annotation += " [soft-dead: referenced only by dead code]"
+	}
+	if symbol.NeverInstantiated {
+		annotation += " [generic, never instantiated]"
+	}
+	if symbol.Constraint {
+		annotation += " [type constraint]"
+	}
+	if symbol.Confidence == ConfidenceVerified {
+		annotation += " [verified deletable]"
+	}
+	if symbol.State != "" {
+		annotation += fmt.Sprintf(" [%s]", symbol.State)
+	}
+	if symbol.Generated {
+		annotation += " [generated]"
+	}
+	if symbol.ConfirmedBy != "" {
+		annotation += fmt.Sprintf(" [also reported by %s]", symbol.ConfirmedBy)
+	}
+	if symbol.Reflection {
+		annotation += " [package uses reflect: may be called through reflection]"
+	}
+	if symbol.DeleteWithCare {
+		annotation += " [delete with care: initializer has side effects]"
+	}
+	for _, twin := range symbol.GeneratedTwins {
+		annotation += fmt.Sprintf(" [regenerate or delete %s]", a.relativePath(twin))
+	}
+	if a.showsDepth() {
+		annotation += fmt.Sprintf(" [import depth %d]", symbol.ImportDepth)
+		if symbol.LeafPackage {
+			annotation += " [leaf package]"
+		}
+	}
+	if symbol.ModuleDir != "" {
+		annotation += fmt.Sprintf(" [module %s in %s]", symbol.Module, symbol.ModuleDir)
+	}
+	if symbol.KeptBy != nil {
+		annotation += fmt.Sprintf(" [kept by assertion at %s]", formatPosition(a.relativePath(symbol.KeptBy.File), Position{Line: symbol.KeptBy.Line, Column: symbol.KeptBy.Column}))
+	}
+	if targets := targetsWith(symbol, TargetNotBuilt); len(targets) > 0 {
+		annotation += fmt.Sprintf(" [not built on %s]", strings.Join(targets, ", "))
+	}
+	if targets := targetsWith(symbol, TargetReachable); len(targets) > 0 {
+		annotation += fmt.Sprintf(" [reachable on %s]", strings.Join(targets, ", "))
+	}
+	for _, variant := range symbol.Variants {
+		if variant.Verdict != VariantOrphaned {
+			annotation += fmt.Sprintf(" [variant %s: %s]", a.relativePath(variant.File), variant.Verdict)
+		}
+	}
+
+	fmt.Printf("  📍 %s (%s) - %s%s\n",
+		symbol.displayName(),
+		exportStatus,
+		formatPosition(relPath, symbol.Start),
+		annotation)
 }
 
 // printSummary prints analysis summary and helpful tips
 func (a *Analyzer) printSummary(result *AnalysisResult) {
 	fmt.Println("💡 These symbols are not reachable from any main() or init() function.")
+	if result.Semantics == SemanticsModule {
+		fmt.Println("💡 Module semantics: the exported API of non-internal packages counts as used.")
+	}
 	fmt.Println("💡 Test functions are excluded as they have separate entry points.")
 
 	if result.MainPackages > 0 {
@@ -61,14 +463,136 @@ func (a *Analyzer) printSummary(result *AnalysisResult) {
 	fmt.Printf("  • Total symbols: %d\n", result.TotalSymbols)
 	fmt.Printf("  • Reachable symbols: %d\n", result.ReachableSymbols)
 	fmt.Printf("  • Orphaned symbols: %d\n", len(result.OrphanedSymbols))
+	if result.Precision != "" {
+		fmt.Printf("  • Call precision: %s\n", result.Precision)
+	}
+
+	covered, uncovered, withCare, verified, softDead, uninstantiated, constraints := 0, 0, 0, 0, 0, 0, 0
+	for _, orphan := range result.OrphanedSymbols {
+		if orphan.Deadness == DeadnessSoft {
+			softDead++
+		}
+		if orphan.NeverInstantiated {
+			uninstantiated++
+		}
+		if orphan.Constraint {
+			constraints++
+		}
+		if orphan.Confidence == ConfidenceVerified {
+			verified++
+		}
+		switch orphan.Coverage {
+		case CoverageCovered:
+			covered++
+		case CoverageUncovered:
+			uncovered++
+		}
+		if orphan.DeleteWithCare {
+			withCare++
+		}
+	}
+	if softDead > 0 {
+		fmt.Printf("  • Hard-dead (unreferenced): %d\n", len(result.OrphanedSymbols)-softDead)
+		fmt.Printf("  • Soft-dead (referenced only by dead code, remove after its users): %d\n", softDead)
+	}
+	if uninstantiated > 0 {
+		fmt.Printf("  • Generic orphans never instantiated (deleting removes every instantiation): %d\n", uninstantiated)
+	}
+	if constraints > 0 {
+		fmt.Printf("  • Unused type constraints (no runtime code, only type parameter lists use them): %d\n", constraints)
+	}
+	if verified > 0 {
+		fmt.Printf("  • Orphans verified deletable by probe: %d\n", verified)
+	}
+	if withCare > 0 {
+		fmt.Printf("  • Orphans to delete with care (side-effecting initializers): %d\n", withCare)
+	}
+	if covered+uncovered > 0 {
+		fmt.Printf("  • Orphans with runtime coverage (suspicious): %d\n", covered)
+		fmt.Printf("  • Orphans never covered (safer to delete): %d\n", uncovered)
+	}
+
+	if result.StateCounts != nil {
+		for _, state := range validStates {
+			fmt.Printf("  • %s: %d\n", strings.ToUpper(state[:1])+state[1:], result.StateCounts[state])
+		}
+	}
+	if result.BaselinedOrphans > 0 {
+		fmt.Printf("  • Recorded in the baseline, not listed: %d\n", result.BaselinedOrphans)
+	}
 
 	if result.TotalSymbols > 0 {
 		orphanPercentage := float64(len(result.OrphanedSymbols)) / float64(result.TotalSymbols) * 100
 		fmt.Printf("  • Orphan rate: %.1f%%\n", orphanPercentage)
 	}
+
+	if result.Precision != "" && result.Precision != PrecisionReferences {
+		printPrecisionTradeoffs(result.Precision)
+	}
+
+	if len(result.ByAuthor) > 0 {
+		fmt.Printf("\n👤 Orphaned code by last author (heuristic: git blame, not who made it dead):\n")
+		for _, summary := range result.ByAuthor {
+			fmt.Printf("  • %s: %d symbol(s), %d line(s)\n", summary.Author, summary.Symbols, summary.Lines)
+		}
+	}
+
+	if len(result.ByComponent) > 0 {
+		printComponents(result.ByComponent)
+	}
+
+	if len(result.SuggestedRoots) > 0 {
+		fmt.Printf("\n🔥 Suggested roots (observed in runtime profiles):\n")
+		for _, root := range result.SuggestedRoots {
+			fmt.Printf("  • %s\n", root)
+		}
+	}
+}
+
+// printPrecisionTradeoffs shows what each precision mode resolves and costs, marking the
+// mode that produced the results
+func printPrecisionTradeoffs(precision string) {
+	fmt.Printf("\n🎯 Call precision trade-offs (▶ produced these results):\n")
+	for _, tradeoff := range precisionTradeoffs {
+		marker := " "
+		if tradeoff.mode == precision {
+			marker = "▶"
+		}
+		fmt.Printf("  %s %-10s %-49s %s\n", marker, tradeoff.mode, tradeoff.resolves, tradeoff.cost)
+	}
 }
 
 // formatPosition formats a position for display
 func formatPosition(file string, pos Position) string {
 	return fmt.Sprintf("%s:%d:%d", file, pos.Line, pos.Column)
 }
+
+// printSummaryLine writes the machine-parsable summary line, e.g.
+// "gorphanage: orphans=123 exported=45 rate=3.2% new=2"
+func printSummaryLine(w io.Writer, result *AnalysisResult) {
+	exported := 0
+	for _, orphan := range result.OrphanedSymbols {
+		if orphan.Exported {
+			exported++
+		}
+	}
+
+	rate := 0.0
+	if result.TotalSymbols > 0 {
+		rate = float64(len(result.OrphanedSymbols)) / float64(result.TotalSymbols) * 100
+	}
+
+	fmt.Fprintf(w, "gorphanage: orphans=%d exported=%d rate=%.1f%% new=%d\n",
+		len(result.OrphanedSymbols), exported, rate, countNew(result))
+}
+
+// countNew counts findings that have not been triaged yet
+func countNew(result *AnalysisResult) int {
+	count := 0
+	for _, orphan := range result.OrphanedSymbols {
+		if orphan.State == "" || orphan.State == StateNew {
+			count++
+		}
+	}
+	return count
+}
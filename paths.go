@@ -0,0 +1,33 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// normalizePath cleans a file path and upper-cases a Windows drive letter, so that paths
+// from flags, git and the go command compare equal
+func normalizePath(file string) string {
+	file = filepath.Clean(file)
+	if volume := filepath.VolumeName(file); len(volume) == 2 && volume[1] == ':' {
+		file = strings.ToUpper(volume) + file[len(volume):]
+	}
+	return file
+}
+
+// relativePath makes a file path relative to the project when possible. Reported paths
+// always use forward slashes so that reports and baselines read the same on every OS.
+func (a *Analyzer) relativePath(file string) string {
+	if rel, err := filepath.Rel(a.config.ProjectPath, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(normalizePath(file))
+}
+
+// matchPackagePath matches an import path against a glob. Import paths always use forward
+// slashes; patterns written with the OS separator are converted first.
+func matchPackagePath(pattern, pkgPath string) bool {
+	matched, _ := path.Match(filepath.ToSlash(pattern), pkgPath)
+	return matched
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// PostTokenEnv names the environment variable holding the bearer token sent with --post
+const PostTokenEnv = "GORPHANAGE_POST_TOKEN"
+
+// Attempts and first delay of --post; the delay doubles after every failed attempt
+const (
+	postAttempts = 4
+	postBackoff  = 2 * time.Second
+)
+
+// validatePostURL checks that a --post endpoint is an absolute http or https URL
+func validatePostURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid --post %q (expected an http or https URL)", endpoint)
+	}
+	return nil
+}
+
+// postRun POSTs the result of a run to the --post endpoint, if any, truncated like the JSON
+// output
+func postRun(config *Config, result *AnalysisResult) error {
+	if config.Post == "" {
+		return nil
+	}
+	if err := postResult(config.Post, truncateResult(result, config.MaxFindings), config); err != nil {
+		return fmt.Errorf("posting results to %s: %w", config.Post, err)
+	}
+	return nil
+}
+
+// postResult POSTs the JSON result of a run to endpoint, with the bearer token of
+// GORPHANAGE_POST_TOKEN when set. Network errors, 429 and 5xx responses are retried with
+// exponential backoff; any other response is final.
+func postResult(endpoint string, result *AnalysisResult, config *Config) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	token := os.Getenv(PostTokenEnv)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	delay := postBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := postOnce(client, endpoint, token, data)
+		if err == nil {
+			if config.Verbose && !config.OutputJSON {
+				fmt.Printf("📮 Posted results to %s\n", endpoint)
+			}
+			return nil
+		}
+		if !retry || attempt == postAttempts {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Posting results failed (%v), retrying in %s\n", err, delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// postOnce sends one POST request and reports whether a failure is worth retrying
+func postOnce(client *http.Client, endpoint, token string, data []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gorphanage/"+version)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return false, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/pprof/profile"
+)
+
+// crossCheckProfiles flags orphaned functions that appear in runtime pprof samples
+func (a *Analyzer) crossCheckProfiles(orphans []*Symbol) ([]string, error) {
+	knownPkgs := make(map[string]bool, len(a.packages))
+	for _, pkg := range a.packages {
+		knownPkgs[pkg.PkgPath] = true
+	}
+
+	observed := make(map[string]bool)
+	for _, path := range a.config.PprofProfiles {
+		if err := collectProfileFunctions(path, knownPkgs, observed); err != nil {
+			return nil, err
+		}
+	}
+
+	var roots []string
+	for _, orphan := range orphans {
+		name := orphan.Package + "." + orphan.keyName()
+		if (orphan.Kind != "function" && orphan.Kind != "method") || !observed[name] {
+			continue
+		}
+		orphan.RuntimeObserved = true
+		orphan.Confidence = ConfidenceLow
+		roots = append(roots, name)
+	}
+	sort.Strings(roots)
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🔥 %d orphaned function(s) appear in runtime profiles\n", len(roots))
+	}
+
+	return roots, nil
+}
+
+// collectProfileFunctions records the package-qualified names of all functions in a profile's samples
+func collectProfileFunctions(path string, knownPkgs, observed map[string]bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open profile: %w", err)
+	}
+	defer f.Close()
+
+	prof, err := profile.Parse(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+
+	for _, sample := range prof.Sample {
+		for _, loc := range sample.Location {
+			for _, line := range loc.Line {
+				if line.Function == nil {
+					continue
+				}
+				if pkgPath, name, ok := splitRuntimeFuncName(line.Function.Name, knownPkgs); ok {
+					observed[pkgPath+"."+name] = true
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitRuntimeFuncName splits a runtime symbol such as "example.com/app/pkg.(*T).Method.func1"
+// into its package path and the key name of the declared function or method, Func or
+// Type.Method. Known package paths
+// disambiguate import paths whose last element contains a dot (gopkg.in/yaml.v3).
+func splitRuntimeFuncName(fullName string, knownPkgs map[string]bool) (string, string, bool) {
+	fullName = stripTypeArgs(fullName)
+
+	slash := strings.LastIndex(fullName, "/")
+	dot := strings.Index(fullName[slash+1:], ".")
+	if dot < 0 {
+		return "", "", false
+	}
+	dot += slash + 1
+	for i := dot; i < len(fullName); i++ {
+		if fullName[i] == '.' && knownPkgs[fullName[:i]] {
+			dot = i
+			break
+		}
+	}
+
+	pkgPath := fullName[:dot]
+	parts := strings.Split(fullName[dot+1:], ".")
+
+	// Methods with pointer receivers: (*T).Method
+	if base, ok := strings.CutPrefix(parts[0], "(*"); ok && len(parts) > 1 {
+		return pkgPath, strings.TrimSuffix(base, ")") + "." + parts[1], true
+	}
+
+	// Methods with value receivers: T.Method (closures look like Func.func1 or Func.gowrap1)
+	if len(parts) > 1 && !isClosureSuffix(parts[1]) {
+		return pkgPath, parts[0] + "." + parts[1], true
+	}
+
+	return pkgPath, parts[0], true
+}
+
+// isClosureSuffix reports whether a name component is compiler-generated for closures
+func isClosureSuffix(part string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if rest, ok := strings.CutPrefix(part, prefix); ok && rest != "" && strings.Trim(rest, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// stripTypeArgs removes generic instantiation brackets such as "[...]" or "[go.shape.int]"
+func stripTypeArgs(name string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
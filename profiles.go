@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// profileOnlySettings are config file settings without a command line flag that a
+// profile may still set
+var profileOnlySettings = map[string]bool{
+	"root-rules":          true,
+	"callback-registries": true,
+	"string-registries":   true,
+	"entry-points":        true,
+	"interface-allowlist": true,
+	"well-known-methods":  true,
+}
+
+// applyProfile overlays the settings of the named profile from the config file's
+// "profiles" section. A profile takes precedence over the top-level settings of the
+// config file, but not over flags given on the command line.
+func applyProfile(cmd *cobra.Command, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	profiles := viper.GetStringMap("profiles")
+	raw, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for profile := range profiles {
+			names = append(names, profile)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile %q (the config file defines no profiles)", name)
+		}
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	settings, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("invalid profile %q: expected a map of settings", name)
+	}
+
+	for key, value := range settings {
+		flag := cmd.Root().Flags().Lookup(key)
+		if flag == nil {
+			flag = cmd.Root().PersistentFlags().Lookup(key)
+		}
+		if flag == nil && !profileOnlySettings[key] {
+			return fmt.Errorf("invalid profile %q: unknown setting %q", name, key)
+		}
+		if key == "profile" || key == "config" {
+			return fmt.Errorf("invalid profile %q: %s cannot be set by a profile", name, key)
+		}
+		if changed := cmd.Flags().Lookup(key); changed != nil && changed.Changed {
+			continue
+		}
+		viper.Set(key, value)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// pullRequestTarget is where the fix command proposes its deletions
+type pullRequestTarget struct {
+	host *pullRequestHost
+	base string // branch checked out when the command started
+}
+
+// preparePullRequest checks that deletions can be proposed from the project's repository
+// before any analysis runs: a clean working tree on a branch, and a supported origin
+func preparePullRequest(dir string) (*pullRequestTarget, error) {
+	status, err := gitOutput(dir, "status", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("project is not inside a git repository: %w", err)
+	}
+	if status != "" {
+		return nil, fmt.Errorf("working tree has uncommitted changes; commit or stash them before --open-pr")
+	}
+
+	base, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if base == "HEAD" {
+		return nil, fmt.Errorf("cannot open a pull request from a detached HEAD")
+	}
+
+	remote, err := gitOutput(dir, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the origin remote: %w", err)
+	}
+	host, err := newPullRequestHost(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pullRequestTarget{host: host, base: base}, nil
+}
+
+// fixInPullRequest commits verified deletions on a new branch, pushes it and opens a pull
+// request. The working tree is switched back to the original branch in every case.
+func (a *Analyzer) fixInPullRequest(target *pullRequestTarget, report *FixReport) error {
+	dir := a.config.ProjectPath
+	base := target.base
+
+	report.Branch = "gorphanage/dead-code-" + time.Now().Format("20060102-150405")
+	if _, err := gitOutput(dir, "checkout", "-b", report.Branch); err != nil {
+		return err
+	}
+
+	err := a.commitFixes(report)
+	if _, checkoutErr := gitOutput(dir, "checkout", base); checkoutErr != nil {
+		return fmt.Errorf("failed to switch back to %s: %w", base, checkoutErr)
+	}
+	if err != nil {
+		// Nothing was pushed: drop the local branch
+		gitOutput(dir, "branch", "-D", report.Branch)
+		report.Branch = ""
+		return err
+	}
+
+	title := fmt.Sprintf("Remove %d unreachable symbol(s)", len(report.Deleted))
+	report.PullRequest, err = target.host.open(report.Branch, base, title, a.pullRequestBody(report.Deleted))
+	if err != nil {
+		return fmt.Errorf("branch %s was pushed, but opening the pull request failed: %w", report.Branch, err)
+	}
+	return nil
+}
+
+// commitFixes applies and verifies the deletions on the current branch, commits and pushes them
+func (a *Analyzer) commitFixes(report *FixReport) error {
+	dir := a.config.ProjectPath
+
+	originals, err := a.applyDeletions(report.Deleted)
+	if err != nil {
+		return err
+	}
+	if err := a.verifyFixes(originals); err != nil {
+		restoreFiles(originals)
+		return err
+	}
+
+	var files []string
+	for file := range originals {
+		files = append(files, file)
+	}
+	message := fmt.Sprintf("Remove %d unreachable symbol(s)\n\nDeleted by gorphanage fix after verifying go build ./... and go test ./... .", len(report.Deleted))
+	_, err = gitOutput(dir, append([]string{"add", "--"}, files...)...)
+	if err == nil {
+		_, err = gitOutput(dir, "commit", "-m", message)
+	}
+	if err != nil {
+		// Leave neither staged nor unstaged edits behind
+		gitOutput(dir, append([]string{"reset", "-q", "--"}, files...)...)
+		restoreFiles(originals)
+		return err
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("⬆️  Pushing %s to origin\n", report.Branch)
+	}
+	_, err = gitOutput(dir, "push", "-u", "origin", report.Branch)
+	return err
+}
+
+// pullRequestBody lists the deleted findings in the pull request description
+func (a *Analyzer) pullRequestBody(deleted []*Symbol) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "gorphanage found these symbols unreachable from every entry point. Each deletion was probed by re-type-checking the project without it, and the batch passes `go build ./...` and `go test ./...`.\n\n")
+	for _, symbol := range deleted {
+		fmt.Fprintf(&body, "- `%s.%s` (%s) - `%s`\n", symbol.Package, symbol.displayName(), symbol.Kind, formatPosition(a.relativePath(symbol.File), symbol.Start))
+	}
+	return body.String()
+}
+
+// pullRequestHost opens pull requests on a code hosting service
+type pullRequestHost struct {
+	open func(branch, base, title, body string) (string, error)
+}
+
+// newPullRequestHost selects the hosting API from the origin remote URL
+func newPullRequestHost(remote string) (*pullRequestHost, error) {
+	host, project, err := parseRemote(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case host == "github.com":
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("GITHUB_TOKEN must be set to open a pull request on GitHub")
+		}
+		return &pullRequestHost{open: func(branch, base, title, body string) (string, error) {
+			var created struct {
+				URL string `json:"html_url"`
+			}
+			err := postJSON("https://api.github.com/repos/"+project+"/pulls",
+				map[string]string{"Authorization": "Bearer " + token, "Accept": "application/vnd.github+json"},
+				map[string]string{"head": branch, "base": base, "title": title, "body": body},
+				&created)
+			return created.URL, err
+		}}, nil
+
+	case strings.Contains(host, "gitlab"):
+		token := os.Getenv("GITLAB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("GITLAB_TOKEN must be set to open a merge request on GitLab")
+		}
+		return &pullRequestHost{open: func(branch, base, title, body string) (string, error) {
+			var created struct {
+				URL string `json:"web_url"`
+			}
+			err := postJSON("https://"+host+"/api/v4/projects/"+url.PathEscape(project)+"/merge_requests",
+				map[string]string{"PRIVATE-TOKEN": token},
+				map[string]string{"source_branch": branch, "target_branch": base, "title": title, "description": body},
+				&created)
+			return created.URL, err
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported remote host %s (expected github.com or a GitLab instance)", host)
+}
+
+// parseRemote extracts the host and owner/repo path from a git remote URL in https, ssh or
+// scp-like form
+func parseRemote(remote string) (string, string, error) {
+	var host, project string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, project = u.Hostname(), u.Path
+	} else if at := strings.Index(remote, "@"); at >= 0 && strings.Contains(remote[at:], ":") {
+		host, project, _ = strings.Cut(remote[at+1:], ":")
+	} else {
+		return "", "", fmt.Errorf("unrecognized remote URL %q", remote)
+	}
+
+	project = strings.TrimSuffix(strings.Trim(project, "/"), ".git")
+	if project == "" {
+		return "", "", fmt.Errorf("unrecognized remote URL %q", remote)
+	}
+	return host, project, nil
+}
+
+// postJSON sends a JSON API request and decodes the JSON response into result
+func postJSON(endpoint string, headers map[string]string, payload any, result any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -2,8 +2,12 @@ package main
 
 import (
 	"fmt"
-	"go/ast"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -16,173 +20,407 @@ func (a *Analyzer) traceReachability() error {
 
 	// Start from all entry points in main packages
 	queue := a.findEntryPoints()
+	if a.program != nil {
+		queue = a.applyCallGraph(queue)
+	}
 
 	if a.config.Verbose && !a.config.OutputJSON {
 		fmt.Printf("🎯 Starting with %d entry points\n", len(queue))
 	}
 
-	// BFS to find all reachable symbols
-	visited := make(map[string]bool)
+	// Example reachability is independent of the entry points, so streamed verdicts can
+	// already tell docs-only symbols apart
+	if a.config.IncludeTests {
+		a.traceExamples()
+	}
 
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
+	start := time.Now()
+	if a.config.Stream && !a.config.OutputJSON {
+		a.traverseStreaming(queue)
+	} else {
+		a.traverse(queue)
+	}
+	elapsed := time.Since(start)
+	a.traceKeepAlives(queue)
 
-		if visited[current] {
-			continue
+	reachableCount := a.reachableCount
+	totalCount := len(a.symbols)
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("📊 Reachability analysis: %d/%d symbols reachable from main packages (traced in %s on %d CPU(s))\n",
+			reachableCount, totalCount, elapsed.Round(time.Microsecond), runtime.GOMAXPROCS(0))
+	}
+
+	return nil
+}
+
+// frontierChunk is the number of frontier symbols a worker takes at a time
+const frontierChunk = 32
+
+// traverse runs a level-synchronous parallel BFS over the symbol graph from the entry
+// points. Workers pull chunks of the current frontier from a shared cursor, so idle
+// workers pick up the remaining work of busy ones. The visited set is a dense array by
+// symbol ID claimed atomically, so workers on different packages share no lock.
+//
+// A symbol first reached on a level keeps the smallest ID among the frontier symbols
+// referencing it as its parent, whichever worker gets to it first: the chains explain
+// and --list-reachable print don't depend on scheduling.
+func (a *Analyzer) traverse(entryPoints []int32) {
+	g := a.graph
+
+	// reached[id] is 0 while unreached, 1 for entry points and parent+2 otherwise. Claims
+	// on the current level are negative, -(parent+2), and settled once the level is done.
+	reached := make([]atomic.Int32, g.size())
+	var frontier []int32
+	for _, id := range entryPoints {
+		if reached[id].CompareAndSwap(0, 1) {
+			frontier = append(frontier, id)
 		}
-		visited[current] = true
+	}
 
-		// Find all symbols referenced by the current symbol
-		referencedSymbols := a.findReferencedSymbols(current)
+	// claim proposes parent for target, reporting whether target was reached first
+	claim := func(target, parent int32) bool {
+		proposed := -(parent + 2)
+		for {
+			old := reached[target].Load()
+			switch {
+			case old > 0 || old < 0 && proposed <= old:
+				return false
+			case old == 0:
+				if reached[target].CompareAndSwap(0, proposed) {
+					return true
+				}
+			default:
+				if reached[target].CompareAndSwap(old, proposed) {
+					return false
+				}
+			}
+		}
+	}
+
+	for len(frontier) > 0 {
+		// Small levels aren't worth the goroutines
+		workers := min(runtime.GOMAXPROCS(0), (len(frontier)+frontierChunk-1)/frontierChunk)
+		var cursor atomic.Int64
+		next := make([][]int32, workers)
+
+		work := func(w int) {
+			for {
+				start := int(cursor.Add(frontierChunk)) - frontierChunk
+				if start >= len(frontier) {
+					return
+				}
+				end := min(start+frontierChunk, len(frontier))
+				for _, current := range frontier[start:end] {
+					// Referenced symbols and both ends of alias chains
+					for _, target := range g.successors(current) {
+						if claim(target, current) {
+							next[w] = append(next[w], target)
+						}
+					}
+				}
+			}
+		}
+		if workers == 1 {
+			work(0)
+		} else {
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					work(w)
+				}(w)
+			}
+			wg.Wait()
+		}
 
-		for _, refSymbol := range referencedSymbols {
-			if !a.reachable[refSymbol] {
-				a.reachable[refSymbol] = true
-				queue = append(queue, refSymbol)
+		frontier = frontier[:0]
+		for _, ids := range next {
+			for _, id := range ids {
+				reached[id].Store(-reached[id].Load())
+				frontier = append(frontier, id)
 			}
 		}
 	}
 
-	reachableCount := len(a.reachable)
-	totalCount := len(a.symbols)
-	if a.config.Verbose && !a.config.OutputJSON {
-		fmt.Printf("📊 Reachability analysis: %d/%d symbols reachable from main packages\n",
-			reachableCount, totalCount)
+	a.reached = make([]int32, len(reached))
+	for id := range reached {
+		a.reached[id] = reached[id].Load()
+		if a.reached[id] != 0 {
+			a.reachableCount++
+		}
 	}
+}
 
-	return nil
+// isReachable reports whether a symbol was reached from an entry point
+func (a *Analyzer) isReachable(symbolKey string) bool {
+	id, ok := a.graph.ids[symbolKey]
+	return ok && int(id) < len(a.reached) && a.reached[id] != 0
 }
 
 // findEntryPoints identifies all entry points for reachability analysis
-func (a *Analyzer) findEntryPoints() []string {
-	var queue []string
+func (a *Analyzer) findEntryPoints() []int32 {
+	var queue []int32
+	queued := make(map[int32]bool)
+	enqueue := func(key, reason string) {
+		id := a.graph.ids[key]
+		if !queued[id] {
+			queued[id] = true
+			queue = append(queue, id)
+			a.emitRoot(key, reason)
+		}
+	}
 
 	// Add main functions and init functions as entry points
 	for _, pkg := range a.mainPackages {
-		mainKey := a.getSymbolKey(pkg.PkgPath, "main", "function")
+		mainKey := a.getSymbolKey(a.keyPath(pkg.Types), "main", "function")
 		if _, exists := a.symbols[mainKey]; exists {
-			queue = append(queue, mainKey)
-			a.reachable[mainKey] = true
+			enqueue(mainKey, RootReasonMain)
 		}
 
 		// Also add init functions as entry points
-		initKey := a.getSymbolKey(pkg.PkgPath, "init", "function")
+		initKey := a.getSymbolKey(a.keyPath(pkg.Types), "init", "function")
 		if _, exists := a.symbols[initKey]; exists {
-			queue = append(queue, initKey)
-			a.reachable[initKey] = true
+			enqueue(initKey, RootReasonInit)
 		}
 
 		// Add all exported symbols from main packages as potentially reachable
 		// (they might be called by tests or external tools)
 		for symbolKey, symbol := range a.symbols {
-			if symbol.Package == pkg.PkgPath && symbol.Exported {
-				if !a.reachable[symbolKey] {
-					queue = append(queue, symbolKey)
-					a.reachable[symbolKey] = true
-				}
+			if symbol.keyPackage() == a.keyPath(pkg.Types) && symbol.Exported {
+				enqueue(symbolKey, RootReasonMainExport)
+			}
+		}
+	}
+
+	// Under module semantics the public API is used by importers outside the project,
+	// and init functions of imported packages always run
+	if a.semantics == SemanticsModule {
+		for symbolKey, symbol := range a.symbols {
+			if symbol.Kind == "function" && symbol.Name == "init" {
+				enqueue(symbolKey, RootReasonInit)
+			}
+			if symbol.Exported && !isInternalPackage(symbol.Package) && !a.isMainPackage(symbol.Package) {
+				enqueue(symbolKey, RootReasonPublicAPI)
 			}
 		}
 	}
 
+	// Packages linked into a binary run their init functions and blank variable
+	// initializers at startup
+	linked := a.linkedPackages()
+	for _, keyPath := range linked {
+		initKey := a.getSymbolKey(keyPath, "init", "function")
+		if _, exists := a.symbols[initKey]; exists {
+			enqueue(initKey, RootReasonInit)
+		}
+		for _, use := range a.initUses[keyPath] {
+			enqueue(a.graph.keys[use.To], RootReasonInitializer)
+		}
+	}
+
+	// Symbols matching configured naming conventions are invoked indirectly
+	for _, key := range a.findRuleRoots() {
+		enqueue(key, RootReasonRule)
+	}
+
+	// Finalizers, pool constructors and other registered callbacks are invoked by code
+	// outside the analysis
+	for _, key := range a.callbackRoots() {
+		enqueue(key, RootReasonCallback)
+	}
+
+	// HTTP handlers, RPC services and command actions are invoked by their framework
+	for _, key := range a.frameworkRoots() {
+		enqueue(key, RootReasonFramework)
+	}
+
+	// Methods of types handed to reflect may be called by name
+	for _, key := range a.reflectionRoots() {
+		enqueue(key, RootReasonReflection)
+	}
+
+	// The linker and assembly code reference symbols by name, out of sight of Go source
+	for _, key := range a.linknameRoots() {
+		enqueue(key, RootReasonLinkname)
+	}
+	for _, key := range a.assemblyRoots() {
+		enqueue(key, RootReasonAssembly)
+	}
+
+	// Functions exported to C are called from C code
+	for _, key := range a.cgoRoots() {
+		enqueue(key, RootReasonCgoExport)
+	}
+
+	// Functions declared as entry points are invoked by frameworks, schedulers or
+	// generated code
+	for _, key := range a.entryPointRoots() {
+		enqueue(key, RootReasonEntryPoint)
+	}
+
+	// Methods of externally consumed contracts are called outside the module
+	for _, key := range a.allowlistRoots() {
+		enqueue(key, RootReasonAllowlist)
+	}
+
+	// Keep-alive assertions come last, so that what they root counts as kept by them only
+	// when nothing else roots it. Interface assertions keep the methods they check.
+	a.keepAliveRoots = nil
+	keep := func(key, reason string) {
+		id := a.graph.ids[key]
+		if queued[id] {
+			return
+		}
+		a.keepAliveRoots = append(a.keepAliveRoots, id)
+		if a.config.IgnoreAssertions {
+			queued[id] = true
+			return
+		}
+		enqueue(key, reason)
+	}
+	asserted := 0
+	for _, keyPath := range linked {
+		for _, use := range a.keepAliveUses[keyPath] {
+			keep(a.graph.keys[use.To], RootReasonKeepAlive)
+		}
+		for _, id := range a.assertedMethods[keyPath] {
+			keep(a.graph.keys[id], RootReasonAssertion)
+			asserted++
+		}
+	}
+	if a.config.Verbose && !a.config.OutputJSON {
+		if a.config.IgnoreAssertions && len(a.keepAliveRoots) > 0 {
+			fmt.Printf("🙈 Ignoring %d symbol(s) rooted only by keep-alive assertions\n", len(a.keepAliveRoots))
+		} else if asserted > 0 {
+			fmt.Printf("✅ %d method(s) kept by compile-time interface assertions\n", asserted)
+		}
+	}
+
 	return queue
 }
 
+// linkedPackages returns the key paths of the project packages whose initialization runs:
+// those imported, directly or not, by a main package, or every package under module
+// semantics
+func (a *Analyzer) linkedPackages() []string {
+	project := make(map[*packages.Package]bool, len(a.packages))
+	for _, pkg := range a.packages {
+		project[pkg] = true
+	}
+
+	linked := make(map[string]bool)
+	visited := make(map[*packages.Package]bool)
+	var link func(pkg *packages.Package)
+	link = func(pkg *packages.Package) {
+		if !project[pkg] || visited[pkg] {
+			return
+		}
+		visited[pkg] = true
+		linked[a.keyPath(pkg.Types)] = true
+		for _, imported := range pkg.Imports {
+			link(imported)
+		}
+	}
+
+	if a.semantics == SemanticsModule {
+		for _, pkg := range a.packages {
+			link(pkg)
+		}
+	}
+	for _, pkg := range a.mainPackages {
+		link(pkg)
+	}
+
+	paths := make([]string, 0, len(linked))
+	for keyPath := range linked {
+		paths = append(paths, keyPath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
 // findReferencedSymbols finds all symbols referenced by a given symbol
 func (a *Analyzer) findReferencedSymbols(symbolKey string) []string {
 	var referenced []string
+	for _, edge := range a.findReferenceEdges(symbolKey) {
+		referenced = append(referenced, edge.To)
+	}
+	return referenced
+}
 
-	// Get the symbol to find its file(s)
-	symbol, exists := a.symbols[symbolKey]
-	if !exists {
+// findReferenceEdges finds all references from a given symbol to other symbols
+func (a *Analyzer) findReferenceEdges(symbolKey string) []Edge {
+	var referenced []Edge
+
+	// References were attributed to their declaration during the reference walk
+	id, ok := a.graph.ids[symbolKey]
+	if !ok {
 		return referenced
 	}
-
-	// Find all packages that might contain this symbol's definition
-	var relevantPackages []*packages.Package
-	for _, pkg := range a.packages {
-		if pkg.PkgPath == symbol.Package {
-			relevantPackages = append(relevantPackages, pkg)
-		}
-	}
-
-	// Look through the symbol's definition files for references to other symbols
-	for _, pkg := range relevantPackages {
-		for _, file := range pkg.Syntax {
-			// Check if this file contains our symbol
-			if a.fileContainsSymbol(file, symbol) {
-				// Find all references in this file
-				ast.Inspect(file, func(n ast.Node) bool {
-					switch node := n.(type) {
-					case *ast.Ident:
-						if obj := pkg.TypesInfo.Uses[node]; obj != nil {
-							kind := a.getObjectKind(obj)
-							pkgPath := ""
-							if obj.Pkg() != nil {
-								pkgPath = obj.Pkg().Path()
-							}
-							refKey := a.getSymbolKey(pkgPath, obj.Name(), kind)
-
-							// Only add if it's a different symbol
-							if refKey != symbolKey {
-								referenced = append(referenced, refKey)
-							}
-						}
-					case *ast.SelectorExpr:
-						if obj := pkg.TypesInfo.Uses[node.Sel]; obj != nil {
-							kind := a.getObjectKind(obj)
-							pkgPath := ""
-							if obj.Pkg() != nil {
-								pkgPath = obj.Pkg().Path()
-							}
-							refKey := a.getSymbolKey(pkgPath, obj.Name(), kind)
-
-							if refKey != symbolKey {
-								referenced = append(referenced, refKey)
-							}
-						}
-					}
-					return true
-				})
-			}
+	for _, use := range a.declUses[id] {
+		// Only add if it's a different symbol
+		if use.To != id {
+			referenced = append(referenced, Edge{
+				From:     symbolKey,
+				To:       a.graph.keys[use.To],
+				Position: a.fileSet.Position(use.Pos),
+				Kind:     edgeKinds[use.Kind],
+			})
 		}
 	}
 
 	return referenced
 }
 
-// fileContainsSymbol checks if a file contains the declaration of a given symbol
-func (a *Analyzer) fileContainsSymbol(file *ast.File, symbol *Symbol) bool {
-	contains := false
-	ast.Inspect(file, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.FuncDecl:
-			if node.Name != nil && node.Name.Name == symbol.Name && symbol.Kind == "function" {
-				contains = true
-				return false
-			}
-		case *ast.GenDecl:
-			for _, spec := range node.Specs {
-				switch s := spec.(type) {
-				case *ast.TypeSpec:
-					if s.Name != nil && s.Name.Name == symbol.Name && symbol.Kind == "type" {
-						contains = true
-						return false
-					}
-				case *ast.ValueSpec:
-					for _, name := range s.Names {
-						if name != nil && name.Name == symbol.Name &&
-							(symbol.Kind == "variable" || symbol.Kind == "constant") {
-							contains = true
-							return false
-						}
-					}
+// referrers returns the project symbols whose declarations reference a symbol. The reverse
+// index is built from the collected references on first use, so explaining many symbols
+// costs one pass over the references rather than one per symbol.
+func (a *Analyzer) referrers(id int32) []int32 {
+	if a.referrerIndex == nil {
+		a.referrerIndex = make(map[int32][]int32)
+		for from, uses := range a.declUses {
+			seen := make(map[int32]bool, len(uses))
+			for _, use := range uses {
+				if use.To != from && !seen[use.To] {
+					seen[use.To] = true
+					a.referrerIndex[use.To] = append(a.referrerIndex[use.To], from)
 				}
 			}
 		}
-		return true
-	})
-	return contains
+	}
+	return a.referrerIndex[id]
+}
+
+// symbolEdges computes the outgoing references of every project symbol, once per
+// referenced symbol and edge kind
+func (a *Analyzer) symbolEdges() map[string][]Edge {
+	edges := make(map[string][]Edge, len(a.symbols))
+	for key := range a.symbols {
+		seen := make(map[[2]string]bool)
+		for _, edge := range a.findReferenceEdges(key) {
+			if !seen[[2]string{edge.To, edge.Kind}] {
+				seen[[2]string{edge.To, edge.Kind}] = true
+				edges[key] = append(edges[key], edge)
+			}
+		}
+	}
+	return edges
+}
+
+// reachabilityPath returns the chain of symbols through which a reachable symbol was first
+// reached, starting at its entry point
+func (a *Analyzer) reachabilityPath(symbolKey string) []string {
+	if !a.isReachable(symbolKey) {
+		return nil
+	}
+
+	path := []string{symbolKey}
+	for id := a.graph.ids[symbolKey]; a.reached[id] > 1; {
+		id = a.reached[id] - 2
+		path = append([]string{a.graph.keys[id]}, path...)
+	}
+	return path
 }
 
 // findOrphans identifies symbols that are not reachable from main packages
@@ -191,12 +429,13 @@ func (a *Analyzer) findOrphans() []*Symbol {
 
 	for key, symbol := range a.symbols {
 		// Skip test functions as they have their own entry points
-		if a.isTestFunction(symbol.Name) {
+		if a.isTestFunction(symbol.Name) || !a.inShard(symbol.Package) || !a.inDepthRange(symbol.Package) {
 			continue
 		}
 
-		// If the symbol is not reachable from any main package, it's orphaned
-		if !a.reachable[key] {
+		// If the symbol is not reachable from any main package or example, it's orphaned
+		if !a.isReachable(key) && !a.isDocsOnly(key) {
+			a.markOrphan(key, symbol)
 			orphans = append(orphans, symbol)
 		}
 	}
@@ -204,6 +443,42 @@ func (a *Analyzer) findOrphans() []*Symbol {
 	return orphans
 }
 
+// markOrphan sets the verdict annotations every orphan starts with
+func (a *Analyzer) markOrphan(key string, symbol *Symbol) {
+	symbol.Confidence = ConfidenceMedium
+	symbol.Deadness = DeadnessHard
+	if a.hasExternalReferences(key, symbol) {
+		symbol.Deadness = DeadnessSoft
+	}
+	symbol.NeverInstantiated = symbol.Generic && !a.isInstantiated(key)
+	if a.config.Reflection == ReflectionConservative && symbol.Kind == "method" && symbol.Exported && a.usesReflection(symbol.Package) {
+		symbol.Confidence = ConfidenceLow
+		symbol.Reflection = true
+	}
+	symbol.Generated = a.generatedFiles[symbol.File]
+	symbol.KeptBy = a.keptBy(a.graph.ids[key])
+	layer := a.packageLayers()[symbol.Package]
+	symbol.ImportDepth, symbol.LeafPackage = layer.depth, layer.leaf
+}
+
+// Deadness classifications for orphans
+const (
+	DeadnessHard = "hard" // no references at all
+	DeadnessSoft = "soft" // referenced, but only from other dead code
+)
+
+// hasExternalReferences reports whether a symbol is referenced from outside its own declaration
+func (a *Analyzer) hasExternalReferences(key string, symbol *Symbol) bool {
+	for _, ref := range a.referencesTo(key) {
+		selfReference := ref.File == symbol.File &&
+			ref.Position.Line >= symbol.Start.Line && ref.Position.Line <= symbol.End.Line
+		if !selfReference {
+			return true
+		}
+	}
+	return false
+}
+
 // isTestFunction checks if a function name indicates it's a test function
 func (a *Analyzer) isTestFunction(name string) bool {
 	return strings.HasPrefix(name, "Test") ||
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ModuleSummary is the breakdown of one module of a --recursive analysis
+type ModuleSummary struct {
+	Module    string `json:"module"`
+	Dir       string `json:"dir"` // relative to the project
+	Semantics string `json:"semantics"`
+	Symbols   int    `json:"symbols"`
+	Reachable int    `json:"reachable"`
+	Orphans   int    `json:"orphans"`
+}
+
+// recursiveModules returns the directories of every module at or below the project root,
+// the root first when it is a module itself
+func (a *Analyzer) recursiveModules() ([]string, error) {
+	var dirs []string
+	if _, err := os.Stat(filepath.Join(a.config.ProjectPath, "go.mod")); err == nil {
+		dirs = append(dirs, a.config.ProjectPath)
+	}
+	nested, err := findNestedModules(a.config.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range nested {
+		if a.skippedDirectory(dir) == "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no go.mod found in %s or below", a.config.ProjectPath)
+	}
+	return dirs, nil
+}
+
+// analyzeRecursive analyzes every module of a monorepo on its own, with its own main
+// packages and semantics, and merges the results into one report broken down by module.
+// The baseline and component manifest of the project root apply to every module.
+func (a *Analyzer) analyzeRecursive() (*AnalysisResult, error) {
+	dirs, err := a.recursiveModules()
+	if err != nil {
+		return nil, err
+	}
+
+	shared := *a.config
+	shared.Recursive = false
+	if shared.BaselineFile == "" {
+		if path := resolveBaselinePath(a.config.ProjectPath, ""); fileExists(path) {
+			shared.BaselineFile = path
+		}
+	}
+	if shared.ComponentsFile == "" {
+		if path := filepath.Join(a.config.ProjectPath, DefaultComponentsFile); fileExists(path) {
+			shared.ComponentsFile = path
+		}
+	}
+
+	var merged *AnalysisResult
+	for i, dir := range dirs {
+		modulePath := dir
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			modulePath = modfile.ModulePath(data)
+		}
+		if a.config.Verbose && !a.config.OutputJSON {
+			fmt.Printf("📦 Analyzing module %s (%d/%d)\n", modulePath, i+1, len(dirs))
+		}
+
+		config := shared
+		config.ProjectPath = dir
+		analyzer := &Analyzer{config: &config, moduleOnly: true}
+		result, err := analyzer.Analyze()
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", modulePath, err)
+		}
+
+		summary := &ModuleSummary{
+			Module:    modulePath,
+			Dir:       a.relativePath(dir),
+			Semantics: result.Semantics,
+			Symbols:   result.TotalSymbols,
+			Reachable: result.ReachableSymbols,
+			Orphans:   len(result.OrphanedSymbols),
+		}
+		result.ProjectPath = a.config.ProjectPath
+		result.Modules = []*ModuleSummary{summary}
+		if merged == nil {
+			merged = result
+			continue
+		}
+
+		// Semantics are resolved per module; the merged result reports them as auto
+		// when the modules disagree
+		semantics := merged.Semantics
+		result.Semantics = semantics
+		if summary.Semantics != semantics {
+			semantics = SemanticsAuto
+		}
+		if merged, err = MergeResults(merged, result); err != nil {
+			return nil, fmt.Errorf("module %s: %w", modulePath, err)
+		}
+		merged.Semantics = semantics
+	}
+	sortOrphans(merged.OrphanedSymbols, a.config.Sort)
+	return merged, nil
+}
+
+// recursiveIncompatible names the first requested output that needs the state of a single
+// analyzer, which a --recursive analysis does not have, or returns ""
+func recursiveIncompatible(config *Config) string {
+	if !config.Recursive {
+		return ""
+	}
+	switch {
+	case config.ExportDB != "":
+		return "--export-db"
+	case config.DumpGraph != "":
+		return "--dump-graph"
+	case config.ListReachable != "":
+		return "--list-reachable"
+	case config.WriteTodos:
+		return "--write-todos"
+	case config.Stream && !config.OutputJSON:
+		return "--stream"
+	}
+	return ""
+}
+
+// fileExists reports whether a file exists at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// printModules prints the breakdown of a --recursive analysis by module
+func (a *Analyzer) printModules(result *AnalysisResult) {
+	if len(result.Modules) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📦 Modules (each analyzed on its own):\n")
+	for _, summary := range result.Modules {
+		rate := 0.0
+		if summary.Symbols > 0 {
+			rate = float64(summary.Orphans) / float64(summary.Symbols) * 100
+		}
+		fmt.Printf("  • %s in %s (%s semantics) - %d symbol(s), %d reachable, %d orphan(s), %.1f%%\n",
+			summary.Module, summary.Dir, summary.Semantics, summary.Symbols, summary.Reachable, summary.Orphans, rate)
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 
 	"golang.org/x/tools/go/packages"
@@ -13,76 +15,257 @@ func (a *Analyzer) findReferences() error {
 		for _, file := range pkg.Syntax {
 			a.findReferencesInFile(pkg, file)
 		}
+		if err := a.findAssemblyReferences(pkg); err != nil {
+			return err
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && a.namedReferences > 0 {
+		fmt.Printf("🔤 %d symbol reference(s) made by name through string registries\n", a.namedReferences)
 	}
 	return nil
 }
 
-// findReferencesInFile finds all symbol references in a single file
+// findReferencesInFile finds all symbol references in a single file. Each reference is
+// attributed to the top-level declaration it appears in, so that reachability never has to
+// walk the syntax tree again and a live symbol only keeps alive what it uses itself.
 func (a *Analyzer) findReferencesInFile(pkg *packages.Package, file *ast.File) {
-	ast.Inspect(file, func(n ast.Node) bool {
-		switch node := n.(type) {
-		case *ast.Ident:
-			a.processIdentReference(pkg, node)
-		case *ast.SelectorExpr:
-			a.processSelectorReference(pkg, node)
+	// Test variants of a package share files; their references are identical
+	filename := a.fileSet.Position(file.Package).Filename
+	if a.walkedFiles[filename] {
+		return
+	}
+	a.walkedFiles[filename] = true
+
+	// A selector's identifier is visited both through the selector and on its own
+	recorded := make(map[token.Pos]bool)
+
+	for _, decl := range file.Decls {
+		for _, part := range a.declarationParts(pkg, decl) {
+			var uses []fileUse
+			context := newEdgeContext()
+			record := func(id int32, pos token.Pos, obj types.Object) {
+				if recorded[pos] {
+					return
+				}
+				recorded[pos] = true
+
+				a.references[id] = append(a.references[id], pos)
+				uses = append(uses, fileUse{To: id, Kind: context.kind(obj, pos), Pos: pos})
+			}
+
+			a.recordVarWrites(pkg, part.node)
+			a.recordFuncSinks(pkg, part.node)
+			ast.Inspect(part.node, func(n ast.Node) bool {
+				context.visit(n)
+				switch node := n.(type) {
+				case *ast.Ident:
+					a.processIdentReference(pkg, node, record)
+				case *ast.SelectorExpr:
+					a.processSelectorReference(pkg, node, record)
+				case *ast.CallExpr:
+					uses = append(uses, a.stringRegistrations(pkg, node)...)
+				}
+				return true
+			})
+
+			for _, owner := range part.owners {
+				a.declUses[owner] = append(a.declUses[owner], uses...)
+			}
+			if part.runsAtInit {
+				keyPath := a.keyPath(pkg.Types)
+				spec := part.node.(*ast.ValueSpec)
+				if isKeepAlive(pkg.TypesInfo, spec) {
+					a.keepAliveUses[keyPath] = append(a.keepAliveUses[keyPath], uses...)
+					for _, use := range uses {
+						a.recordKeepAlive(use.To, use.Pos)
+					}
+				} else {
+					a.initUses[keyPath] = append(a.initUses[keyPath], uses...)
+				}
+				a.recordAssertions(pkg, spec)
+			}
 		}
-		return true
-	})
+	}
+
+	a.trackInstantiations(pkg, file)
+	a.findCallbackRegistrations(pkg, file)
+	a.findFrameworkRegistrations(pkg, file)
+	a.findLinknames(pkg, file)
+	a.findCgoExports(pkg, file)
+	if a.config.Reflection == ReflectionConservative {
+		a.findReflectedTypes(pkg, file)
+	}
+}
+
+// declPart is a piece of a top-level declaration together with the project symbols owning
+// the references made in it
+type declPart struct {
+	node       ast.Node
+	owners     []int32
+	runsAtInit bool // initializer of blank variables, run when the package is linked
+}
+
+// declarationParts splits a top-level declaration into the parts whose references belong
+// to the same symbols: a function or method declaration as a whole, and each type or value
+// spec of a general declaration. All names of a spec share its references (var a, b = f()).
+func (a *Analyzer) declarationParts(pkg *packages.Package, decl ast.Decl) []declPart {
+	owner := func(name *ast.Ident) []int32 {
+		if id, ok := a.objectIDs[pkg.TypesInfo.Defs[name]]; ok {
+			return []int32{id}
+		}
+		return nil
+	}
+
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Name == nil {
+			return nil
+		}
+		return []declPart{{node: d, owners: owner(d.Name)}}
+
+	case *ast.GenDecl:
+		var parts []declPart
+		for i, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				parts = append(parts, declPart{node: s, owners: owner(s.Name)})
+
+			case *ast.ValueSpec:
+				var owners []int32
+				for _, name := range s.Names {
+					owners = append(owners, owner(name)...)
+				}
+				// Constants without a type and values repeat those of the spec before them
+				if d.Tok == token.CONST && len(s.Values) > 0 {
+					for _, next := range d.Specs[i+1:] {
+						repeated := next.(*ast.ValueSpec)
+						if repeated.Type != nil || len(repeated.Values) > 0 {
+							break
+						}
+						for _, name := range repeated.Names {
+							owners = append(owners, owner(name)...)
+						}
+					}
+				}
+
+				parts = append(parts, declPart{
+					node:       s,
+					owners:     owners,
+					runsAtInit: d.Tok == token.VAR && len(owners) == 0 && len(s.Values) > 0,
+				})
+			}
+		}
+		return parts
+	}
+
+	return nil
+}
+
+// releaseSyntax drops the syntax trees and type information of all packages once every
+// file has been walked, so that large projects don't keep them alive during reachability
+func (a *Analyzer) releaseSyntax() {
+	for _, pkg := range a.packages {
+		pkg.Syntax = nil
+		pkg.TypesInfo = nil
+	}
 }
 
 // processIdentReference processes identifier references
-func (a *Analyzer) processIdentReference(pkg *packages.Package, node *ast.Ident) {
+func (a *Analyzer) processIdentReference(pkg *packages.Package, node *ast.Ident, record func(int32, token.Pos, types.Object)) {
 	// Check if this identifier is being used (not declared)
 	obj := pkg.TypesInfo.Uses[node]
 	if obj == nil {
 		return
 	}
+	a.recordMethodUse(obj)
 
-	pos := a.fileSet.Position(node.Pos())
-	kind := a.getObjectKind(obj)
-
-	// Get package path, handling nil package
-	pkgPath := ""
-	if obj.Pkg() != nil {
-		pkgPath = obj.Pkg().Path()
+	if id, ok := a.objectID(obj); ok {
+		record(id, node.Pos(), obj)
 	}
-
-	key := a.getSymbolKey(pkgPath, obj.Name(), kind)
-
-	a.references[key] = append(a.references[key], Reference{
-		File:     pos.Filename,
-		Position: pos,
-	})
 }
 
 // processSelectorReference processes selector expression references (pkg.Symbol)
-func (a *Analyzer) processSelectorReference(pkg *packages.Package, node *ast.SelectorExpr) {
+func (a *Analyzer) processSelectorReference(pkg *packages.Package, node *ast.SelectorExpr, record func(int32, token.Pos, types.Object)) {
 	obj := pkg.TypesInfo.Uses[node.Sel]
 	if obj == nil {
 		return
 	}
 
-	pos := a.fileSet.Position(node.Sel.Pos())
+	if id, ok := a.objectID(obj); ok {
+		record(id, node.Sel.Pos(), obj)
+	}
+}
+
+// objectID returns the interned ID of the symbol a types.Object denotes. Objects of project
+// packages are identified by the declaration defining them, so locals, parameters, fields
+// and interface methods never stand for a package symbol of the same name; instantiations
+// denote their generic origin. Objects of other packages are keyed by name, methods by
+// the base type of their receiver. Interface methods, wherever declared, denote the
+// invocation of that method on whatever implements the interface.
+func (a *Analyzer) objectID(obj types.Object) (int32, bool) {
+	switch o := obj.(type) {
+	case *types.Func:
+		if isInterfaceMethod(o) {
+			return a.interfaceMethodID(o), true
+		}
+		obj = o.Origin()
+	case *types.Var:
+		obj = o.Origin()
+	}
+	if id, ok := a.objectIDs[obj]; ok {
+		return id, true
+	}
+	if obj.Pkg() == nil || a.projectPkgs[obj.Pkg().Path()] {
+		return 0, false
+	}
+
 	kind := a.getObjectKind(obj)
+	if kind == "method" {
+		return a.symbolID(a.keyPath(obj.Pkg()), methodName(obj.(*types.Func)), kind), true
+	}
+	return a.symbolID(a.keyPath(obj.Pkg()), obj.Name(), kind), true
+}
 
-	// Get package path, handling nil package
-	pkgPath := ""
-	if obj.Pkg() != nil {
-		pkgPath = obj.Pkg().Path()
+// methodName returns the key name of a method, Type.Method, where Type is the base type
+// of the receiver of its generic origin. Methods of unnamed interfaces keep their bare name.
+func methodName(fn *types.Func) string {
+	recv := fn.Origin().Type().(*types.Signature).Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	if named, ok := recv.(*types.Named); ok {
+		return named.Origin().Obj().Name() + "." + fn.Name()
 	}
+	return fn.Name()
+}
 
-	key := a.getSymbolKey(pkgPath, obj.Name(), kind)
+// referencesTo returns the references to a symbol
+func (a *Analyzer) referencesTo(symbolKey string) []Reference {
+	id, ok := a.graph.ids[symbolKey]
+	if !ok {
+		return nil
+	}
 
-	a.references[key] = append(a.references[key], Reference{
-		File:     pos.Filename,
-		Position: pos,
-	})
+	refs := make([]Reference, 0, len(a.references[id]))
+	for _, pos := range a.references[id] {
+		position := a.fileSet.Position(pos)
+		refs = append(refs, Reference{
+			File:     position.Filename,
+			Position: position,
+			Pos:      pos,
+		})
+	}
+	return refs
 }
 
 // getObjectKind determines the kind of a types.Object
 func (a *Analyzer) getObjectKind(obj types.Object) string {
-	switch obj.(type) {
+	switch o := obj.(type) {
 	case *types.Func:
+		if sig, ok := o.Type().(*types.Signature); ok && sig.Recv() != nil {
+			return "method"
+		}
 		return "function"
 	case *types.TypeName:
 		return "type"
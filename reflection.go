@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Reflection modes, selected with --reflection
+const (
+	ReflectionNone         = "none"         // reflection keeps nothing alive (default)
+	ReflectionConservative = "conservative" // assume reflect calls the exported methods it can reach
+)
+
+// findReflectedTypes records the project types a file passes to the reflect package, such
+// as reflect.ValueOf(v) or reflect.TypeOf((*T)(nil)), whose exported methods
+// reflect.Value.MethodByName and friends may call
+func (a *Analyzer) findReflectedTypes(pkg *packages.Package, file *ast.File) {
+	info := pkg.TypesInfo
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := typeutil.Callee(info, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "reflect" {
+			return true
+		}
+		for _, arg := range call.Args {
+			t := info.TypeOf(arg)
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			named, ok := t.(*types.Named)
+			if !ok || named.Obj().Pkg() == nil || !a.projectPkgs[named.Obj().Pkg().Path()] {
+				continue
+			}
+			named = named.Origin()
+			a.reflectedTypes[a.getSymbolKey(a.keyPath(named.Obj().Pkg()), named.Obj().Name(), "type")] = named
+		}
+		return true
+	})
+}
+
+// reflectionRoots returns the keys of the exported methods of the types passed to reflect,
+// including those promoted from embedded fields
+func (a *Analyzer) reflectionRoots() []string {
+	keys := make([]string, 0, len(a.reflectedTypes))
+	for key := range a.reflectedTypes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var roots []string
+	for _, key := range keys {
+		methods := types.NewMethodSet(types.NewPointer(a.reflectedTypes[key]))
+		for i := 0; i < methods.Len(); i++ {
+			fn, ok := methods.At(i).Obj().(*types.Func)
+			if !ok || !fn.Exported() || isInterfaceMethod(fn) {
+				continue
+			}
+			if id, ok := a.objectID(fn); ok {
+				if _, exists := a.symbols[a.graph.keys[id]]; exists {
+					roots = append(roots, a.graph.keys[id])
+				}
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(a.reflectedTypes) > 0 {
+		fmt.Printf("🪞 %d exported method(s) of %d type(s) passed to reflect kept alive\n", len(roots), len(a.reflectedTypes))
+	}
+
+	return roots
+}
+
+// usesReflection reports whether a project package imports reflect, so that its exported
+// methods may be called through reflection even when no type is visibly passed to it
+func (a *Analyzer) usesReflection(pkgPath string) bool {
+	if a.reflectPkgs == nil {
+		a.reflectPkgs = make(map[string]bool)
+		for _, pkg := range a.packages {
+			if _, ok := pkg.Imports["reflect"]; ok {
+				a.reflectPkgs[pkg.PkgPath] = true
+			}
+		}
+	}
+	return a.reflectPkgs[pkgPath]
+}
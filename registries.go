@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// namePlaceholder stands for the registered name in a string registry's names template
+const namePlaceholder = "{}"
+
+// StringRegistry is an API that looks symbols up by a string name, such as a job
+// dispatcher or a handler table filled from a config file. A call passing a constant name
+// references the symbols the name resolves to, so they are reachable whenever the calling
+// code is. Registries are declared in the config file.
+type StringRegistry struct {
+	Func     string   `mapstructure:"func"`     // qualified function or method taking the name, e.g. pkg.Registry.Call
+	Arg      int      `mapstructure:"arg"`      // index of the name argument of Func
+	Packages string   `mapstructure:"packages"` // package pattern of the named symbols; default: any project package
+	Names    string   `mapstructure:"names"`    // symbol name built from the registered name, e.g. "Handle{}"; default "{}"
+	Kinds    []string `mapstructure:"kinds"`    // optional symbol kinds, e.g. [function]
+	Reason   string   `mapstructure:"reason"`
+}
+
+// validate checks that a registry is well-formed
+func (r StringRegistry) validate() error {
+	if r.Func == "" {
+		return fmt.Errorf("string registry needs a func")
+	}
+	if r.Arg < 0 {
+		return fmt.Errorf("invalid arg %d for %s", r.Arg, r.Func)
+	}
+	if r.Names != "" && strings.Count(r.Names, namePlaceholder) != 1 {
+		return fmt.Errorf("invalid names template %q (expected exactly one %s)", r.Names, namePlaceholder)
+	}
+	if _, err := path.Match(strings.TrimSuffix(r.Packages, "/..."), ""); err != nil {
+		return fmt.Errorf("invalid packages pattern %q: %w", r.Packages, err)
+	}
+	return nil
+}
+
+// symbolName returns the name of the symbols a registered name stands for: a function,
+// type or variable name, or Type.Method for a method
+func (r StringRegistry) symbolName(name string) string {
+	if r.Names == "" {
+		return name
+	}
+	return strings.Replace(r.Names, namePlaceholder, name, 1)
+}
+
+// matches reports whether a symbol is one the registry may look up
+func (r StringRegistry) matches(symbol *Symbol) bool {
+	if r.Packages != "" && !matchPackagePattern(r.Packages, symbol.Package) {
+		return false
+	}
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range r.Kinds {
+		if kind == symbol.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// stringRegistrations returns the references a call to a string registry makes: the
+// symbols named by its constant name argument. Names computed at run time cannot be
+// resolved and are left alone.
+func (a *Analyzer) stringRegistrations(pkg *packages.Package, call *ast.CallExpr) []fileUse {
+	if len(a.config.StringRegistries) == 0 {
+		return nil
+	}
+	callee := typeutil.StaticCallee(pkg.TypesInfo, call)
+	if callee == nil {
+		return nil
+	}
+
+	var uses []fileUse
+	name := qualifiedFuncName(callee)
+	for _, registry := range a.config.StringRegistries {
+		if registry.Func != name || registry.Arg >= len(call.Args) {
+			continue
+		}
+		arg := call.Args[registry.Arg]
+		value := pkg.TypesInfo.Types[arg].Value
+		if value == nil || value.Kind() != constant.String {
+			continue
+		}
+		for _, key := range a.symbolsNamed(registry.symbolName(constant.StringVal(value))) {
+			if !registry.matches(a.symbols[key]) {
+				continue
+			}
+			id := a.graph.ids[key]
+			a.references[id] = append(a.references[id], arg.Pos())
+			uses = append(uses, fileUse{To: id, Pos: arg.Pos()})
+		}
+	}
+	a.namedReferences += len(uses)
+	return uses
+}
+
+// symbolsNamed returns the keys of the project symbols with a name, Type.Method for
+// methods, indexing them on first use
+func (a *Analyzer) symbolsNamed(name string) []string {
+	if a.symbolNames == nil {
+		a.symbolNames = make(map[string][]string)
+		for _, key := range sortedSymbolKeys(a.symbols) {
+			symbol := a.symbols[key]
+			a.symbolNames[symbol.keyName()] = append(a.symbolNames[symbol.keyName()], key)
+		}
+	}
+	return a.symbolNames[name]
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// resultUsage tracks how the callers of one project function use its results
+type resultUsage struct {
+	signature *types.Signature
+	calls     int
+	used      []bool // by result index: used by at least one call site
+	asValue   bool   // referenced other than by a call, so its signature is not its own to change
+}
+
+// findResults records, for every call of a project function with results, which results
+// the caller uses, with --results. A call used as a statement, deferred or started as a
+// goroutine ignores all of them; an assignment ignores those assigned to _. Calls are
+// counted module-wide, dead code included.
+func (a *Analyzer) findResults() {
+	a.results = make(map[int32]*resultUsage)
+	walked := make(map[string]bool)
+	for _, pkg := range a.packages {
+		for _, file := range pkg.Syntax {
+			filename := a.fileSet.Position(file.Package).Filename
+			if walked[filename] {
+				continue
+			}
+			walked[filename] = true
+			a.countResultUses(pkg, file)
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("📤 Tracking the results of %d function(s)\n", len(a.results))
+	}
+}
+
+// resultsOf returns the usage of the results of a project function, or nil for objects
+// that are not project functions with results
+func (a *Analyzer) resultsOf(obj types.Object) *resultUsage {
+	fn, ok := obj.(*types.Func)
+	if !ok || isInterfaceMethod(fn) {
+		return nil
+	}
+	id, ok := a.objectIDs[fn.Origin()]
+	if !ok {
+		return nil
+	}
+	if usage, ok := a.results[id]; ok {
+		return usage
+	}
+	sig := fn.Origin().Type().(*types.Signature)
+	if sig.Results().Len() == 0 {
+		return nil
+	}
+	usage := &resultUsage{signature: sig, used: make([]bool, sig.Results().Len())}
+	a.results[id] = usage
+	return usage
+}
+
+// calledFunc returns the function a call expression statically calls, with the identifier
+// naming it, or nil
+func calledFunc(info *types.Info, call *ast.CallExpr) (*ast.Ident, types.Object) {
+	fun := ast.Unparen(call.Fun)
+	switch expr := fun.(type) {
+	case *ast.IndexExpr: // f[T]()
+		fun = expr.X
+	case *ast.IndexListExpr: // f[K, V]()
+		fun = expr.X
+	}
+
+	var ident *ast.Ident
+	switch expr := fun.(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		if selection, ok := info.Selections[expr]; ok && selection.Kind() != types.MethodVal {
+			return nil, nil
+		}
+		ident = expr.Sel
+	default:
+		return nil, nil
+	}
+	return ident, info.Uses[ident]
+}
+
+// countResultUses records the result uses of the calls of a file
+func (a *Analyzer) countResultUses(pkg *packages.Package, file *ast.File) {
+	// ignored[call] lists, by result index, the results a call discards
+	ignored := make(map[*ast.CallExpr][]bool)
+	ignoreAll := func(expr ast.Expr) {
+		if call, ok := ast.Unparen(expr).(*ast.CallExpr); ok {
+			ignored[call] = nil
+		}
+	}
+	ignoreBlank := func(lhs []ast.Expr, rhs ast.Expr) {
+		call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		blanks := make([]bool, len(lhs))
+		for i, expr := range lhs {
+			ident, ok := expr.(*ast.Ident)
+			blanks[i] = ok && ident.Name == "_"
+		}
+		ignored[call] = blanks
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ExprStmt:
+			ignoreAll(node.X)
+		case *ast.GoStmt:
+			ignoreAll(node.Call)
+		case *ast.DeferStmt:
+			ignoreAll(node.Call)
+		case *ast.AssignStmt:
+			if len(node.Rhs) == 1 {
+				ignoreBlank(node.Lhs, node.Rhs[0])
+			}
+		case *ast.ValueSpec:
+			if len(node.Values) == 1 {
+				lhs := make([]ast.Expr, len(node.Names))
+				for i, name := range node.Names {
+					lhs[i] = name
+				}
+				ignoreBlank(lhs, node.Values[0])
+			}
+		}
+		return true
+	})
+
+	callees := make(map[*ast.Ident]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			ident, obj := calledFunc(pkg.TypesInfo, node)
+			usage := a.resultsOf(obj)
+			if usage == nil {
+				return true
+			}
+			callees[ident] = true
+			usage.calls++
+			blanks, discarded := ignored[node]
+			for i := range usage.used {
+				if !discarded || blanks != nil && i < len(blanks) && !blanks[i] {
+					usage.used[i] = true
+				}
+			}
+		case *ast.Ident:
+			// Functions passed around as values must keep their signature
+			if usage := a.resultsOf(pkg.TypesInfo.Uses[node]); usage != nil && !callees[node] {
+				usage.asValue = true
+			}
+		}
+		return true
+	})
+}
+
+// unusedResults returns the reachable functions and methods with a result that every call
+// site ignores. Functions used as values, methods that may implement an interface and the
+// exported API under module semantics are left out: their signature is not theirs to
+// simplify.
+func (a *Analyzer) unusedResults() []*Symbol {
+	interfaceMethods := a.interfaceMethodNames()
+	wellKnown := a.wellKnownMethodNames()
+
+	var unused []*Symbol
+	for id, usage := range a.results {
+		key := a.graph.keys[id]
+		symbol, ok := a.symbols[key]
+		if !ok || usage.calls == 0 || usage.asValue || !a.inShard(symbol.Package) || !a.isReachable(key) {
+			continue
+		}
+		if symbol.Kind == "method" && (interfaceMethods[symbol.Name] || wellKnown[symbol.Name]) {
+			continue
+		}
+		if a.semantics == SemanticsModule && symbol.Exported && !isInternalPackage(symbol.Package) {
+			continue
+		}
+
+		var ignored []string
+		results := usage.signature.Results()
+		for i, used := range usage.used {
+			if !used {
+				ignored = append(ignored, describeResult(i, results.At(i)))
+			}
+		}
+		if len(ignored) == 0 {
+			continue
+		}
+
+		reported := *symbol
+		reported.IgnoredResults = ignored
+		reported.CallSites = usage.calls
+		unused = append(unused, &reported)
+	}
+	sort.Slice(unused, func(i, j int) bool { return a.symbolKey(unused[i]) < a.symbolKey(unused[j]) })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(unused) > 0 {
+		fmt.Printf("📤 %d function(s) return results no caller uses\n", len(unused))
+	}
+
+	return unused
+}
+
+// describeResult names a result by position, name and type, e.g. "#2 error" or "#1 n int",
+// qualifying types from other packages
+func describeResult(index int, result *types.Var) string {
+	typ := types.TypeString(result.Type(), types.RelativeTo(result.Pkg()))
+	if result.Name() != "" && result.Name() != "_" {
+		return fmt.Sprintf("#%d %s %s", index+1, result.Name(), typ)
+	}
+	return fmt.Sprintf("#%d %s", index+1, typ)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxReviewLines caps the source shown for one finding during an interactive review
+const maxReviewLines = 40
+
+// reviewFixes steps through the candidate deletions, showing the source of each, and
+// returns the findings the user chose to delete and to suppress. Quitting keeps the
+// choices made so far; input ending early abandons the review.
+func (a *Analyzer) reviewFixes(candidates []*Symbol, in io.Reader) ([]*Symbol, []*Symbol, error) {
+	var deleted, suppressed []*Symbol
+	reader := bufio.NewReader(in)
+
+	for i, symbol := range candidates {
+		fmt.Printf("\n[%d/%d] %s.%s (%s, %s) - %s\n", i+1, len(candidates), symbol.Package, symbol.displayName(),
+			symbol.Kind, symbol.Deadness, formatPosition(a.relativePath(symbol.File), symbol.Start))
+		a.printDeclaration(symbol)
+
+	prompt:
+		for {
+			fmt.Print("Delete, skip, suppress as wontfix or quit? [d/s/p/q]: ")
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read choice, nothing was changed: %w", err)
+			}
+
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "d", "delete":
+				deleted = append(deleted, symbol)
+			case "s", "skip", "":
+			case "p", "suppress":
+				suppressed = append(suppressed, symbol)
+			case "q", "quit":
+				return deleted, suppressed, nil
+			default:
+				fmt.Println("Please answer d, s, p or q")
+				continue
+			}
+			break prompt
+		}
+	}
+
+	return deleted, suppressed, nil
+}
+
+// printDeclaration shows the source a deletion would remove, with line numbers
+func (a *Analyzer) printDeclaration(symbol *Symbol) {
+	declRange, ok := a.declarationRange(symbol)
+	content, err := os.ReadFile(symbol.File)
+	if !ok || err != nil || declRange.End > len(content) {
+		fmt.Println("  (source unavailable)")
+		return
+	}
+
+	line := 1 + bytes.Count(content[:declRange.Start], []byte("\n"))
+	lines := strings.Split(strings.TrimRight(string(content[declRange.Start:declRange.End]), "\n"), "\n")
+	for i, text := range lines {
+		if i == maxReviewLines {
+			fmt.Printf("       │ … %d more line(s)\n", len(lines)-i)
+			break
+		}
+		fmt.Printf("  %4d │ %s\n", line+i, text)
+	}
+}
+
+// suppressFindings records findings as wontfix in the baseline, so later runs and fixes
+// leave them alone
+func (a *Analyzer) suppressFindings(symbols []*Symbol) error {
+	baseline, err := LoadBaseline(resolveBaselinePath(a.config.ProjectPath, a.config.BaselineFile))
+	if err != nil {
+		return err
+	}
+
+	author := currentAuthor(a.config.ProjectPath)
+	for _, symbol := range symbols {
+		baseline.Set(symbol, StateWontfix, "suppressed during gorphanage fix --interactive", author)
+		symbol.State = StateWontfix
+	}
+	return baseline.Save()
+}
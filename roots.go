@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RootRule declares a naming convention whose matching symbols are invoked indirectly
+// (DI containers, reflection, code generators) and must be treated as roots
+type RootRule struct {
+	Packages string   `mapstructure:"packages"` // package pattern, "..." matches any suffix
+	Names    string   `mapstructure:"names"`    // glob on the symbol name, e.g. "New*"
+	Kinds    []string `mapstructure:"kinds"`    // optional symbol kinds, e.g. [function]
+	Reason   string   `mapstructure:"reason"`
+}
+
+// Matches reports whether a symbol falls under the rule
+func (r RootRule) Matches(symbol *Symbol) bool {
+	if r.Packages != "" && !matchPackagePattern(r.Packages, symbol.Package) {
+		return false
+	}
+
+	if r.Names != "" {
+		if matched, _ := path.Match(r.Names, symbol.Name); !matched {
+			return false
+		}
+	}
+
+	if len(r.Kinds) > 0 {
+		for _, kind := range r.Kinds {
+			if kind == symbol.Kind {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// validate checks that a rule is well-formed
+func (r RootRule) validate() error {
+	if r.Packages == "" && r.Names == "" {
+		return fmt.Errorf("root rule needs at least one of packages or names")
+	}
+	if _, err := path.Match(r.Names, ""); err != nil {
+		return fmt.Errorf("invalid names pattern %q: %w", r.Names, err)
+	}
+	if _, err := path.Match(strings.TrimSuffix(r.Packages, "/..."), ""); err != nil {
+		return fmt.Errorf("invalid packages pattern %q: %w", r.Packages, err)
+	}
+	return nil
+}
+
+// matchPackagePattern matches a package path against a go-style pattern: "pkg/..." matches
+// pkg and everything below it, other patterns are globs
+func matchPackagePattern(pattern, pkgPath string) bool {
+	pattern = filepath.ToSlash(pattern)
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		if matched, _ := path.Match(prefix, pkgPath); matched {
+			return true
+		}
+		// Compare the prefix against the same number of leading path elements
+		depth := strings.Count(prefix, "/") + 1
+		parts := strings.SplitN(pkgPath, "/", depth+1)
+		if len(parts) <= depth {
+			return false
+		}
+		matched, _ := path.Match(prefix, strings.Join(parts[:depth], "/"))
+		return matched
+	}
+
+	matched, _ := path.Match(pattern, pkgPath)
+	return matched
+}
+
+// findRuleRoots returns the keys of all symbols matched by a convention root rule
+func (a *Analyzer) findRuleRoots() []string {
+	var roots []string
+	for key, symbol := range a.symbols {
+		for _, rule := range a.config.RootRules {
+			if rule.Matches(symbol) {
+				roots = append(roots, key)
+				break
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(a.config.RootRules) > 0 {
+		fmt.Printf("📐 %d symbol(s) matched %d convention root rule(s)\n", len(roots), len(a.config.RootRules))
+	}
+
+	return roots
+}
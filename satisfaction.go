@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// isInterfaceMethod reports whether a function is a method of an interface, whose calls
+// dispatch to the implementations
+func isInterfaceMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+	_, ok = sig.Recv().Type().Underlying().(*types.Interface)
+	return ok
+}
+
+// interfaceMethodID returns the graph node standing for calls of an interface method and
+// remembers the method, so that the node can be linked to its implementations. Methods of
+// anonymous interfaces are keyed by name alone and share a node per package.
+func (a *Analyzer) interfaceMethodID(fn *types.Func) int32 {
+	pkgPath := ""
+	if fn.Pkg() != nil {
+		pkgPath = a.keyPath(fn.Pkg())
+	}
+	id := a.symbolID(pkgPath, methodName(fn), "method")
+	for _, known := range a.invokedMethods[id] {
+		if known == fn {
+			return id
+		}
+	}
+	a.invokedMethods[id] = append(a.invokedMethods[id], fn)
+	return id
+}
+
+// satisfactionTargets links every project type satisfying an interface whose methods are
+// called in the project to its implementations of those methods. Satisfaction is checked
+// with types.Implements for the type or a pointer to it, so the interface may be named,
+// anonymous or declared in a package the project does not import directly. A type that is
+// reachable keeps such methods alive, since a value of it may reach the call; the call
+// graph of --precision rta or vta also requires the call itself to be reachable.
+func (a *Analyzer) satisfactionTargets() map[int32][]int32 {
+	if len(a.invokedMethods) == 0 {
+		return nil
+	}
+
+	concrete := a.concreteTypes()
+
+	targets := make(map[int32][]int32)
+	linked := make(map[int32]bool)
+	for _, methods := range a.invokedMethods {
+		for _, method := range methods {
+			iface := method.Type().(*types.Signature).Recv().Type().Underlying().(*types.Interface)
+			for _, typeName := range concrete {
+				t := typeName.Type()
+				if !types.Implements(t, iface) && !types.Implements(types.NewPointer(t), iface) {
+					continue
+				}
+				pkgPath := a.keyPath(typeName.Pkg())
+				target, ok := a.graph.ids[a.getSymbolKey(pkgPath, typeName.Name()+"."+method.Name(), "method")]
+				if !ok {
+					continue // promoted from an embedded field
+				}
+				typeID := a.graph.ids[a.getSymbolKey(pkgPath, typeName.Name(), "type")]
+				targets[typeID] = append(targets[typeID], target)
+				linked[target] = true
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && len(linked) > 0 {
+		fmt.Printf("🔌 %d method(s) implement interface methods called in the project\n", len(linked))
+	}
+
+	return targets
+}
+
+// concreteTypes returns the project's non-generic, non-interface named types, once per
+// package path even when a test variant declares them again
+func (a *Analyzer) concreteTypes() []*types.TypeName {
+	var concrete []*types.TypeName
+	seen := make(map[string]bool)
+	for _, pkg := range a.packages {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			named, ok := typeName.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+				continue
+			}
+			key := a.getSymbolKey(a.keyPath(pkg.Types), name, "type")
+			if _, exists := a.symbols[key]; exists && !seen[key] {
+				seen[key] = true
+				concrete = append(concrete, typeName)
+			}
+		}
+	}
+	return concrete
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+)
+
+// selfModule is the module path of gorphanage's own source tree
+const selfModule = "github.com/mirrir0/gorphanage"
+
+// SelfCheckFile holds the findings expected when gorphanage analyzes its own source tree,
+// committed in the repository root
+const SelfCheckFile = "selfcheck.json"
+
+var (
+	selfCheckJSON   bool
+	selfCheckUpdate bool
+)
+
+// SelfCheckExpectations are the findings of the self-analysis, by fingerprint
+type SelfCheckExpectations struct {
+	Version                  int      `json:"version"`
+	Orphans                  []string `json:"orphans"`
+	UnreadFields             []string `json:"unread_fields"`
+	UnusedResults            []string `json:"unused_results"`
+	WriteOnlyVariables       []string `json:"write_only_variables"`
+	UnusedConstraintPackages []string `json:"unused_constraint_packages"`
+}
+
+// SelfCheckReport compares the self-analysis with the committed expectations
+type SelfCheckReport struct {
+	Source     string   `json:"source"`
+	Expected   string   `json:"expected"`
+	Symbols    int      `json:"symbols"`
+	Findings   int      `json:"findings"`
+	Unexpected []string `json:"unexpected"` // found but not expected
+	Missing    []string `json:"missing"`    // expected but no longer found
+}
+
+// Drifted reports whether the analysis no longer matches the expectations
+func (r *SelfCheckReport) Drifted() bool {
+	return len(r.Unexpected) > 0 || len(r.Missing) > 0
+}
+
+// selfCheckExpectations collects the findings of an analysis in a stable order
+func selfCheckExpectations(result *AnalysisResult) *SelfCheckExpectations {
+	expectations := &SelfCheckExpectations{
+		Version:                  1,
+		Orphans:                  []string{},
+		UnreadFields:             []string{},
+		UnusedResults:            []string{},
+		WriteOnlyVariables:       []string{},
+		UnusedConstraintPackages: append([]string{}, result.UnusedConstraintPackages...),
+	}
+	for _, orphan := range result.OrphanedSymbols {
+		expectations.Orphans = append(expectations.Orphans, fingerprint(orphan))
+	}
+	for _, field := range result.UnreadFields {
+		expectations.UnreadFields = append(expectations.UnreadFields, fingerprint(field))
+	}
+	for _, fn := range result.UnusedResults {
+		for _, ignored := range fn.IgnoredResults {
+			expectations.UnusedResults = append(expectations.UnusedResults, fingerprint(fn)+" "+ignored)
+		}
+	}
+	for _, variable := range result.WriteOnlyVariables {
+		expectations.WriteOnlyVariables = append(expectations.WriteOnlyVariables, fingerprint(variable))
+	}
+	sort.Strings(expectations.Orphans)
+	sort.Strings(expectations.UnreadFields)
+	sort.Strings(expectations.UnusedResults)
+	sort.Strings(expectations.WriteOnlyVariables)
+	sort.Strings(expectations.UnusedConstraintPackages)
+	return expectations
+}
+
+// findings lists every expected finding, prefixed by its section
+func (e *SelfCheckExpectations) findings() map[string]bool {
+	findings := make(map[string]bool)
+	for _, fp := range e.Orphans {
+		findings["orphan "+fp] = true
+	}
+	for _, fp := range e.UnreadFields {
+		findings["unread field "+fp] = true
+	}
+	for _, fp := range e.UnusedResults {
+		findings["unused result "+fp] = true
+	}
+	for _, fp := range e.WriteOnlyVariables {
+		findings["write-only variable "+fp] = true
+	}
+	for _, pkg := range e.UnusedConstraintPackages {
+		findings["unused constraint package "+pkg] = true
+	}
+	return findings
+}
+
+// loadSelfCheckExpectations reads the committed expectations
+func loadSelfCheckExpectations(path string) (*SelfCheckExpectations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected findings (run with --update to record them): %w", err)
+	}
+	var expectations SelfCheckExpectations
+	if err := json.Unmarshal(data, &expectations); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &expectations, nil
+}
+
+// save writes the expectations in a stable, diff-friendly format
+func (e *SelfCheckExpectations) save(path string) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal expected findings: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write expected findings: %w", err)
+	}
+	return nil
+}
+
+// checkSelfSource makes sure a directory is gorphanage's own source tree
+func checkSelfSource(sourcePath string) error {
+	gomod := filepath.Join(sourcePath, "go.mod")
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return fmt.Errorf("%s is not a gorphanage source tree: %w", sourcePath, err)
+	}
+	if modulePath := modfile.ModulePath(data); modulePath != selfModule {
+		return fmt.Errorf("%s is the source tree of %s, not of %s", sourcePath, modulePath, selfModule)
+	}
+	return nil
+}
+
+// SelfCheck analyzes gorphanage's own source tree and compares the findings with the
+// committed expectations, or records them with update. The analysis ignores config files,
+// profiles and environment variables, so that its outcome depends only on the analyzer
+// and the source tree.
+func SelfCheck(sourcePath string, update bool) (*SelfCheckReport, error) {
+	if err := checkSelfSource(sourcePath); err != nil {
+		return nil, err
+	}
+
+	result, err := New(WithProjectPath(sourcePath), WithFields(), WithResults()).Analyze()
+	if err != nil {
+		return nil, fmt.Errorf("self-analysis failed: %w", err)
+	}
+	actual := selfCheckExpectations(result)
+
+	report := &SelfCheckReport{
+		Source:     sourcePath,
+		Expected:   filepath.Join(sourcePath, SelfCheckFile),
+		Symbols:    result.TotalSymbols,
+		Findings:   len(actual.findings()),
+		Unexpected: []string{},
+		Missing:    []string{},
+	}
+	if update {
+		return report, actual.save(report.Expected)
+	}
+
+	expected, err := loadSelfCheckExpectations(report.Expected)
+	if err != nil {
+		return nil, err
+	}
+	want, got := expected.findings(), actual.findings()
+	for finding := range got {
+		if !want[finding] {
+			report.Unexpected = append(report.Unexpected, finding)
+		}
+	}
+	for finding := range want {
+		if !got[finding] {
+			report.Missing = append(report.Missing, finding)
+		}
+	}
+	sort.Strings(report.Unexpected)
+	sort.Strings(report.Missing)
+	return report, nil
+}
+
+// printSelfCheck outputs a self-check report in human-readable format
+func printSelfCheck(report *SelfCheckReport, update bool) {
+	if update {
+		fmt.Printf("📝 Recorded %d expected finding(s) of %d symbol(s) in %s\n", report.Findings, report.Symbols, report.Expected)
+		return
+	}
+	if !report.Drifted() {
+		fmt.Printf("✅ Self-analysis matches %s: %d finding(s) of %d symbol(s)\n", report.Expected, report.Findings, report.Symbols)
+		return
+	}
+
+	fmt.Printf("❌ Self-analysis drifted from %s\n", report.Expected)
+	if len(report.Unexpected) > 0 {
+		fmt.Printf("\n➕ New findings (%d):\n", len(report.Unexpected))
+		for _, finding := range report.Unexpected {
+			fmt.Printf("  • %s\n", finding)
+		}
+	}
+	if len(report.Missing) > 0 {
+		fmt.Printf("\n➖ Findings no longer reported (%d):\n", len(report.Missing))
+		for _, finding := range report.Missing {
+			fmt.Printf("  • %s\n", finding)
+		}
+	}
+	fmt.Printf("\n💡 If the change is intended, record it with: gorphanage self-check --update\n")
+}
+
+var selfCheckCmd = &cobra.Command{
+	Use:   "self-check [source-path]",
+	Short: "Analyze gorphanage's own source and compare with the expected findings",
+	Long: `Runs the analyzer on gorphanage's own source tree, the current directory by default,
+with a fixed configuration, and compares its orphans, unread struct fields, unused
+results, write-only variables and unused constraint packages with those committed in
+selfcheck.json. Any difference means the
+analysis semantics changed: the command fails and lists the findings that appeared or
+disappeared. Intended changes are recorded with --update and reviewed like code.`,
+	Example: `  gorphanage self-check
+  gorphanage self-check --update ~/src/gorphanage`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		sourcePath := "."
+		if len(args) > 0 {
+			sourcePath = args[0]
+		}
+		absPath, err := filepath.Abs(sourcePath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve source path: %w", err)
+		}
+
+		report, err := SelfCheck(normalizePath(absPath), selfCheckUpdate)
+		if err != nil {
+			return err
+		}
+		if selfCheckJSON {
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			printSelfCheck(report, selfCheckUpdate)
+		}
+
+		if report.Drifted() {
+			return fmt.Errorf("self-analysis drifted: %d new and %d missing finding(s)", len(report.Unexpected), len(report.Missing))
+		}
+		return nil
+	},
+}
+
+func init() {
+	selfCheckCmd.Flags().BoolVar(&selfCheckJSON, "json", false, "output the comparison in JSON format")
+	selfCheckCmd.Flags().BoolVar(&selfCheckUpdate, "update", false, "record the current findings as the expected ones")
+	rootCmd.AddCommand(selfCheckCmd)
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+// GraphNode is a symbol of the graph view
+type GraphNode struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Package  string `json:"package"`
+	Verdict  string `json:"verdict"` // reachable, kept-by-assertion, orphaned, test or docs-only
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	External bool   `json:"external,omitempty"` // in another package, referencing or referenced by the viewed one
+}
+
+// GraphLink is a reference between two symbols of the graph view
+type GraphLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// GraphView is the symbol graph of one package, with the symbols of other packages it
+// references or is referenced by
+type GraphView struct {
+	Package string       `json:"package"`
+	Nodes   []*GraphNode `json:"nodes"`
+	Links   []*GraphLink `json:"links"`
+}
+
+// PackageView summarizes a package in the graph server's package list
+type PackageView struct {
+	Package string `json:"package"`
+	Symbols int    `json:"symbols"`
+	Orphans int    `json:"orphans"`
+}
+
+// graphServer serves the symbol graph of a project over HTTP. The analysis is kept until
+// a project file changes, and the packages are kept loaded like a daemon does.
+type graphServer struct {
+	config   *Config
+	cache    packageCache
+	analyzer *Analyzer
+	stamp    string
+	mu       sync.Mutex // one request at a time; the analyzer builds indexes on demand
+}
+
+// current returns the analysis of the project as it is on disk, analyzing it again when
+// its files changed
+func (s *graphServer) current() (*Analyzer, error) {
+	stamp, err := projectStamp(s.config.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	if s.analyzer != nil && stamp == s.stamp {
+		return s.analyzer, nil
+	}
+
+	analyzer := New(WithConfig(s.config))
+	analyzer.config.Verbose = false
+	analyzer.config.OutputJSON = true
+	analyzer.cache = &s.cache
+	if _, err := analyzer.Analyze(); err != nil {
+		return nil, err
+	}
+	s.analyzer, s.stamp = analyzer, stamp
+	return analyzer, nil
+}
+
+// graphNode describes a symbol as a node of the graph view
+func (a *Analyzer) graphNode(key string, external bool) *GraphNode {
+	symbol := a.symbols[key]
+	return &GraphNode{
+		Key:      key,
+		Name:     symbol.displayName(),
+		Kind:     symbol.Kind,
+		Package:  symbol.Package,
+		Verdict:  a.symbolVerdict(key),
+		File:     a.relativePath(symbol.File),
+		Line:     symbol.Start.Line,
+		External: external,
+	}
+}
+
+// packageGraph builds the graph view of a package
+func (a *Analyzer) packageGraph(pkgPath string) *GraphView {
+	view := &GraphView{Package: pkgPath, Nodes: []*GraphNode{}, Links: []*GraphLink{}}
+	nodes := make(map[string]bool)
+	links := make(map[GraphLink]bool)
+	addNode := func(key string) {
+		if !nodes[key] {
+			nodes[key] = true
+			view.Nodes = append(view.Nodes, a.graphNode(key, a.symbols[key].Package != pkgPath))
+		}
+	}
+	addLink := func(from, to string) {
+		link := GraphLink{Source: from, Target: to}
+		if !links[link] {
+			links[link] = true
+			view.Links = append(view.Links, &link)
+		}
+	}
+
+	for _, key := range sortedSymbolKeys(a.symbols) {
+		if a.symbols[key].Package != pkgPath {
+			continue
+		}
+		addNode(key)
+		for _, target := range a.findReferencedSymbols(key) {
+			if _, ok := a.symbols[target]; ok {
+				addNode(target)
+				addLink(key, target)
+			}
+		}
+		for _, from := range a.referrers(a.graph.ids[key]) {
+			if source := a.graph.keys[from]; a.symbols[source].Package != pkgPath {
+				addNode(source)
+				addLink(source, key)
+			}
+		}
+	}
+	return view
+}
+
+// packageViews lists the project packages with their symbol and orphan counts
+func (a *Analyzer) packageViews() []*PackageView {
+	byPackage := make(map[string]*PackageView)
+	for key, symbol := range a.symbols {
+		view, ok := byPackage[symbol.Package]
+		if !ok {
+			view = &PackageView{Package: symbol.Package}
+			byPackage[symbol.Package] = view
+		}
+		view.Symbols++
+		if a.symbolVerdict(key) == "orphaned" {
+			view.Orphans++
+		}
+	}
+
+	views := make([]*PackageView, 0, len(byPackage))
+	for _, view := range byPackage {
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Package < views[j].Package })
+	return views
+}
+
+// handler routes the graph server's page and API
+func (s *graphServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, graphPage)
+	})
+	mux.HandleFunc("GET /api/packages", s.serveAPI(func(a *Analyzer, r *http.Request) (any, int) {
+		return a.packageViews(), http.StatusOK
+	}))
+	mux.HandleFunc("GET /api/graph", s.serveAPI(func(a *Analyzer, r *http.Request) (any, int) {
+		pkgPath := r.URL.Query().Get("package")
+		if !a.projectPkgs[pkgPath] {
+			return fmt.Sprintf("unknown package %q", pkgPath), http.StatusNotFound
+		}
+		return a.packageGraph(pkgPath), http.StatusOK
+	}))
+	mux.HandleFunc("GET /api/explain", s.serveAPI(func(a *Analyzer, r *http.Request) (any, int) {
+		key := r.URL.Query().Get("symbol")
+		if _, ok := a.symbols[key]; !ok {
+			return fmt.Sprintf("unknown symbol %q", key), http.StatusNotFound
+		}
+		return a.explainSymbol(key), http.StatusOK
+	}))
+	return mux
+}
+
+// serveAPI answers an API request with the JSON of what answer returns for the current
+// analysis; a string is sent as the error of a failed request
+func (s *graphServer) serveAPI(answer func(*Analyzer, *http.Request) (any, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		analyzer, err := s.current()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		body, status := answer(analyzer, r)
+		if message, ok := body.(string); ok {
+			body = map[string]string{"error": message}
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [project-path]",
+	Short: "Browse the symbol graph of a project in an interactive page",
+	Long: `Serves an interactive, force-directed graph of a package's symbols, colored by
+verdict: reachable, orphaned, test or docs-only, with the symbols of other packages
+referencing or referenced by it. Clicking a symbol explains it: the chain keeping it
+alive from its root, or the symbols still referencing an orphan. The project is analyzed
+on the first request and again whenever its files change; the packages stay loaded in
+between. The page loads d3 from its CDN. The analysis follows the config file, like
+explain and refs do.`,
+	Example: `  gorphanage serve .
+  gorphanage serve --addr localhost:8080 ./myproject`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+		config, err := configFromViper(projectPath)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		server := &graphServer{config: config}
+		fmt.Printf("🕸️  Serving the symbol graph of %s on http://%s\n", config.ProjectPath, serveAddr)
+		if err := http.ListenAndServe(serveAddr, server.handler()); err != nil {
+			return fmt.Errorf("graph server failed: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7070", "address to listen on")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// graphPage is the interactive graph view, rendered with d3's force simulation
+const graphPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gorphanage - symbol graph</title>
+<script src="https://d3js.org/d3.v7.min.js"></script>
+<style>
+  body { margin: 0; font: 13px sans-serif; display: flex; height: 100vh; }
+  #main { flex: 1; display: flex; flex-direction: column; }
+  #toolbar { padding: 8px; border-bottom: 1px solid #ddd; }
+  #toolbar select { max-width: 60%; }
+  .legend span { margin-left: 12px; }
+  .legend i { display: inline-block; width: 10px; height: 10px; border-radius: 5px; margin-right: 4px; }
+  svg { flex: 1; }
+  #panel { width: 340px; border-left: 1px solid #ddd; padding: 8px; overflow: auto; }
+  #panel li { margin: 2px 0; cursor: pointer; }
+  .link { stroke: #bbb; stroke-opacity: .7; }
+  .node text { font-size: 11px; pointer-events: none; }
+</style>
+</head>
+<body>
+<div id="main">
+  <div id="toolbar">
+    <select id="packages"></select>
+    <span class="legend">
+      <span><i style="background:#2e9e44"></i>reachable</span>
+      <span><i style="background:#e07000"></i>kept by assertion</span>
+      <span><i style="background:#d9363e"></i>orphaned</span>
+      <span><i style="background:#e0a400"></i>test / docs-only</span>
+      <span><i style="background:#ccc"></i>other package</span>
+    </span>
+  </div>
+  <svg id="graph"></svg>
+</div>
+<div id="panel"><p>Click a symbol to explain it.</p></div>
+<script>
+const colors = {reachable: "#2e9e44", orphaned: "#d9363e", test: "#e0a400", "docs-only": "#e0a400", "kept-by-assertion": "#e07000"};
+const select = document.getElementById("packages");
+const panel = document.getElementById("panel");
+const svg = d3.select("#graph");
+let simulation;
+
+function text(tag, content) {
+  const element = document.createElement(tag);
+  element.textContent = content;
+  return element;
+}
+
+async function getJSON(url) {
+  const response = await fetch(url);
+  const body = await response.json();
+  if (!response.ok) throw new Error(body.error);
+  return body;
+}
+
+function symbolList(title, keys) {
+  panel.appendChild(text("h4", title));
+  const list = document.createElement("ol");
+  for (const key of keys) {
+    const item = text("li", key);
+    item.onclick = () => explain(key);
+    list.appendChild(item);
+  }
+  panel.appendChild(list);
+}
+
+async function explain(key) {
+  panel.replaceChildren(text("p", "Loading…"));
+  try {
+    const explanation = await getJSON("api/explain?symbol=" + encodeURIComponent(key));
+    const symbol = explanation.symbol;
+    panel.replaceChildren(text("h3", symbol.package + "." + symbol.name + " (" + symbol.kind + ")"));
+    panel.appendChild(text("p", symbol.file + ":" + symbol.start.line));
+    panel.appendChild(text("p", explanation.reachable ? "✅ Reachable" : "🗑️ Orphaned"));
+    if (explanation.path) symbolList("Chain from its root", explanation.path);
+    if (explanation.referenced_by) symbolList("Referenced by", explanation.referenced_by);
+    const pkg = document.createElement("button");
+    pkg.textContent = "Show package " + symbol.package;
+    pkg.onclick = () => { select.value = symbol.package; draw(symbol.package); };
+    panel.appendChild(pkg);
+  } catch (error) {
+    panel.replaceChildren(text("p", "⚠️ " + error.message));
+  }
+}
+
+async function draw(pkg) {
+  if (simulation) simulation.stop();
+  svg.selectAll("*").remove();
+  let view;
+  try {
+    view = await getJSON("api/graph?package=" + encodeURIComponent(pkg));
+  } catch (error) {
+    panel.replaceChildren(text("p", "⚠️ " + error.message));
+    return;
+  }
+  const {width, height} = svg.node().getBoundingClientRect();
+  const layer = svg.append("g");
+  svg.call(d3.zoom().on("zoom", event => layer.attr("transform", event.transform)));
+  svg.append("defs").append("marker").attr("id", "arrow").attr("viewBox", "0 -4 8 8")
+    .attr("refX", 14).attr("markerWidth", 6).attr("markerHeight", 6).attr("orient", "auto")
+    .append("path").attr("d", "M0,-4L8,0L0,4").attr("fill", "#bbb");
+
+  simulation = d3.forceSimulation(view.nodes)
+    .force("link", d3.forceLink(view.links).id(node => node.key).distance(70))
+    .force("charge", d3.forceManyBody().strength(-160))
+    .force("center", d3.forceCenter(width / 2, height / 2));
+
+  const link = layer.append("g").selectAll("line").data(view.links).join("line")
+    .attr("class", "link").attr("marker-end", "url(#arrow)");
+  const node = layer.append("g").selectAll("g").data(view.nodes).join("g").attr("class", "node")
+    .call(d3.drag()
+      .on("start", (event, d) => { if (!event.active) simulation.alphaTarget(0.3).restart(); d.fx = d.x; d.fy = d.y; })
+      .on("drag", (event, d) => { d.fx = event.x; d.fy = event.y; })
+      .on("end", (event, d) => { if (!event.active) simulation.alphaTarget(0); d.fx = null; d.fy = null; }));
+  node.append("circle").attr("r", d => d.external ? 5 : 8)
+    .attr("fill", d => d.external ? "#ccc" : colors[d.verdict])
+    .on("click", (event, d) => explain(d.key));
+  node.append("title").text(d => d.package + "." + d.name + " (" + d.kind + ", " + d.verdict + ")\n" + d.file + ":" + d.line);
+  node.append("text").attr("x", 10).attr("y", 4).text(d => d.external ? d.package.split("/").pop() + "." + d.name : d.name);
+
+  simulation.on("tick", () => {
+    link.attr("x1", d => d.source.x).attr("y1", d => d.source.y).attr("x2", d => d.target.x).attr("y2", d => d.target.y);
+    node.attr("transform", d => "translate(" + d.x + "," + d.y + ")");
+  });
+}
+
+(async () => {
+  try {
+    const packages = await getJSON("api/packages");
+    for (const pkg of packages) {
+      const option = text("option", pkg.package + " (" + pkg.orphans + "/" + pkg.symbols + " orphaned)");
+      option.value = pkg.package;
+      select.appendChild(option);
+    }
+    select.onchange = () => draw(select.value);
+    if (packages.length > 0) draw(packages[0].package);
+  } catch (error) {
+    panel.replaceChildren(text("p", "⚠️ " + error.message));
+  }
+})();
+</script>
+</body>
+</html>
+`
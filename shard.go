@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// parseShard parses a shard specification such as "3/8" into a 1-based index and a count
+func parseShard(spec string) (int, int, error) {
+	indexPart, countPart, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --shard %q (expected index/count, e.g. 3/8)", spec)
+	}
+
+	index, err := strconv.Atoi(indexPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --shard %q: bad index: %w", spec, err)
+	}
+	count, err := strconv.Atoi(countPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid --shard %q: bad count: %w", spec, err)
+	}
+	if count < 1 || index < 1 || index > count {
+		return 0, 0, fmt.Errorf("invalid --shard %q (index must be between 1 and the count)", spec)
+	}
+
+	return index, count, nil
+}
+
+// inShard reports whether a package's findings are reported by this shard. Packages are
+// assigned by a hash of their path, so every shard agrees on the partition without
+// coordination and adding a package never moves the others.
+func (a *Analyzer) inShard(pkgPath string) bool {
+	if a.config.ShardCount <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(pkgPath))
+	return int(h.Sum32()%uint32(a.config.ShardCount)) == a.config.ShardIndex-1
+}
+
+// symbolCounts returns the total and reachable symbol counts of the result. A shard only
+// counts the project symbols of its own packages so that merged shards add up.
+func (a *Analyzer) symbolCounts() (int, int) {
+	if a.config.ShardCount <= 1 {
+		return len(a.symbols), a.reachableCount
+	}
+
+	total, reachable := 0, 0
+	for key, symbol := range a.symbols {
+		if !a.inShard(symbol.Package) {
+			continue
+		}
+		total++
+		if a.isReachable(key) {
+			reachable++
+		}
+	}
+	return total, reachable
+}
+
+// reportShard prints which part of the project this shard reports
+func (a *Analyzer) reportShard() {
+	if a.config.ShardCount <= 1 || !a.config.Verbose || a.config.OutputJSON {
+		return
+	}
+
+	reported := 0
+	for _, pkg := range a.packages {
+		if a.inShard(pkg.PkgPath) {
+			reported++
+		}
+	}
+	fmt.Printf("🧩 Shard %d/%d: reporting %d of %d packages\n",
+		a.config.ShardIndex, a.config.ShardCount, reported, len(a.packages))
+}
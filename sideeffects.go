@@ -0,0 +1,64 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// pureBuiltins are builtin functions whose calls cannot affect program state
+var pureBuiltins = map[string]bool{
+	"append":  true,
+	"cap":     true,
+	"complex": true,
+	"imag":    true,
+	"len":     true,
+	"make":    true,
+	"max":     true,
+	"min":     true,
+	"new":     true,
+	"real":    true,
+}
+
+// hasSideEffects reports whether evaluating a package-level initializer may have observable effects
+// (function calls, channel operations). Function literal bodies are not evaluated and are ignored.
+func (a *Analyzer) hasSideEffects(pkg *packages.Package, expr ast.Expr) bool {
+	effects := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if effects {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				effects = true
+			}
+		case *ast.CallExpr:
+			if !a.isPureCall(pkg, node) {
+				effects = true
+			}
+		}
+		return !effects
+	})
+	return effects
+}
+
+// isPureCall reports whether a call expression is a type conversion or a pure builtin
+func (a *Analyzer) isPureCall(pkg *packages.Package, call *ast.CallExpr) bool {
+	if tv, ok := pkg.TypesInfo.Types[call.Fun]; ok && tv.IsType() {
+		return true
+	}
+
+	fun := ast.Unparen(call.Fun)
+	if ident, ok := fun.(*ast.Ident); ok {
+		if builtin, ok := pkg.TypesInfo.Uses[ident].(*types.Builtin); ok {
+			return pureBuiltins[builtin.Name()]
+		}
+	}
+
+	return false
+}
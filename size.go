@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// Rough per-symbol contributions to the binary. Function code is estimated from the size
+// of its syntax tree; type descriptors and function metadata are flat costs.
+const (
+	functionOverheadBytes = 64 // prologue, stack check and pclntab entry
+	bytesPerSyntaxNode    = 6
+	typeDescriptorBytes   = 96
+)
+
+// SizeCluster is a group of orphans referencing each other, deleted together
+type SizeCluster struct {
+	EstimatedBytes int      `json:"estimated_bytes"`
+	Symbols        []string `json:"symbols"`
+}
+
+// estimateSizes estimates the binary size of every top-level declaration while the syntax
+// trees are still available
+func (a *Analyzer) estimateSizes() {
+	a.sizes = make(map[string]int)
+	fallbackSizes := types.SizesFor("gc", "amd64")
+
+	for _, pkg := range a.packages {
+		sizes := pkg.TypesSizes
+		if sizes == nil {
+			sizes = fallbackSizes
+		}
+
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					name, kind := funcDeclName(d)
+					key := a.getSymbolKey(a.keyPath(pkg.Types), name, kind)
+					a.sizes[key] = functionOverheadBytes + bytesPerSyntaxNode*countNodes(d.Body)
+				case *ast.GenDecl:
+					a.estimateGenDeclSizes(a.keyPath(pkg.Types), pkg.TypesInfo, sizes, d)
+				}
+			}
+		}
+	}
+}
+
+// estimateGenDeclSizes estimates type descriptors and the storage of package variables;
+// constants are folded by the compiler and cost nothing, and so do constraint interfaces,
+// which never become the type of a value
+func (a *Analyzer) estimateGenDeclSizes(pkgPath string, info *types.Info, sizes types.Sizes, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			if obj := info.Defs[s.Name]; obj != nil && isConstraintInterface(obj.Type()) {
+				continue
+			}
+			a.sizes[a.getSymbolKey(pkgPath, s.Name.Name, "type")] = typeDescriptorBytes
+		case *ast.ValueSpec:
+			if decl.Tok != token.VAR {
+				continue
+			}
+			for _, name := range s.Names {
+				obj := info.Defs[name]
+				if obj == nil || name.Name == "_" {
+					continue
+				}
+				size := int(sizes.Sizeof(obj.Type()))
+				// Initializers compile into the package's init function
+				size += bytesPerSyntaxNode * countNodes(s.Type)
+				for _, value := range s.Values {
+					size += bytesPerSyntaxNode * countNodes(value)
+				}
+				a.sizes[a.getSymbolKey(pkgPath, name.Name, "variable")] = size
+			}
+		}
+	}
+}
+
+// countNodes counts the syntax nodes of a tree, 0 for nil
+func countNodes(node ast.Node) int {
+	if node == nil {
+		return 0
+	}
+	count := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n != nil {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// sizeClusters annotates orphans with their estimated size and groups orphans that
+// reference each other into clusters, largest first
+func (a *Analyzer) sizeClusters(orphans []*Symbol) []*SizeCluster {
+	// Union-find over the graph IDs of the orphans
+	parent := make(map[int32]int32, len(orphans))
+	var find func(id int32) int32
+	find = func(id int32) int32 {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+
+	keys := make(map[*Symbol]string, len(orphans))
+	for _, orphan := range orphans {
+		key := a.symbolKey(orphan)
+		keys[orphan] = key
+		orphan.EstimatedBytes = a.sizes[key]
+		id := a.graph.ids[key]
+		parent[id] = id
+	}
+	for id := range parent {
+		for _, target := range a.graph.successors(id) {
+			if _, orphaned := parent[target]; orphaned {
+				parent[find(id)] = find(target)
+			}
+		}
+	}
+
+	clusters := make(map[int32]*SizeCluster)
+	for _, orphan := range orphans {
+		root := find(a.graph.ids[keys[orphan]])
+		cluster, ok := clusters[root]
+		if !ok {
+			cluster = &SizeCluster{}
+			clusters[root] = cluster
+		}
+		cluster.EstimatedBytes += orphan.EstimatedBytes
+		cluster.Symbols = append(cluster.Symbols, keys[orphan])
+	}
+
+	result := make([]*SizeCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		sort.Strings(cluster.Symbols)
+		result = append(result, cluster)
+	}
+	sortSizeClusters(result)
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		total := 0
+		for _, cluster := range result {
+			total += cluster.EstimatedBytes
+		}
+		fmt.Printf("📦 Orphans contribute an estimated %s in %d cluster(s)\n", formatBytes(total), len(result))
+	}
+
+	return result
+}
+
+// sortSizeClusters orders clusters by estimated size, largest first
+func sortSizeClusters(clusters []*SizeCluster) {
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].EstimatedBytes != clusters[j].EstimatedBytes {
+			return clusters[i].EstimatedBytes > clusters[j].EstimatedBytes
+		}
+		return clusters[i].Symbols[0] < clusters[j].Symbols[0]
+	})
+}
+
+// formatBytes formats a byte count in KB
+func formatBytes(n int) string {
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
+}
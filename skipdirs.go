@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// skippedDirectory returns why the packages of a directory are left out of the analysis,
+// or "" when they are analyzed. testdata directories hold fixtures the go command
+// ignores, often deliberately broken or dead; internal/tools holds build tooling such as
+// code generators and tool dependency pins, which the project's binaries never run.
+func (a *Analyzer) skippedDirectory(dir string) string {
+	elements := strings.Split(a.relativePath(dir), "/")
+	for i, element := range elements {
+		switch {
+		case element == "testdata" && !a.config.IncludeTestdata:
+			return "testdata"
+		case element == "tools" && i > 0 && elements[i-1] == "internal" && !a.config.IncludeTools:
+			return "internal/tools"
+		}
+	}
+	return ""
+}
+
+// testdataPatterns returns a pattern for every package directory below a testdata
+// directory of the project module, which ./... never matches. Nested modules are left to
+// their own go.mod.
+func testdataPatterns(projectPath string) ([]string, error) {
+	var patterns []string
+	err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == projectPath {
+			return nil
+		}
+
+		name := d.Name()
+		if name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !slices.Contains(strings.Split(rel, "/"), "testdata") {
+			return nil
+		}
+		if matches, _ := filepath.Glob(filepath.Join(path, "*.go")); len(matches) > 0 {
+			patterns = append(patterns, "./"+rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for testdata packages: %w", err)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// stdlibInterfaces are standard library interfaces whose methods are called by the
+// standard library itself: fmt formats Stringers, encoding/json calls Marshalers, io.Copy
+// reads and writes. Project code implementing them rarely calls the methods by name.
+var stdlibInterfaces = []string{
+	// Formatting
+	"fmt.Stringer", "fmt.GoStringer", "fmt.Formatter", "fmt.Scanner",
+	// Encoding
+	"encoding.TextMarshaler", "encoding.TextUnmarshaler",
+	"encoding.BinaryMarshaler", "encoding.BinaryUnmarshaler",
+	"encoding/json.Marshaler", "encoding/json.Unmarshaler",
+	"encoding/xml.Marshaler", "encoding/xml.Unmarshaler",
+	"encoding/xml.MarshalerAttr", "encoding/xml.UnmarshalerAttr",
+	"encoding/gob.GobEncoder", "encoding/gob.GobDecoder",
+	// I/O
+	"io.Reader", "io.Writer", "io.Closer", "io.Seeker",
+	"io.ReaderAt", "io.WriterAt", "io.ReaderFrom", "io.WriterTo",
+	"io.ByteReader", "io.ByteScanner", "io.ByteWriter",
+	"io.RuneReader", "io.RuneScanner", "io.StringWriter",
+	"io/fs.FS", "io/fs.File", "io/fs.FileInfo", "io/fs.DirEntry",
+	"io/fs.ReadDirFS", "io/fs.ReadFileFS", "io/fs.StatFS", "io/fs.SubFS", "io/fs.GlobFS",
+	// Collections and flags
+	"sort.Interface", "container/heap.Interface", "flag.Value", "flag.Getter",
+	// Networking and HTTP
+	"net.Conn", "net.Listener", "net.Addr", "net.Error",
+	"net/http.Handler", "net/http.RoundTripper", "net/http.ResponseWriter",
+	"net/http.Flusher", "net/http.Hijacker", "net/http.CookieJar",
+	"net/http.FileSystem", "net/http.File",
+	// Databases
+	"database/sql.Scanner", "database/sql/driver.Valuer", "database/sql/driver.Driver",
+	"database/sql/driver.Conn", "database/sql/driver.Stmt", "database/sql/driver.Rows",
+	"database/sql/driver.Result", "database/sql/driver.Tx",
+	// Logging, contexts and the rest
+	"log/slog.Handler", "log/slog.LogValuer", "log/slog.Leveler",
+	"context.Context", "hash.Hash", "hash.Hash32", "hash.Hash64",
+	"crypto.Signer", "crypto.Decrypter", "crypto/cipher.Block", "crypto/cipher.Stream",
+	"image.Image", "image/color.Color", "image/color.Model", "image/draw.Image",
+	"expvar.Var", "os.Signal", "go/ast.Visitor",
+}
+
+// stdlibTargets links every project type implementing a standard library interface to the
+// methods implementing it, and remembers them like well-known methods: the standard library
+// calls them, so they are reachable whenever the type is, with any --precision. Unlike a
+// well-known name, the whole interface must be implemented with matching signatures, so a
+// Close method of a type that is no io.Closer is not kept. Interfaces whose package is not
+// loaded, directly or indirectly, cannot call into the project and are skipped.
+func (a *Analyzer) stdlibTargets() map[int32][]int32 {
+	var interfaces []*types.Interface
+	for _, name := range stdlibInterfaces {
+		if iface := a.lookupInterface(name); iface != nil {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	if len(interfaces) == 0 {
+		return nil
+	}
+
+	targets := make(map[int32][]int32)
+	kept := 0
+	for _, typeName := range a.concreteTypes() {
+		t := typeName.Type()
+		ptr := types.NewPointer(t)
+		pkgPath := a.keyPath(typeName.Pkg())
+		typeID := a.graph.ids[a.getSymbolKey(pkgPath, typeName.Name(), "type")]
+		for _, iface := range interfaces {
+			if !types.Implements(t, iface) && !types.Implements(ptr, iface) {
+				continue
+			}
+			for i := 0; i < iface.NumMethods(); i++ {
+				target, ok := a.graph.ids[a.getSymbolKey(pkgPath, typeName.Name()+"."+iface.Method(i).Name(), "method")]
+				if !ok || a.wellKnown[target] {
+					continue // promoted from an embedded field, or already kept
+				}
+				a.wellKnown[target] = true
+				targets[typeID] = append(targets[typeID], target)
+				kept++
+			}
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && kept > 0 {
+		fmt.Printf("📚 %d method(s) implement standard library interfaces, kept with their types\n", kept)
+	}
+
+	return targets
+}
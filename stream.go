@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// traverseStreaming traces reachability package by package and prints the findings of each
+// package as soon as its verdicts are final. Packages are condensed into strongly connected
+// components of the package-level reference graph and visited in topological order: once
+// every component that can reference a package is done, nothing can reach more of it.
+func (a *Analyzer) traverseStreaming(entryPoints []int32) {
+	g := a.graph
+	n := g.size()
+
+	// Package index of every project symbol, -1 for symbols outside the project
+	pkgIndex := make(map[string]int)
+	var pkgPaths []string
+	nodePkg := make([]int, n)
+	for id := range nodePkg {
+		nodePkg[id] = -1
+		symbol, ok := a.symbols[g.keys[id]]
+		if !ok {
+			continue
+		}
+		index, ok := pkgIndex[symbol.Package]
+		if !ok {
+			index = len(pkgPaths)
+			pkgIndex[symbol.Package] = index
+			pkgPaths = append(pkgPaths, symbol.Package)
+		}
+		nodePkg[id] = index
+	}
+
+	pkgEdges := make([]map[int]bool, len(pkgPaths))
+	pkgNodes := make([][]int32, len(pkgPaths))
+	for id := int32(0); id < int32(n); id++ {
+		from := nodePkg[id]
+		if from < 0 {
+			continue
+		}
+		pkgNodes[from] = append(pkgNodes[from], id)
+		for _, target := range g.successors(id) {
+			if to := nodePkg[target]; to >= 0 && to != from {
+				if pkgEdges[from] == nil {
+					pkgEdges[from] = make(map[int]bool)
+				}
+				pkgEdges[from][to] = true
+			}
+		}
+	}
+
+	components, componentOf := stronglyConnected(len(pkgPaths), pkgEdges)
+
+	// reached[id] is 0 while unreached, 1 for entry points and parent+2 otherwise
+	a.reached = make([]int32, n)
+	for _, id := range entryPoints {
+		a.reached[id] = 1
+	}
+
+	fmt.Printf("\n🗑️  ORPHANED CODE ANALYSIS (streaming)\n\n")
+
+	// Tarjan's algorithm yields components in reverse topological order
+	for c := len(components) - 1; c >= 0; c-- {
+		var queue []int32
+		for _, pkg := range components[c] {
+			for _, id := range pkgNodes[pkg] {
+				if a.reached[id] != 0 {
+					queue = append(queue, id)
+				}
+			}
+		}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, target := range g.successors(current) {
+				if a.reached[target] != 0 {
+					continue
+				}
+				a.reached[target] = current + 2
+				// Targets in later components are expanded when their component comes up
+				if pkg := nodePkg[target]; pkg >= 0 && componentOf[pkg] == c {
+					queue = append(queue, target)
+				}
+			}
+		}
+
+		for _, pkg := range components[c] {
+			a.printFinalPackage(pkgPaths[pkg], pkgNodes[pkg])
+		}
+	}
+
+	for _, from := range a.reached {
+		if from != 0 {
+			a.reachableCount++
+		}
+	}
+}
+
+// printFinalPackage prints the orphans of a package whose verdicts are final
+func (a *Analyzer) printFinalPackage(pkgPath string, nodes []int32) {
+	if !a.inShard(pkgPath) || !a.inDepthRange(pkgPath) {
+		return
+	}
+
+	var orphans []*Symbol
+	for _, id := range nodes {
+		key := a.graph.keys[id]
+		symbol := a.symbols[key]
+		if a.reached[id] != 0 || a.isTestFunction(symbol.Name) || a.isDocsOnly(key) {
+			continue
+		}
+		a.markOrphan(key, symbol)
+		if a.isMainHelper(symbol) || a.generatedApart(symbol) {
+			continue
+		}
+		orphans = append(orphans, symbol)
+	}
+	if len(orphans) == 0 {
+		return
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].File != orphans[j].File {
+			return orphans[i].File < orphans[j].File
+		}
+		return orphans[i].Start.Line < orphans[j].Start.Line
+	})
+
+	// Past --max-findings only the count is kept, for the overflow summary
+	limit := len(orphans)
+	if a.config.MaxFindings > 0 {
+		limit = min(limit, max(a.config.MaxFindings-a.streamed, 0))
+	}
+	a.streamed += len(orphans)
+	if limit == 0 {
+		return
+	}
+
+	fmt.Printf("=== %s ===\n", pkgPath)
+	for _, orphan := range orphans[:limit] {
+		a.printOrphan(orphan)
+	}
+	fmt.Println()
+}
+
+// stronglyConnected computes the strongly connected components of a graph with Tarjan's
+// algorithm, in reverse topological order, and the component index of every node
+func stronglyConnected(n int, edges []map[int]bool) ([][]int, []int) {
+	index := make([]int, n)
+	lowlink := make([]int, n)
+	onStack := make([]bool, n)
+	componentOf := make([]int, n)
+	for i := range index {
+		index[i] = -1
+	}
+
+	var components [][]int
+	var stack []int
+	next := 0
+
+	var connect func(v int)
+	connect = func(v int) {
+		index[v] = next
+		lowlink[v] = next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		// Visit successors in a stable order so the output order is deterministic
+		successors := make([]int, 0, len(edges[v]))
+		for w := range edges[v] {
+			successors = append(successors, w)
+		}
+		sort.Ints(successors)
+
+		for _, w := range successors {
+			if index[w] < 0 {
+				connect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] == index[v] {
+			var component []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				componentOf[w] = len(components)
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for v := 0; v < n; v++ {
+		if index[v] < 0 {
+			connect(v)
+		}
+	}
+
+	return components, componentOf
+}
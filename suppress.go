@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	suppressProject  string
+	suppressBaseline string
+	suppressKinds    []string
+	suppressPackages []string
+	suppressNames    []string
+	suppressState    string
+	suppressReason   string
+	suppressAuthor   string
+	suppressDryRun   bool
+)
+
+// symbolKinds lists the kinds of symbols findings can have
+var symbolKinds = []string{"function", "method", "type", "variable", "constant", "field"}
+
+// FindingFilter selects current findings by kind, package and name; an empty criterion
+// matches every finding
+type FindingFilter struct {
+	Kinds    []string
+	Packages []string // import path patterns, or directory patterns relative to the project (./internal/...)
+	Names    []string // globs on the name, Type.Method for methods
+}
+
+// validate checks that the filter selects something narrower than every finding
+func (f *FindingFilter) validate() error {
+	if len(f.Kinds) == 0 && len(f.Packages) == 0 && len(f.Names) == 0 {
+		return fmt.Errorf("at least one of --kind, --package or --name is required")
+	}
+	for _, kind := range f.Kinds {
+		if !slices.Contains(symbolKinds, kind) {
+			return fmt.Errorf("invalid --kind %q (expected one of %s)", kind, strings.Join(symbolKinds, ", "))
+		}
+	}
+	for _, pattern := range append(append([]string(nil), f.Packages...), f.Names...) {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/..."), ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchesFilter reports whether a finding meets every criterion of the filter
+func (a *Analyzer) matchesFilter(filter *FindingFilter, symbol *Symbol) bool {
+	if len(filter.Kinds) > 0 && !slices.Contains(filter.Kinds, symbol.Kind) {
+		return false
+	}
+	if len(filter.Packages) > 0 && !a.matchesPackage(filter.Packages, symbol) {
+		return false
+	}
+	if len(filter.Names) > 0 {
+		matched := false
+		for _, pattern := range filter.Names {
+			if ok, _ := path.Match(pattern, symbol.keyName()); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesPackage matches the package of a finding against import path patterns, or its
+// directory against patterns starting with ./ relative to the project
+func (a *Analyzer) matchesPackage(patterns []string, symbol *Symbol) bool {
+	dir := path.Dir(a.relativePath(symbol.File))
+	for _, pattern := range patterns {
+		if relative, ok := strings.CutPrefix(filepath.ToSlash(pattern), "./"); ok {
+			if relative == "..." || matchPackagePattern(relative, dir) {
+				return true
+			}
+			continue
+		}
+		if matchPackagePattern(pattern, symbol.Package) {
+			return true
+		}
+	}
+	return false
+}
+
+var suppressCmd = &cobra.Command{
+	Use:   "suppress",
+	Short: "Record every current finding matching a filter in the baseline",
+	Long: `Runs the analysis and records the current findings matching --kind, --package and
+--name in the baseline, as wontfix by default, with a reason and an author. Large-scale
+triage, such as accepting all the dead constants of a legacy package, then needs no
+hand-editing of the baseline. Packages are import path patterns, or directory patterns
+relative to the project when they start with ./; names are globs. Findings already in
+the requested state are left as they are.`,
+	Example: `  gorphanage suppress --kind constant --package ./internal/legacy/... --reason "kept for wire compatibility"
+  gorphanage suppress --package example.com/app/gen/... --state acknowledged --reason "regenerated in Q3"
+  gorphanage suppress --name 'Test*' --dry-run --reason "fixtures"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if suppressState != StateAcknowledged && suppressState != StateWontfix {
+			return fmt.Errorf("invalid --state %q (expected acknowledged or wontfix)", suppressState)
+		}
+		if suppressReason == "" {
+			return fmt.Errorf("--reason is required: it explains the suppression to the next reader of the baseline")
+		}
+		filter := &FindingFilter{Kinds: suppressKinds, Packages: suppressPackages, Names: suppressNames}
+		if err := filter.validate(); err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		config, err := configFromViper(suppressProject)
+		if err != nil {
+			return err
+		}
+		config.OutputJSON = true
+
+		analyzer, result, err := analyze(config)
+		if err != nil {
+			return err
+		}
+
+		baseline, err := LoadBaseline(resolveBaselinePath(config.ProjectPath, suppressBaseline))
+		if err != nil {
+			return err
+		}
+
+		author := suppressAuthor
+		if author == "" {
+			author = currentAuthor(config.ProjectPath)
+		}
+
+		suppressed, unchanged := 0, 0
+		for _, symbol := range result.OrphanedSymbols {
+			if !analyzer.matchesFilter(filter, symbol) {
+				continue
+			}
+			if baseline.StateOf(symbol) == suppressState {
+				unchanged++
+				continue
+			}
+			suppressed++
+			fmt.Printf("✅ %s.%s (%s) → %s\n", symbol.Package, symbol.displayName(), symbol.Kind, suppressState)
+			if !suppressDryRun {
+				baseline.Set(symbol, suppressState, suppressReason, author)
+			}
+		}
+
+		if suppressDryRun {
+			fmt.Printf("\n🔍 Dry run: %d finding(s) would be recorded as %s, %d already are\n", suppressed, suppressState, unchanged)
+			return nil
+		}
+		fmt.Printf("\n📝 Recorded %d finding(s) as %s in %s, %d already were\n", suppressed, suppressState, baseline.path, unchanged)
+		if suppressed == 0 {
+			return nil
+		}
+		return baseline.Save()
+	},
+}
+
+func init() {
+	suppressCmd.Flags().StringVar(&suppressProject, "project", ".", "project path")
+	suppressCmd.Flags().StringVar(&suppressBaseline, "baseline", "", "baseline file (default is <project>/"+DefaultBaselineFile+")")
+	suppressCmd.Flags().StringSliceVar(&suppressKinds, "kind", nil, "suppress findings of these kinds: "+strings.Join(symbolKinds, ", "))
+	suppressCmd.Flags().StringSliceVar(&suppressPackages, "package", nil, "suppress findings of packages matching these patterns: import paths, or directories starting with ./, e.g. ./internal/legacy/...")
+	suppressCmd.Flags().StringSliceVar(&suppressNames, "name", nil, "suppress findings whose name matches these globs (Type.Method for methods)")
+	suppressCmd.Flags().StringVar(&suppressState, "state", StateWontfix, "state recorded: wontfix or acknowledged")
+	suppressCmd.Flags().StringVar(&suppressReason, "reason", "", "reason recorded with every suppressed finding (required)")
+	suppressCmd.Flags().StringVar(&suppressAuthor, "author", "", "author recorded with every suppressed finding (default is git user.name)")
+	suppressCmd.Flags().BoolVar(&suppressDryRun, "dry-run", false, "list the matching findings without changing the baseline")
+	rootCmd.AddCommand(suppressCmd)
+}
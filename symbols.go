@@ -3,33 +3,59 @@ package main
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
 // findSymbols discovers all symbols in the project
 func (a *Analyzer) findSymbols() error {
+	a.assignKeyPaths()
 	for _, pkg := range a.packages {
 		for i, file := range pkg.Syntax {
-			if i < len(pkg.CompiledGoFiles) {
-				a.findSymbolsInFile(pkg, file, pkg.CompiledGoFiles[i])
+			if i >= len(pkg.CompiledGoFiles) {
+				continue
 			}
+			// cmd/cgo rewrites files importing "C" into the build cache, with line
+			// directives pointing back at the source
+			filename := pkg.CompiledGoFiles[i]
+			if isCgoGenerated(file) {
+				if source := a.fileSet.Position(file.Package).Filename; source != "" {
+					filename = source
+				}
+			} else if hasGeneratedHeader(file) {
+				a.generatedFiles[filename] = true
+			}
+			a.findSymbolsInFile(pkg, file, filename)
+			a.recordFileDecls(pkg, file, filename)
 		}
 	}
 	return nil
 }
 
-// findSymbolsInFile extracts symbols from a single file
+// findSymbolsInFile extracts the package-level symbols of a single file. Declarations
+// inside function bodies are local and never symbols of the package.
 func (a *Analyzer) findSymbolsInFile(pkg *packages.Package, file *ast.File, filename string) {
-	ast.Inspect(file, func(n ast.Node) bool {
-		switch node := n.(type) {
+	a.projectPkgs[pkg.PkgPath] = true
+	for _, decl := range file.Decls {
+		switch node := decl.(type) {
 		case *ast.FuncDecl:
 			a.processFunctionDecl(pkg, node, filename)
 		case *ast.GenDecl:
 			a.processGenDecl(pkg, node, filename)
 		}
-		return true
-	})
+	}
+}
+
+// declare registers a symbol under its key and the object its identifier defines. Every
+// package variant, such as the one compiled with tests, defines its own objects for the
+// same declaration; all of them denote the symbol.
+func (a *Analyzer) declare(pkg *packages.Package, ident *ast.Ident, key string, symbol *Symbol) {
+	a.symbols[key] = symbol
+	if obj := pkg.TypesInfo.Defs[ident]; obj != nil {
+		a.objectIDs[obj] = a.graph.intern(key)
+	}
 }
 
 // processFunctionDecl processes function declarations
@@ -41,9 +67,10 @@ func (a *Analyzer) processFunctionDecl(pkg *packages.Package, node *ast.FuncDecl
 	startPos := a.fileSet.Position(node.Pos())
 	endPos := a.fileSet.Position(node.End())
 
+	name, kind := funcDeclName(node)
 	symbol := &Symbol{
 		Name:     node.Name.Name,
-		Kind:     "function",
+		Kind:     kind,
 		File:     filename,
 		Position: startPos,
 		Start: Position{
@@ -54,12 +81,76 @@ func (a *Analyzer) processFunctionDecl(pkg *packages.Package, node *ast.FuncDecl
 			Line:   endPos.Line,
 			Column: endPos.Column,
 		},
-		Exported: ast.IsExported(node.Name.Name),
-		Package:  pkg.PkgPath,
+		Exported:  ast.IsExported(node.Name.Name),
+		Package:   pkg.PkgPath,
+		Module:    moduleOf(pkg),
+		ModuleDir: a.moduleDirs[pkg.Types],
+		Generic:   node.Type.TypeParams != nil && len(node.Type.TypeParams.List) > 0,
+	}
+	if kind == "method" {
+		symbol.Receiver = receiverName(node.Recv.List[0].Type)
 	}
 
-	key := a.getSymbolKey(pkg.PkgPath, node.Name.Name, "function")
-	a.symbols[key] = symbol
+	a.declare(pkg, node.Name, a.getSymbolKey(a.keyPath(pkg.Types), name, kind), symbol)
+}
+
+// funcDeclName returns the key name and kind of a function declaration. Methods are keyed
+// by their receiver's base type, Type.Method, so that methods of different types and
+// functions of the same name stay apart.
+func funcDeclName(decl *ast.FuncDecl) (string, string) {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name, "function"
+	}
+	return strings.TrimPrefix(receiverName(decl.Recv.List[0].Type), "*") + "." + decl.Name.Name, "method"
+}
+
+// receiverName returns a receiver type as written without type parameters: T or *T
+func receiverName(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.StarExpr:
+		return "*" + receiverName(e.X)
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// keyPackage returns the package part of a symbol's key: its import path, qualified by
+// its module when several project modules provide that path
+func (s *Symbol) keyPackage() string {
+	if s.ModuleDir != "" {
+		return qualifiedPackagePath(s.Package, s.Module, s.ModuleDir)
+	}
+	return s.Package
+}
+
+// keyName returns the name part of a symbol's key: Type.Method for methods, else the name
+func (s *Symbol) keyName() string {
+	if s.Receiver != "" {
+		return strings.TrimPrefix(s.Receiver, "*") + "." + s.Name
+	}
+	return s.Name
+}
+
+// displayName returns a symbol's name as Go writes it in stack traces: (*T).Method,
+// T.Method or Name
+func (s *Symbol) displayName() string {
+	if base, ok := strings.CutPrefix(s.Receiver, "*"); ok {
+		return "(*" + base + ")." + s.Name
+	}
+	if s.Receiver != "" {
+		return s.Receiver + "." + s.Name
+	}
+	return s.Name
+}
+
+// symbolKey returns the key of a symbol
+func (a *Analyzer) symbolKey(symbol *Symbol) string {
+	return a.getSymbolKey(symbol.keyPackage(), symbol.keyName(), symbol.Kind)
 }
 
 // processGenDecl processes general declarations (types, variables, constants)
@@ -96,17 +187,56 @@ func (a *Analyzer) processTypeSpec(pkg *packages.Package, spec *ast.TypeSpec, fi
 			Line:   endPos.Line,
 			Column: endPos.Column,
 		},
-		Exported: ast.IsExported(spec.Name.Name),
-		Package:  pkg.PkgPath,
+		Exported:  ast.IsExported(spec.Name.Name),
+		Package:   pkg.PkgPath,
+		Module:    moduleOf(pkg),
+		ModuleDir: a.moduleDirs[pkg.Types],
+		Generic:   spec.TypeParams != nil && len(spec.TypeParams.List) > 0,
+	}
+	if obj := pkg.TypesInfo.Defs[spec.Name]; obj != nil {
+		symbol.Constraint = isConstraintInterface(obj.Type())
 	}
 
-	key := a.getSymbolKey(pkg.PkgPath, spec.Name.Name, "type")
-	a.symbols[key] = symbol
+	key := a.getSymbolKey(a.keyPath(pkg.Types), spec.Name.Name, "type")
+	a.declare(pkg, spec.Name, key, symbol)
+
+	if spec.Assign.IsValid() {
+		a.recordAlias(pkg, spec, key)
+	}
+}
+
+// recordAlias links an alias declaration (type Foo = other.Foo) with the type it names, in both
+// directions, so that reaching either end of an alias chain keeps the other end alive
+func (a *Analyzer) recordAlias(pkg *packages.Package, spec *ast.TypeSpec, aliasKey string) {
+	obj, ok := pkg.TypesInfo.Defs[spec.Name].(*types.TypeName)
+	if !ok {
+		return
+	}
+	alias, ok := obj.Type().(*types.Alias)
+	if !ok {
+		return
+	}
+
+	// Link to the next step of the chain only; that step links onwards itself
+	var target *types.TypeName
+	switch rhs := alias.Rhs().(type) {
+	case *types.Alias:
+		target = rhs.Obj()
+	case *types.Named:
+		target = rhs.Obj()
+	}
+	if target == nil || target.Pkg() == nil {
+		return
+	}
+
+	targetKey := a.getSymbolKey(a.keyPath(target.Pkg()), target.Name(), "type")
+	a.aliasLinks[aliasKey] = append(a.aliasLinks[aliasKey], targetKey)
+	a.aliasLinks[targetKey] = append(a.aliasLinks[targetKey], aliasKey)
 }
 
 // processValueSpec processes variable and constant specifications
 func (a *Analyzer) processValueSpec(pkg *packages.Package, spec *ast.ValueSpec, tok token.Token, filename string) {
-	for _, name := range spec.Names {
+	for i, name := range spec.Names {
 		if name == nil || name.Name == "_" {
 			continue
 		}
@@ -119,6 +249,16 @@ func (a *Analyzer) processValueSpec(pkg *packages.Package, spec *ast.ValueSpec,
 			kind = "constant"
 		}
 
+		// Variable initializers run at program start even when the variable is never used
+		sideEffects := false
+		if kind == "variable" && len(spec.Values) > 0 {
+			value := spec.Values[0]
+			if i < len(spec.Values) {
+				value = spec.Values[i]
+			}
+			sideEffects = a.hasSideEffects(pkg, value)
+		}
+
 		symbol := &Symbol{
 			Name:     name.Name,
 			Kind:     kind,
@@ -132,11 +272,14 @@ func (a *Analyzer) processValueSpec(pkg *packages.Package, spec *ast.ValueSpec,
 				Line:   endPos.Line,
 				Column: endPos.Column,
 			},
-			Exported: ast.IsExported(name.Name),
-			Package:  pkg.PkgPath,
+			Exported:  ast.IsExported(name.Name),
+			Package:   pkg.PkgPath,
+			Module:    moduleOf(pkg),
+			ModuleDir: a.moduleDirs[pkg.Types],
+
+			DeleteWithCare: sideEffects,
 		}
 
-		key := a.getSymbolKey(pkg.PkgPath, name.Name, kind)
-		a.symbols[key] = symbol
+		a.declare(pkg, name, a.getSymbolKey(a.keyPath(pkg.Types), name.Name, kind), symbol)
 	}
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// todoFileName is the checklist written into each package directory by --write-todos
+const todoFileName = "DEADCODE.md"
+
+// todoMarker starts every generated checklist, so that stale ones can be told apart from
+// files written by hand
+const todoMarker = "<!-- generated by gorphanage --write-todos; do not edit the list by hand -->"
+
+// WriteTodos drops a DEADCODE.md checklist of its orphans into every package directory,
+// for cleanup campaigns tracked in code review. Checklists written by a previous run for
+// packages that no longer have orphans are removed; hand-written files of the same name
+// are never touched. Findings marked wontfix in the baseline are left out.
+func (a *Analyzer) WriteTodos(result *AnalysisResult) error {
+	byDir := make(map[string][]*Symbol)
+	for _, orphan := range result.OrphanedSymbols {
+		if orphan.State == StateWontfix {
+			continue
+		}
+		dir := filepath.Dir(orphan.File)
+		byDir[dir] = append(byDir[dir], orphan)
+	}
+
+	written := 0
+	for dir, orphans := range byDir {
+		path := filepath.Join(dir, todoFileName)
+		if !isGeneratedTodo(path) {
+			if a.config.Verbose && !a.config.OutputJSON {
+				fmt.Printf("⚠️  Not overwriting hand-written %s\n", a.relativePath(path))
+			}
+			continue
+		}
+		if err := os.WriteFile(path, todoChecklist(orphans), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", todoFileName, err)
+		}
+		written++
+	}
+
+	removed := 0
+	for _, pkg := range a.packages {
+		if len(pkg.GoFiles) == 0 || !a.inShard(pkg.PkgPath) {
+			continue
+		}
+		dir := filepath.Dir(pkg.GoFiles[0])
+		if _, ok := byDir[dir]; ok {
+			continue
+		}
+		path := filepath.Join(dir, todoFileName)
+		if _, err := os.Stat(path); err != nil || !isGeneratedTodo(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove stale %s: %w", todoFileName, err)
+		}
+		removed++
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("📝 Wrote %d %s file(s), removed %d stale one(s)\n", written, todoFileName, removed)
+	}
+
+	return nil
+}
+
+// isGeneratedTodo reports whether a checklist path is free or holds a generated checklist
+func isGeneratedTodo(path string) bool {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return true
+	}
+	return err == nil && bytes.HasPrefix(data, []byte(todoMarker))
+}
+
+// todoChecklist renders the checklist of one package directory's orphans
+func todoChecklist(orphans []*Symbol) []byte {
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].File != orphans[j].File {
+			return orphans[i].File < orphans[j].File
+		}
+		return orphans[i].Start.Line < orphans[j].Start.Line
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, todoMarker)
+	fmt.Fprintf(&buf, "# Dead code in `%s`\n\n", orphans[0].Package)
+	fmt.Fprintf(&buf, "gorphanage found %d symbol(s) in this package that are not reachable from any entry point.\n", len(orphans))
+	fmt.Fprintln(&buf, "Check off each one as it is deleted or confirmed to be used, and rerun gorphanage to refresh the list.")
+	fmt.Fprintln(&buf)
+
+	for _, orphan := range orphans {
+		file := filepath.Base(orphan.File)
+		fmt.Fprintf(&buf, "- [ ] `%s` (%s, %s) - [%s:%d](%s#L%d)\n",
+			orphan.displayName(), orphan.Kind, orphan.Deadness, file, orphan.Start.Line, file, orphan.Start.Line)
+	}
+
+	return buf.Bytes()
+}
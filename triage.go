@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	triageProject  string
+	triageBaseline string
+	triageReason   string
+	triageAuthor   string
+
+	baselineWriteFile   string
+	baselineWriteReason string
+	baselineWriteAuthor string
+	baselineWritePrune  bool
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Manage finding lifecycle states in the baseline",
+	Long: `Record the lifecycle state of findings in the baseline file:
+
+  new           not yet looked at (the default for findings missing from the baseline)
+  acknowledged  known dead code, scheduled for cleanup
+  wontfix       intentionally kept`,
+}
+
+var triageSetCmd = &cobra.Command{
+	Use:   "set <state> <symbol>...",
+	Short: "Set the state of one or more current findings",
+	Long: `Set the lifecycle state of current findings. Symbols are matched by name
+or by package-qualified name (example.com/app/pkg.Name); methods also by
+Type.Method.`,
+	Example: `  gorphanage triage set acknowledged example.com/app/internal/legacy.Parse --reason "removed in v2"
+  gorphanage triage set wontfix debugDump`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state := args[0]
+		if !isValidState(state) {
+			return fmt.Errorf("invalid state %q (expected one of %s)", state, strings.Join(validStates, ", "))
+		}
+
+		config, err := configFromViper(triageProject)
+		if err != nil {
+			return err
+		}
+		config.OutputJSON = true
+
+		_, result, err := analyze(config)
+		if err != nil {
+			return err
+		}
+
+		baseline, err := LoadBaseline(resolveBaselinePath(config.ProjectPath, triageBaseline))
+		if err != nil {
+			return err
+		}
+
+		author := triageAuthor
+		if author == "" {
+			author = currentAuthor(config.ProjectPath)
+		}
+
+		for _, name := range args[1:] {
+			matches := matchFindings(result.OrphanedSymbols, name)
+			if len(matches) == 0 {
+				return fmt.Errorf("no current finding matches %q", name)
+			}
+			for _, symbol := range matches {
+				baseline.Set(symbol, state, triageReason, author)
+				fmt.Printf("✅ %s.%s (%s) → %s\n", symbol.Package, symbol.displayName(), symbol.Kind, state)
+			}
+		}
+
+		return baseline.Save()
+	},
+}
+
+var triageListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List triaged findings by state",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := configFromViper(triageProject)
+		if err != nil {
+			return err
+		}
+
+		baseline, err := LoadBaseline(resolveBaselinePath(config.ProjectPath, triageBaseline))
+		if err != nil {
+			return err
+		}
+
+		byState := make(map[string][]*BaselineEntry)
+		for _, entry := range baseline.entries {
+			byState[entry.State] = append(byState[entry.State], entry)
+		}
+
+		for _, state := range validStates {
+			entries := byState[state]
+			if len(entries) == 0 {
+				continue
+			}
+			sort.Slice(entries, func(i, j int) bool { return entries[i].Fingerprint < entries[j].Fingerprint })
+
+			fmt.Printf("=== %s (%d) ===\n", state, len(entries))
+			for _, entry := range entries {
+				fmt.Printf("  📍 %s.%s (%s)", entry.Package, entry.Name, entry.Kind)
+				if entry.Reason != "" {
+					fmt.Printf(" - %s", entry.Reason)
+				}
+				fmt.Println()
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Manage the baseline of known findings",
+}
+
+var baselineWriteCmd = &cobra.Command{
+	Use:   "write [project-path]",
+	Short: "Record the current orphans in the baseline",
+	Long: `Records every current orphan missing from the baseline as acknowledged, keeping the
+state of the findings already in it. Later runs with --fail-on-new report, and fail
+on, only the orphans missing from the baseline: a legacy codebase can adopt the
+analysis without cleaning up first, and pay the debt down over time.
+
+With --prune, entries matching no current orphan are dropped, such as findings
+deleted since.`,
+	Example: `  gorphanage baseline write .
+  gorphanage --fail-on-new .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+
+		config, err := configFromViper(projectPath)
+		if err != nil {
+			return err
+		}
+		config.OutputJSON = true
+
+		_, result, err := analyze(config)
+		if err != nil {
+			return err
+		}
+
+		path := baselineWriteFile
+		if path == "" {
+			path = config.BaselineFile
+		}
+		baseline, err := LoadBaseline(resolveBaselinePath(config.ProjectPath, path))
+		if err != nil {
+			return err
+		}
+
+		author := baselineWriteAuthor
+		if author == "" {
+			author = currentAuthor(config.ProjectPath)
+		}
+
+		added := baseline.Record(result.OrphanedSymbols, baselineWriteReason, author)
+		pruned := 0
+		if baselineWritePrune {
+			pruned = baseline.Prune(result.OrphanedSymbols)
+		}
+		if err := baseline.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("📒 Recorded %d new finding(s) in %s (%d in total)\n", added, baseline.path, len(baseline.entries))
+		if pruned > 0 {
+			fmt.Printf("🧹 Dropped %d finding(s) no longer reported\n", pruned)
+		}
+		return nil
+	},
+}
+
+func init() {
+	triageCmd.PersistentFlags().StringVar(&triageProject, "project", ".", "project path")
+	triageCmd.PersistentFlags().StringVar(&triageBaseline, "baseline", "", "baseline file (default is <project>/"+DefaultBaselineFile+")")
+	triageSetCmd.Flags().StringVar(&triageReason, "reason", "", "reason recorded with the state change")
+	triageSetCmd.Flags().StringVar(&triageAuthor, "author", "", "author recorded with the state change (default is git user.name)")
+
+	triageCmd.AddCommand(triageSetCmd)
+	triageCmd.AddCommand(triageListCmd)
+	rootCmd.AddCommand(triageCmd)
+
+	baselineWriteCmd.Flags().StringVar(&baselineWriteFile, "baseline", "", "baseline file (default is <project>/"+DefaultBaselineFile+")")
+	baselineWriteCmd.Flags().StringVar(&baselineWriteReason, "reason", "", "reason recorded with the new findings")
+	baselineWriteCmd.Flags().StringVar(&baselineWriteAuthor, "author", "", "author recorded with the new findings (default is git user.name)")
+	baselineWriteCmd.Flags().BoolVar(&baselineWritePrune, "prune", false, "drop entries matching no current orphan")
+
+	baselineCmd.AddCommand(baselineWriteCmd)
+	rootCmd.AddCommand(baselineCmd)
+}
+
+// matchFindings returns the findings any of whose names equals name
+func matchFindings(findings []*Symbol, name string) []*Symbol {
+	var matches []*Symbol
+	for _, symbol := range findings {
+		for _, candidate := range symbolNames(symbol) {
+			if candidate == name {
+				matches = append(matches, symbol)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// currentAuthor returns the git user name, falling back to the login name
+func currentAuthor(dir string) string {
+	if name, err := gitOutput(dir, "config", "user.name"); err == nil && name != "" {
+		return name
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// shownFindings returns the orphans listed in detail under --max-findings, in file order so
+// that the same findings are shown from run to run, and whether any were left out
+func shownFindings(orphans []*Symbol, maxFindings int) ([]*Symbol, bool) {
+	if maxFindings <= 0 || len(orphans) <= maxFindings {
+		return orphans, false
+	}
+
+	sorted := append([]*Symbol(nil), orphans...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Start.Line < sorted[j].Start.Line
+	})
+	return sorted[:maxFindings], true
+}
+
+// orphansByPackage counts the orphans of every package
+func orphansByPackage(orphans []*Symbol) map[string]int {
+	counts := make(map[string]int)
+	for _, orphan := range orphans {
+		counts[orphan.Package]++
+	}
+	return counts
+}
+
+// truncateResult returns the result written as JSON under --max-findings: a copy listing
+// at most maxFindings orphans, with the complete total and per-package counts alongside
+func truncateResult(result *AnalysisResult, maxFindings int) *AnalysisResult {
+	shown, truncated := shownFindings(result.OrphanedSymbols, maxFindings)
+	if !truncated {
+		return result
+	}
+
+	capped := *result
+	capped.OrphanedSymbols = shown
+	capped.Truncated = true
+	capped.TotalOrphans = len(result.OrphanedSymbols)
+	capped.OrphansByPackage = orphansByPackage(result.OrphanedSymbols)
+	return &capped
+}
+
+// printOverflow summarizes the orphans left out of the detailed list, with the orphan
+// count of every package, largest first
+func printOverflow(orphans []*Symbol, shown, maxFindings int) {
+	fmt.Printf("… %d more orphan(s) not shown (--max-findings %d). Orphans by package:\n", len(orphans)-shown, maxFindings)
+
+	counts := orphansByPackage(orphans)
+	pkgPaths := make([]string, 0, len(counts))
+	for pkgPath := range counts {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Slice(pkgPaths, func(i, j int) bool {
+		if counts[pkgPaths[i]] != counts[pkgPaths[j]] {
+			return counts[pkgPaths[i]] > counts[pkgPaths[j]]
+		}
+		return pkgPaths[i] < pkgPaths[j]
+	})
+	for _, pkgPath := range pkgPaths {
+		fmt.Printf("  • %s: %d\n", pkgPath, counts[pkgPath])
+	}
+	fmt.Println()
+}
@@ -2,28 +2,137 @@ package main
 
 import (
 	"go/token"
+	"go/types"
 
 	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
 )
 
 // Config holds the configuration for the analysis
 type Config struct {
-	ProjectPath  string
-	OutputJSON   bool
-	Verbose      bool
-	Exclude      []string
-	IncludeTests bool
+	ProjectPath        string
+	OutputJSON         bool
+	Verbose            bool
+	Exclude            []string
+	IncludeTests       bool
+	IncludeReplaced    bool
+	IncludeTestdata    bool // analyze packages below testdata directories
+	IncludeTools       bool // analyze packages below internal/tools
+	ExportDB           string
+	DumpGraph          string
+	ListReachable      string
+	SizeEstimate       bool
+	WriteTodos         bool
+	Post               string // endpoint the JSON result of every run is POSTed to
+	ImportFindings     string // unused findings of another tool to cross-check
+	CoverProfile       string
+	PprofProfiles      []string
+	BaselineFile       string
+	FailOn             string
+	FailOnNew          bool // report only the orphans missing from the baseline, and fail on them
+	Probe              bool
+	ProbeSamples       int
+	FixUnsafe          bool // probe orphans whose initializer has side effects too, to fix them
+	RootRules          []RootRule
+	CallbackRegistries []CallbackRegistry
+	StringRegistries   []StringRegistry
+	FrameworkDetectors []FrameworkDetector `json:"-"`
+	EntryPoints        []EntryPoint
+	InterfaceAllowlist []string // pkg.Interface contracts whose implementing methods are kept
+	WellKnownMethods   []string // method names kept with their receiver type, beyond the defaults
+	Frameworks         []string // built-in framework detectors to run, nil for all
+	ByAuthor           bool
+	Stream             bool
+	Semantics          string
+	Precision          string   // how calls are resolved: references, rta or vta
+	Reflection         string   // how reflection is accounted for: none or conservative
+	IgnoreAssertions   bool     // keep-alive assertions (var _ = f, var _ I = (*T)(nil)) keep nothing
+	MainUnexported     string   // how unexported orphans of main packages are reported: report or group
+	Generated          string   // how orphans of generated files are reported: include, exclude or separate
+	WithReferences     bool     // list the references to every reachable symbol in the result
+	CodeActions        bool     // attach editor quick fixes to every orphan in the result
+	Tags               []string // build tags packages are loaded with
+	Platforms          []string // os/arch pairs build constraints must be satisfiable on
+	BuildMatrix        []string // os/arch[+tag...] configurations an orphan must be dead in
+	PerTargetReport    bool     // list the orphans of each build matrix configuration on its own
+	Recursive          bool     // analyze every module below the project on its own and merge the results
+	ShardIndex         int      // 1-based shard reported by this run
+	ShardCount         int      // number of shards, 0 when not sharded
+	Daemon             bool     // run the analysis in a background daemon keeping the project loaded
+	ComponentsFile     string   // component manifest; default is components.yaml in the project root
+	Fields             bool     // report struct fields that are never read
+	Results            bool     // report function results that no caller uses
+	Wrappers           bool     // report functions only converting or copying, with few callers
+	SuggestUnexport    bool     // report exported symbols only their own package references
+	WrapperMaxCallers  int      // callers up to which a trivial wrapper is reported
+	MaxFindings        int      // orphans listed in detail, 0 for all
+	SkipPackagesOver   int      // packages declaring more symbols are left out, 0 for no limit
+	MinImportDepth     int      // report only orphans of packages at least this deep in the import graph
+	MaxImportDepth     int      // report only orphans of packages at most this deep, 0 for no limit
+	Sort               string   // order of the listed orphans: file or depth, analysis order when empty
+	Hooks              Hooks    `json:"-"` // callbacks of an embedding application
 }
 
 // Symbol represents a code symbol (function, type, variable, constant)
 type Symbol struct {
 	Name     string   `json:"name"`
-	Kind     string   `json:"kind"` // "function", "variable", "type", "constant"
+	Kind     string   `json:"kind"` // "function", "method", "variable", "type", "constant", "field"
 	File     string   `json:"file"`
 	Start    Position `json:"start"`
 	End      Position `json:"end"`
 	Exported bool     `json:"exported"`
 	Package  string   `json:"package"`
+	Module   string   `json:"module,omitempty"`
+	// ModuleDir is set when several project modules provide the package path, e.g. a
+	// vendored fork: the directory of the symbol's module, relative to the project
+	ModuleDir string `json:"module_dir,omitempty"`
+	Generic   bool   `json:"generic,omitempty"`
+	Receiver  string `json:"receiver,omitempty"` // receiver type of a method, e.g. *Server
+
+	// Verdict annotations
+	Confidence string `json:"confidence,omitempty"`
+	Coverage   string `json:"coverage,omitempty"`
+
+	RuntimeObserved bool `json:"runtime_observed,omitempty"`
+	DeleteWithCare  bool `json:"delete_with_care,omitempty"` // initializer has side effects
+	Reflection      bool `json:"reflection,omitempty"`       // exported method in a package importing reflect, may be called through it
+	Generated       bool `json:"generated,omitempty"`        // declared in a file with the generated code header
+
+	ConfirmedBy string `json:"confirmed_by,omitempty"` // other tool reporting the symbol unused, with --import-findings
+
+	State    string `json:"state,omitempty"`    // lifecycle state from the baseline
+	Deadness string `json:"deadness,omitempty"` // "hard" (unreferenced) or "soft" (referenced only by dead code)
+
+	GeneratedTwins []string `json:"generated_twins,omitempty"` // generated files derived from the symbol
+
+	Variants []*BuildVariant  `json:"variants,omitempty"` // per-file verdicts of a symbol declared in build-variant files
+	Targets  []*TargetVerdict `json:"targets,omitempty"`  // per-configuration verdicts, with --build-matrix
+
+	KeptBy *RefLocation `json:"kept_by,omitempty"` // keep-alive assertion the verdict hangs on
+
+	StoredIn []string `json:"stored_in,omitempty"` // variables and fields an uncalled function is stored in
+
+	CodeActions []*CodeAction `json:"code_actions,omitempty"` // editor quick fixes, with --code-actions
+
+	NeverInstantiated bool `json:"never_instantiated,omitempty"` // generic without any concrete instantiation
+	Constraint        bool `json:"constraint,omitempty"`         // interface with a type set, only usable in type parameter lists
+
+	Component string `json:"component,omitempty"` // owning component from the component manifest
+
+	ImportDepth int  `json:"import_depth,omitempty"` // package depth in the import graph, 1 for a main or other top package
+	LeafPackage bool `json:"leaf_package,omitempty"` // package imports no other project package
+
+	FieldWrites int `json:"field_writes,omitempty"` // writes of a field that is never read
+
+	IgnoredResults []string `json:"ignored_results,omitempty"` // results every call site ignores, e.g. "#2 error"
+	CallSites      int      `json:"call_sites,omitempty"`      // calls of a function with ignored results
+
+	WrittenAt []RefLocation `json:"written_at,omitempty"` // assignments to a variable that is never read
+
+	Wraps   string `json:"wraps,omitempty"`   // what a trivial wrapper does, e.g. "conversion to Celsius"
+	Callers int    `json:"callers,omitempty"` // declarations referencing a trivial wrapper
+
+	EstimatedBytes int `json:"estimated_bytes,omitempty"` // rough binary size, with --size-estimate
 
 	// Internal fields (not serialized)
 	Position token.Position `json:"-"`
@@ -39,26 +148,144 @@ type Position struct {
 type Reference struct {
 	File     string
 	Position token.Position
+	Pos      token.Pos
+}
+
+// fileUse is a symbol reference recorded while walking a declaration
+type fileUse struct {
+	To   int32 // graph ID of the referenced symbol
+	Kind uint8 // edge kind, an index in edgeKinds
+	Pos  token.Pos
+}
+
+// Edge kinds in the symbol graph
+const (
+	EdgeReference    = "reference"     // use of a variable or constant, or a reference made by name
+	EdgeCall         = "call"          // call of a function or method
+	EdgeAddressTaken = "address-taken" // function or method used as a value, without calling it
+	EdgeTypeUse      = "type-use"      // use of a type, conversions included
+	EdgeEmbed        = "embed"         // type embedded in a struct or interface
+	EdgeAlias        = "alias"
+)
+
+// Edge represents a dependency from one symbol to another
+type Edge struct {
+	From     string
+	To       string
+	Position token.Position
+	Kind     string
 }
 
 // AnalysisResult contains the complete analysis results
 type AnalysisResult struct {
-	ProjectPath      string    `json:"project_path"`
-	TotalSymbols     int       `json:"total_symbols"`
-	ReachableSymbols int       `json:"reachable_symbols"`
-	MainPackages     int       `json:"main_packages"`
-	OrphanedSymbols  []*Symbol `json:"orphaned_symbols"`
-	ExcludedPackages []string  `json:"excluded_packages,omitempty"`
-	IncludedTests    bool      `json:"included_tests"`
+	ProjectPath      string             `json:"project_path"`
+	TotalSymbols     int                `json:"total_symbols"`
+	ReachableSymbols int                `json:"reachable_symbols"`
+	MainPackages     int                `json:"main_packages"`
+	Semantics        string             `json:"semantics"`
+	Precision        string             `json:"precision,omitempty"`
+	OrphanedSymbols  []*Symbol          `json:"orphaned_symbols"`
+	Truncated        bool               `json:"truncated,omitempty"`          // orphaned_symbols was cut by --max-findings
+	TotalOrphans     int                `json:"total_orphans,omitempty"`      // all orphans, when truncated
+	OrphansByPackage map[string]int     `json:"orphans_by_package,omitempty"` // all orphans by package, when truncated
+	ExcludedPackages []string           `json:"excluded_packages,omitempty"`
+	SkippedPackages  []*SkippedPackage  `json:"skipped_packages,omitempty"` // over --skip-packages-over
+	IncludedTests    bool               `json:"included_tests"`
+	SuggestedRoots   []string           `json:"suggested_roots,omitempty"`
+	StateCounts      map[string]int     `json:"state_counts,omitempty"`
+	BaselinedOrphans int                `json:"baselined_orphans,omitempty"` // recorded in the baseline, left out with --fail-on-new
+	ByAuthor         []AuthorSummary    `json:"by_author,omitempty"`         // heuristic, based on git blame
+	ByComponent      []ComponentSummary `json:"by_component,omitempty"`
+
+	InterfaceNarrowings []*InterfaceNarrowing `json:"interface_narrowings,omitempty"`
+	DocsOnlySymbols     []*Symbol             `json:"docs_only_symbols,omitempty"` // reachable only from Example functions
+	ObsoleteFiles       []*ObsoleteFile       `json:"obsolete_files,omitempty"`
+	OrphanedFiles       []*OrphanedFile       `json:"orphaned_files,omitempty"` // files declaring nothing but orphans
+	SizeClusters        []*SizeCluster        `json:"size_clusters,omitempty"`  // largest first, with --size-estimate
+	References          []*SymbolReferences   `json:"references,omitempty"`     // of reachable symbols, with --with-references
+	ExtractableClusters []*ExtractableCluster `json:"extractable_clusters,omitempty"`
+	DeadClusters        []*DeadCluster        `json:"dead_clusters,omitempty"` // largest first
+
+	UnusedConstraintPackages []string           `json:"unused_constraint_packages,omitempty"` // packages declaring only unused constraints
+	OrphanedPackages         []*OrphanedPackage `json:"orphaned_packages,omitempty"`          // packages no main package imports
+	UnreadFields             []*Symbol          `json:"unread_fields,omitempty"`              // struct fields never read, with --fields
+	UnusedResults            []*Symbol          `json:"unused_results,omitempty"`             // functions with results no caller uses, with --results
+	WriteOnlyVariables       []*Symbol          `json:"write_only_variables,omitempty"`       // package-level variables assigned but never read
+	KeptByAssertion          []*Symbol          `json:"kept_by_assertion,omitempty"`          // reachable only through keep-alive assertions
+	UncalledFunctions        []*Symbol          `json:"uncalled_functions,omitempty"`         // functions stored only where nothing reads them
+	TrivialWrappers          []*Symbol          `json:"trivial_wrappers,omitempty"`           // functions only converting or copying, with --wrappers
+	UnexportCandidates       []*Symbol          `json:"unexport_candidates,omitempty"`        // exported symbols only their own package uses, with --suggest-unexport
+	MainHelpers              []*Symbol          `json:"main_helpers,omitempty"`               // unexported orphans of main packages, with --main-unexported group
+	GeneratedOrphans         []*Symbol          `json:"generated_orphans,omitempty"`          // orphans of generated files, with --generated separate
+
+	ToolComparison *ToolComparison  `json:"tool_comparison,omitempty"` // with --import-findings
+	BuildMatrix    []*TargetSummary `json:"build_matrix,omitempty"`    // per configuration, with --build-matrix
+	Modules        []*ModuleSummary `json:"modules,omitempty"`         // per module, with --recursive
+
+	// Labels are the display names of the symbol keys the sections above list, so a result
+	// prints the same without the analysis it came from, as from the daemon or merged
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Analyzer performs the orphaned code analysis
 type Analyzer struct {
-	config       *Config
-	fileSet      *token.FileSet
-	packages     []*packages.Package
-	symbols      map[string]*Symbol
-	references   map[string][]Reference
-	reachable    map[string]bool
-	mainPackages []*packages.Package
+	config          *Config
+	fileSet         *token.FileSet
+	packages        []*packages.Package
+	symbols         map[string]*Symbol
+	objectIDs       map[types.Object]int32    // project symbols by the objects defining them
+	projectPkgs     map[string]bool           // packages whose symbols were collected
+	keyPaths        map[*types.Package]string // package path in symbol keys, qualified when several modules provide it
+	moduleDirs      map[*types.Package]string // module directories of packages with qualified key paths
+	references      map[int32][]token.Pos     // reference positions by symbol ID
+	varWrites       map[int32][]token.Pos     // assignments to each package-level variable
+	writePositions  map[token.Pos]bool        // references that only write a variable
+	funcSinks       map[token.Pos]string      // variable or field each function value taken is stored in, by reference
+	reached         []int32                   // by symbol ID: 0 unreached, 1 entry point, parent ID+2
+	reachableCount  int
+	mainPackages    []*packages.Package
+	semantics       string // resolved analysis semantics, binary or module
+	aliasLinks      map[string][]string
+	walkedFiles     map[string]bool      // files whose references were collected
+	declUses        map[int32][]fileUse  // references made by each symbol's declaration
+	initUses        map[string][]fileUse // references run by blank variable initializers, by package
+	keepAliveUses   map[string][]fileUse // references of blank variables initialized without effect, by package
+	assertedMethods map[string][]int32   // methods checked by var _ I = (*T)(nil) assertions, by package
+	keepAlivePos    map[int32]token.Pos  // first keep-alive assertion referencing or checking each symbol
+	keepAliveRoots  []int32              // entry points rooted only by keep-alive assertions
+	keptAlive       map[int32]int32      // symbols whose verdict hangs on keep-alive assertions, with their root
+	referrerIndex   map[int32][]int32    // symbols referencing each symbol, built on demand
+	referenceOwners map[token.Pos]string // declaration each reference appears in, built on demand
+	graph           *symbolGraph
+	usedMethods     map[string]bool         // referenced methods by pkg.Type.Method
+	callbacks       map[int32]bool          // functions registered with a callback registry
+	handlers        map[int32]string        // functions registered with a framework, with its name
+	linknamed       map[int32]bool          // symbols named by //go:linkname directives
+	assemblyRefs    map[int32]bool          // symbols referenced from assembly files
+	cgoSymbols      map[int32]bool          // functions exported to C and declarations generated by cgo
+	generatedFiles  map[string]bool         // files with the standard generated code header
+	fileDecls       map[string]*fileDecls   // top-level declarations of every file
+	symbolNames     map[string][]string     // symbol keys by name, built on demand for string registries
+	namedReferences int                     // references made by name through string registries
+	allowlisted     map[int32]bool          // methods implementing an allowlisted interface
+	wellKnown       map[int32]bool          // methods with a well-known name, kept with their type
+	invokedMethods  map[int32][]*types.Func // interface methods called in the project, by graph node
+	exampleReached  []bool                  // by symbol ID: reachable from an Example function
+	instantiated    map[int32]bool          // generic symbols with a concrete instantiation
+	genericDeps     map[int32][]int32       // generic symbols instantiated with type parameters of another
+	layers          map[string]packageLayer // import graph position of every package, built on demand
+	reflectedTypes  map[string]*types.Named // project types passed to reflect, by symbol key
+	reflectPkgs     map[string]bool         // packages importing reflect, built on demand
+	narrowings      []*InterfaceNarrowing
+	sizes           map[string]int         // estimated binary size by symbol key
+	program         *ssa.Program           // whole-program SSA form, built for call-graph precision
+	cache           *packageCache          // packages kept loaded between analyses by a daemon
+	target          *BuildTarget           // build matrix configuration analyzed, nil for the host
+	moduleOnly      bool                   // leave nested modules out, analyzed on their own by --recursive
+	fields          map[string]*fieldUsage // struct fields by key, with --fields
+	wholeStructs    map[string]bool        // struct types read as a whole, by key
+	results         map[int32]*resultUsage // result uses of project functions, with --results
+	wrappers        map[int32]string       // functions only converting or copying their operands, with --wrappers
+	streamed        int                    // orphans printed so far by --stream
+	skippedPackages []*SkippedPackage      // packages left out by --skip-packages-over
 }
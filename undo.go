@@ -0,0 +1,170 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// undoJournalFile is the journal of applied fixes, relative to the project root
+const undoJournalFile = ".gorphanage/undo.json"
+
+// UndoJournal records the fixes applied in place, most recent last, so that they can be
+// reverted without version control
+type UndoJournal struct {
+	Version int        `json:"version"`
+	Fixes   []*UndoFix `json:"fixes"`
+}
+
+// UndoFix is one run of the fix command
+type UndoFix struct {
+	Applied time.Time    `json:"applied"`
+	Deleted []string     `json:"deleted"`
+	Files   []*UndoEntry `json:"files"`
+}
+
+// UndoEntry holds the original content of a file edited by a fix. The hashes tell whether
+// the file was changed again after the fix, and whether the stored content is intact.
+type UndoEntry struct {
+	File         string `json:"file"` // relative to the project root
+	OriginalHash string `json:"original_hash"`
+	AppliedHash  string `json:"applied_hash"`
+	Original     []byte `json:"original,omitempty"`
+}
+
+// contentHash returns the hex SHA-256 of file content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadUndoJournal reads the journal of a project; a missing journal is empty
+func loadUndoJournal(projectPath string) (*UndoJournal, error) {
+	journal := &UndoJournal{Version: 1}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, undoJournalFile))
+	if os.IsNotExist(err) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read undo journal: %w", err)
+	}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("failed to parse undo journal: %w", err)
+	}
+	return journal, nil
+}
+
+// save writes the journal back, removing it once no fix is left to undo
+func (j *UndoJournal) save(projectPath string) error {
+	path := filepath.Join(projectPath, undoJournalFile)
+	if len(j.Fixes) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove undo journal: %w", err)
+		}
+		os.Remove(filepath.Dir(path)) // only succeeds when nothing else lives there
+		return nil
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo journal: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(undoJournalFile), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write undo journal: %w", err)
+	}
+	return nil
+}
+
+// recordUndo appends a fix applied in place to the project's undo journal
+func (a *Analyzer) recordUndo(deleted []*Symbol, originals map[string][]byte) error {
+	journal, err := loadUndoJournal(a.config.ProjectPath)
+	if err != nil {
+		return err
+	}
+
+	fix := &UndoFix{Applied: time.Now().UTC().Truncate(time.Second)}
+	for _, symbol := range deleted {
+		fix.Deleted = append(fix.Deleted, symbol.Package+"."+symbol.displayName())
+	}
+	files := make([]string, 0, len(originals))
+	for file := range originals {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		applied, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		fix.Files = append(fix.Files, &UndoEntry{
+			File:         a.relativePath(file),
+			OriginalHash: contentHash(originals[file]),
+			AppliedHash:  contentHash(applied),
+			Original:     originals[file],
+		})
+	}
+
+	journal.Fixes = append(journal.Fixes, fix)
+	return journal.save(a.config.ProjectPath)
+}
+
+// undoLastFix restores the files edited by the most recent fix recorded in the journal.
+// Nothing is restored when any of them was changed since, or when the journal is damaged.
+func undoLastFix(projectPath string) (*UndoFix, error) {
+	journal, err := loadUndoJournal(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(journal.Fixes) == 0 {
+		return nil, fmt.Errorf("no fix to undo (%s is empty or missing)", undoJournalFile)
+	}
+	fix := journal.Fixes[len(journal.Fixes)-1]
+
+	for _, entry := range fix.Files {
+		path := filepath.Join(projectPath, filepath.FromSlash(entry.File))
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.File, err)
+		}
+		if contentHash(current) != entry.AppliedHash {
+			return nil, fmt.Errorf("%s changed since the fix of %s was applied; nothing was restored", entry.File, fix.Applied.Format(time.RFC3339))
+		}
+		if contentHash(entry.Original) != entry.OriginalHash {
+			return nil, fmt.Errorf("undo journal entry for %s is damaged; nothing was restored", entry.File)
+		}
+	}
+
+	originals := make(map[string][]byte, len(fix.Files))
+	for _, entry := range fix.Files {
+		originals[filepath.Join(projectPath, filepath.FromSlash(entry.File))] = entry.Original
+	}
+	if err := restoreFiles(originals); err != nil {
+		return nil, err
+	}
+
+	journal.Fixes = journal.Fixes[:len(journal.Fixes)-1]
+	return fix, journal.save(projectPath)
+}
+
+// printUndo outputs a reverted fix in human-readable format
+func printUndo(fix *UndoFix) {
+	fmt.Printf("↩️  Reverted the fix applied at %s, restoring %d file(s):\n", fix.Applied.Local().Format(time.DateTime), len(fix.Files))
+	for _, entry := range fix.Files {
+		fmt.Printf("  • %s\n", entry.File)
+	}
+	if len(fix.Deleted) > 0 {
+		fmt.Printf("\n🔙 Restored %d symbol(s):\n", len(fix.Deleted))
+		for _, name := range fix.Deleted {
+			fmt.Printf("  • %s\n", name)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// unexportCandidates returns the exported functions, types, variables and constants that
+// only their own package references, with --suggest-unexport: they could be unexported
+// to shrink the public API. References from other packages of the analysis count, tests
+// of an external _test package included when analyzed; importers outside it don't.
+// Methods are left out, since they may implement interfaces, and so are orphans, main
+// packages and symbols named outside Go code: linkname, assembly and cgo exports.
+func (a *Analyzer) unexportCandidates() []*Symbol {
+	mainPackages := make(map[string]bool)
+	for _, pkg := range a.packages {
+		if pkg.Name == "main" {
+			mainPackages[pkg.PkgPath] = true
+		}
+	}
+
+	// Packages referencing each symbol, by the key package of the referencing declaration
+	referencedFrom := make(map[int32]map[string]bool)
+	reference := func(to int32, keyPath string) {
+		if referencedFrom[to] == nil {
+			referencedFrom[to] = make(map[string]bool)
+		}
+		referencedFrom[to][keyPath] = true
+	}
+	for from, uses := range a.declUses {
+		owner, ok := a.symbols[a.graph.keys[from]]
+		if !ok {
+			continue
+		}
+		for _, use := range uses {
+			if use.To != from {
+				reference(use.To, owner.keyPackage())
+			}
+		}
+	}
+	for _, inits := range []map[string][]fileUse{a.initUses, a.keepAliveUses} {
+		for keyPath, uses := range inits {
+			for _, use := range uses {
+				reference(use.To, keyPath)
+			}
+		}
+	}
+
+	var candidates []*Symbol
+	for key, symbol := range a.symbols {
+		if !symbol.Exported || symbol.Kind == "method" || symbol.Kind == "field" || mainPackages[symbol.Package] || !a.inShard(symbol.Package) {
+			continue
+		}
+		id := a.graph.ids[key]
+		if a.linknamed[id] || a.assemblyRefs[id] || a.cgoSymbols[id] {
+			continue
+		}
+		if !a.isReachable(key) && !a.isDocsOnly(key) {
+			continue
+		}
+		packages := referencedFrom[id]
+		if len(packages) == 1 && packages[symbol.keyPackage()] {
+			candidates = append(candidates, symbol)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return a.symbolKey(candidates[i]) < a.symbolKey(candidates[j]) })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(candidates) > 0 {
+		fmt.Printf("🔒 %d exported symbol(s) are only used by their own package\n", len(candidates))
+	}
+
+	return candidates
+}
+
+// printUnexportCandidates lists the exported symbols only their own package uses
+func (a *Analyzer) printUnexportCandidates(result *AnalysisResult) {
+	if len(result.UnexportCandidates) == 0 {
+		return
+	}
+
+	fmt.Printf("\n🔒 Could be unexported (only referenced from their own package):\n")
+	for _, symbol := range result.UnexportCandidates {
+		fmt.Printf("  📍 %s.%s (%s) - %s\n", symbol.Package, symbol.displayName(), symbol.Kind, formatPosition(a.relativePath(symbol.File), symbol.Start))
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// Verdicts of the declarations of a symbol in build-variant files
+const (
+	VariantOrphaned    = "orphaned"     // the analyzed declaration, unreachable on this platform
+	VariantNotAnalyzed = "not-analyzed" // excluded from this build; may still be used elsewhere
+	VariantObsolete    = "obsolete"     // the file's build constraints can never be satisfied
+)
+
+// BuildVariant is one declaration of a symbol declared in several files selected by build
+// constraints, such as foo_linux.go and foo_darwin.go
+type BuildVariant struct {
+	File       string `json:"file"`
+	Constraint string `json:"constraint,omitempty"`
+	Verdict    string `json:"verdict"`
+}
+
+// findBuildVariants gives orphans that are also declared in files excluded from this build
+// a verdict per file. Only the analyzed declaration is known to be dead; deleting the
+// others requires analyzing the platforms that build them.
+func (a *Analyzer) findBuildVariants(orphans []*Symbol) {
+	byKey := make(map[string]*Symbol, len(orphans))
+	for _, orphan := range orphans {
+		byKey[a.symbolKey(orphan)] = orphan
+	}
+
+	linked := 0
+	seen := make(map[string]bool)
+	for _, pkg := range a.packages {
+		if !a.inShard(pkg.PkgPath) {
+			continue
+		}
+		minGo := moduleGoMinor(pkg)
+		for _, file := range pkg.IgnoredFiles {
+			if seen[file] || !strings.HasSuffix(file, ".go") {
+				continue
+			}
+			seen[file] = true
+
+			keys := a.variantKeys(a.keyPath(pkg.Types), pkg.Name, file)
+			if len(keys) == 0 {
+				continue
+			}
+			variant := &BuildVariant{File: file, Verdict: VariantNotAnalyzed}
+			if expr, err := fileConstraint(file); err == nil && expr != nil {
+				variant.Constraint = expr.String()
+				if _, ok := a.unsatisfiable(expr, minGo); ok {
+					variant.Verdict = VariantObsolete
+				}
+			}
+
+			for _, key := range keys {
+				orphan, ok := byKey[key]
+				if !ok {
+					continue
+				}
+				if len(orphan.Variants) == 0 {
+					host := &BuildVariant{File: orphan.File, Verdict: VariantOrphaned}
+					if expr, err := fileConstraint(orphan.File); err == nil && expr != nil {
+						host.Constraint = expr.String()
+					}
+					orphan.Variants = append(orphan.Variants, host)
+					linked++
+				}
+				orphan.Variants = append(orphan.Variants, variant)
+			}
+		}
+	}
+
+	for _, orphan := range orphans {
+		if len(orphan.Variants) > 1 {
+			others := orphan.Variants[1:]
+			sort.Slice(others, func(i, j int) bool { return others[i].File < others[j].File })
+		}
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON && linked > 0 {
+		fmt.Printf("🧩 %d orphan(s) are also declared in files excluded from this build\n", linked)
+	}
+}
+
+// variantKeys returns the symbol keys of the top-level declarations of a file excluded
+// from the build. Files of another package, such as generators under //go:build ignore,
+// declare nothing of this package.
+func (a *Analyzer) variantKeys(pkgPath, pkgName, path string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil || file.Name.Name != pkgName {
+		return nil
+	}
+
+	var keys []string
+	add := func(name, kind string) {
+		if name != "_" {
+			keys = append(keys, a.getSymbolKey(pkgPath, name, kind))
+		}
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.Name == "init" {
+				continue
+			}
+			add(funcDeclName(d))
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					add(s.Name.Name, "type")
+				case *ast.ValueSpec:
+					kind := "variable"
+					if d.Tok == token.CONST {
+						kind = "constant"
+					}
+					for _, name := range s.Names {
+						add(name.Name, kind)
+					}
+				}
+			}
+		}
+	}
+	return keys
+}
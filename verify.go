@@ -0,0 +1,250 @@
+package main
+
+import (
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"debug/pe"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// BinaryVerification summarizes how analysis verdicts compare with a built binary
+type BinaryVerification struct {
+	Binary          string    `json:"binary"`
+	MainPackage     string    `json:"main_package"`
+	FalsePositives  []*Symbol `json:"false_positives"`
+	LinkerAgrees    []*Symbol `json:"linker_agrees"`
+	ReachableAbsent int       `json:"reachable_absent"`
+	FunctionsOnly   bool      `json:"functions_only,omitempty"` // stripped binary: variables were not compared
+}
+
+var verifyJSON bool
+
+var verifyBinaryCmd = &cobra.Command{
+	Use:   "verify-binary <binary> [project-path]",
+	Short: "Cross-check orphans against a built binary's symbol table",
+	Long: `Reads the symbol table of a built Go binary and compares it with the analysis:
+orphaned symbols present in the binary indicate analysis false positives, while
+orphaned symbols absent from the binary confirm that the linker agrees they are dead.`,
+	Example: `  gorphanage verify-binary ./bin/app .`,
+	Args:    cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 1 {
+			projectPath = args[1]
+		}
+
+		config, err := configFromViper(projectPath)
+		if err != nil {
+			return err
+		}
+		config.OutputJSON = config.OutputJSON || verifyJSON
+
+		analyzer, result, err := analyze(config)
+		if err != nil {
+			return err
+		}
+
+		verification, err := analyzer.VerifyBinary(args[0], result)
+		if err != nil {
+			return fmt.Errorf("verifying binary: %w", err)
+		}
+
+		if config.OutputJSON {
+			jsonData, err := json.MarshalIndent(verification, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(jsonData))
+			return nil
+		}
+
+		analyzer.printVerification(verification)
+		return nil
+	},
+}
+
+func init() {
+	verifyBinaryCmd.Flags().BoolVar(&verifyJSON, "json", false, "output results in JSON format")
+	rootCmd.AddCommand(verifyBinaryCmd)
+}
+
+// VerifyBinary compares orphan verdicts with the functions and variables linked into a binary
+func (a *Analyzer) VerifyBinary(binaryPath string, result *AnalysisResult) (*BinaryVerification, error) {
+	info, err := buildinfo.ReadFile(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build info: %w", err)
+	}
+
+	names, functionsOnly, err := readBinarySymbols(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	knownPkgs := make(map[string]bool, len(a.packages))
+	for _, pkg := range a.packages {
+		knownPkgs[pkg.PkgPath] = true
+	}
+
+	// The binary names its main package "main" rather than by import path
+	present := make(map[string]bool)
+	linkedPkgs := make(map[string]bool)
+	for _, name := range names {
+		pkgPath, symbolName, ok := splitRuntimeFuncName(name, knownPkgs)
+		if !ok {
+			continue
+		}
+		if pkgPath == "main" {
+			pkgPath = info.Path
+		}
+		present[pkgPath+"."+symbolName] = true
+		linkedPkgs[pkgPath] = true
+	}
+
+	verification := &BinaryVerification{
+		Binary:        binaryPath,
+		MainPackage:   info.Path,
+		FunctionsOnly: functionsOnly,
+	}
+
+	for _, orphan := range result.OrphanedSymbols {
+		if !linkedPkgs[orphan.Package] || !isLinkedKind(orphan.Kind, functionsOnly) {
+			continue
+		}
+		if present[orphan.Package+"."+orphan.keyName()] {
+			verification.FalsePositives = append(verification.FalsePositives, orphan)
+		} else {
+			verification.LinkerAgrees = append(verification.LinkerAgrees, orphan)
+		}
+	}
+
+	for key, symbol := range a.symbols {
+		if a.isReachable(key) && linkedPkgs[symbol.Package] && isLinkedKind(symbol.Kind, functionsOnly) &&
+			!present[symbol.Package+"."+symbol.keyName()] {
+			verification.ReachableAbsent++
+		}
+	}
+
+	return verification, nil
+}
+
+// isLinkedKind reports whether symbols of a kind leave a trace in the binary's symbol
+// table, or in its Go line table, which only holds functions, when functionsOnly is set
+func isLinkedKind(kind string, functionsOnly bool) bool {
+	return kind == "function" || kind == "method" || kind == "variable" && !functionsOnly
+}
+
+// readBinarySymbols returns the symbol names of an ELF, Mach-O or PE binary, and whether
+// they only name functions, read from the Go line table of a stripped binary
+func readBinarySymbols(path string) ([]string, bool, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		return readELFSymbols(f)
+	}
+
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		if f.Symtab == nil {
+			return nil, false, fmt.Errorf("%s has no symbol table (was it built with -ldflags=-s?)", path)
+		}
+		var names []string
+		for _, sym := range f.Symtab.Syms {
+			names = append(names, sym.Name)
+		}
+		return names, false, nil
+	}
+
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		if len(f.Symbols) == 0 {
+			return nil, false, fmt.Errorf("%s has no symbol table (was it built with -ldflags=-s?)", path)
+		}
+		var names []string
+		for _, sym := range f.Symbols {
+			names = append(names, sym.Name)
+		}
+		return names, false, nil
+	}
+
+	return nil, false, fmt.Errorf("%s is not a supported executable (ELF, Mach-O or PE)", path)
+}
+
+// readELFSymbols reads the ELF symbol table, falling back to the Go pclntab for stripped
+// binaries, in which case only function names are returned
+func readELFSymbols(f *elf.File) ([]string, bool, error) {
+	syms, err := f.Symbols()
+	if err == nil {
+		names := make([]string, 0, len(syms))
+		for _, sym := range syms {
+			names = append(names, sym.Name)
+		}
+		return names, false, nil
+	}
+	if !errors.Is(err, elf.ErrNoSymbols) {
+		return nil, false, fmt.Errorf("failed to read symbols: %w", err)
+	}
+
+	pclntab := f.Section(".gopclntab")
+	text := f.Section(".text")
+	if pclntab == nil || text == nil {
+		return nil, false, fmt.Errorf("binary is stripped and has no Go line table")
+	}
+
+	data, err := pclntab.Data()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read .gopclntab: %w", err)
+	}
+
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text.Addr))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode Go line table: %w", err)
+	}
+
+	// Only functions survive in the line table; variables cannot be verified here
+	names := make([]string, 0, len(table.Funcs))
+	for _, fn := range table.Funcs {
+		names = append(names, fn.Name)
+	}
+	return names, true, nil
+}
+
+// printVerification outputs a binary verification in human-readable format
+func (a *Analyzer) printVerification(v *BinaryVerification) {
+	fmt.Printf("\n🔬 BINARY VERIFICATION: %s (main package %s)\n\n", v.Binary, v.MainPackage)
+
+	if len(v.FalsePositives) > 0 {
+		fmt.Printf("=== Present in binary but flagged orphaned (analysis false positives) ===\n")
+		a.printSymbolList(v.FalsePositives)
+		fmt.Println()
+	}
+
+	fmt.Printf("📊 Verification Summary:\n")
+	fmt.Printf("  • Orphans confirmed absent by the linker: %d\n", len(v.LinkerAgrees))
+	fmt.Printf("  • Orphans present in the binary: %d\n", len(v.FalsePositives))
+	fmt.Printf("  • Reachable symbols absent from the binary (inlined or eliminated): %d\n", v.ReachableAbsent)
+	if v.FunctionsOnly {
+		fmt.Printf("💡 The binary is stripped: only functions and methods, kept in its Go line table, were compared.\n")
+	}
+}
+
+// printSymbolList prints symbols sorted by position
+func (a *Analyzer) printSymbolList(symbols []*Symbol) {
+	sorted := append([]*Symbol(nil), symbols...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Start.Line < sorted[j].Start.Line
+	})
+
+	for _, symbol := range sorted {
+		relPath := a.relativePath(symbol.File)
+		fmt.Printf("  📍 %s (%s) - %s\n", symbol.displayName(), symbol.Kind, formatPosition(relPath, symbol.Start))
+	}
+}
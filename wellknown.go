@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// defaultWellKnownMethods are method names that generated code, codecs and frameworks call
+// through reflection or interfaces the project may not import. A method with one of these
+// names is reachable whenever its receiver type is.
+var defaultWellKnownMethods = []string{
+	"String", "GoString", "Error", "Format",
+	"MarshalJSON", "UnmarshalJSON", "MarshalText", "UnmarshalText",
+	"MarshalBinary", "UnmarshalBinary", "MarshalYAML", "UnmarshalYAML",
+	"DeepCopy", "DeepCopyInto", "DeepCopyObject",
+}
+
+// validateWellKnownMethod checks a well-known-methods entry
+func validateWellKnownMethod(name string) error {
+	if !token.IsIdentifier(name) {
+		return fmt.Errorf("invalid method name %q", name)
+	}
+	return nil
+}
+
+// wellKnownMethodNames returns the built-in well-known method names extended with the
+// configured ones
+func (a *Analyzer) wellKnownMethodNames() map[string]bool {
+	names := make(map[string]bool, len(defaultWellKnownMethods)+len(a.config.WellKnownMethods))
+	for _, name := range defaultWellKnownMethods {
+		names[name] = true
+	}
+	for _, name := range a.config.WellKnownMethods {
+		names[name] = true
+	}
+	return names
+}
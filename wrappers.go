@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// findWrappers records the functions and methods whose whole body is a type conversion or
+// a field copy, with --wrappers, before the syntax trees are released
+func (a *Analyzer) findWrappers() {
+	a.wrappers = make(map[int32]string)
+	walked := make(map[string]bool)
+	for _, pkg := range a.packages {
+		for _, file := range pkg.Syntax {
+			filename := a.fileSet.Position(file.Package).Filename
+			if walked[filename] {
+				continue
+			}
+			walked[filename] = true
+
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil || len(fn.Body.List) != 1 {
+					continue
+				}
+				id, ok := a.objectIDs[pkg.TypesInfo.Defs[fn.Name]]
+				if !ok {
+					continue
+				}
+				if wraps := wrappedOperation(pkg, fn.Body.List[0]); wraps != "" {
+					a.wrappers[id] = wraps
+				}
+			}
+		}
+	}
+}
+
+// trivialWrappers returns the reachable wrappers at most --wrapper-max-callers
+// declarations reference: inlining them at their call site loses nothing. Roots, which
+// have no callers in the project, methods that may implement an interface and the
+// exported API under module semantics are left out.
+func (a *Analyzer) trivialWrappers() []*Symbol {
+	interfaceMethods := a.interfaceMethodNames()
+	wellKnown := a.wellKnownMethodNames()
+
+	var wrappers []*Symbol
+	for id, wraps := range a.wrappers {
+		key := a.graph.keys[id]
+		symbol, ok := a.symbols[key]
+		if !ok || !a.inShard(symbol.Package) || !a.isReachable(key) {
+			continue
+		}
+		if symbol.Kind == "method" && (interfaceMethods[symbol.Name] || wellKnown[symbol.Name]) {
+			continue
+		}
+		if a.semantics == SemanticsModule && symbol.Exported && !isInternalPackage(symbol.Package) {
+			continue
+		}
+		callers := len(a.referrers(id))
+		if callers == 0 || callers > a.config.WrapperMaxCallers {
+			continue
+		}
+
+		reported := *symbol
+		reported.Wraps = wraps
+		reported.Callers = callers
+		wrappers = append(wrappers, &reported)
+	}
+	sort.Slice(wrappers, func(i, j int) bool { return a.symbolKey(wrappers[i]) < a.symbolKey(wrappers[j]) })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(wrappers) > 0 {
+		fmt.Printf("🎁 %d trivial wrapper(s) could be inlined at their call site\n", len(wrappers))
+	}
+
+	return wrappers
+}
+
+// wrappedOperation describes the statement making up a function body when it only
+// converts or copies its operands: return T(x), return x.f, return T{F: x.F, ...}, or
+// x.F = y.G. Operands are identifiers or field selectors; any other expression does work
+// of its own and yields "".
+func wrappedOperation(pkg *packages.Package, stmt ast.Stmt) string {
+	info := pkg.TypesInfo
+	qualifier := types.RelativeTo(pkg.Types)
+	operand := func(expr ast.Expr) bool {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			_, ok := info.Uses[e].(*types.Var)
+			return ok
+		case *ast.SelectorExpr:
+			selection, ok := info.Selections[e]
+			return ok && selection.Kind() == types.FieldVal
+		case *ast.StarExpr:
+			_, ok := ast.Unparen(e.X).(*ast.Ident)
+			return ok
+		}
+		return false
+	}
+
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		if len(s.Results) != 1 {
+			return ""
+		}
+		result := ast.Unparen(s.Results[0])
+		if unary, ok := result.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			result = ast.Unparen(unary.X)
+		}
+		switch e := result.(type) {
+		case *ast.CallExpr:
+			if tv, ok := info.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 && operand(e.Args[0]) {
+				return "conversion to " + types.TypeString(tv.Type, qualifier)
+			}
+		case *ast.SelectorExpr:
+			if operand(e) {
+				return "copy of field " + e.Sel.Name
+			}
+		case *ast.CompositeLit:
+			if len(e.Elts) == 0 {
+				return ""
+			}
+			for _, elt := range e.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				if !operand(elt) {
+					return ""
+				}
+			}
+			return "field copy into " + types.TypeString(info.TypeOf(e), qualifier)
+		}
+
+	case *ast.AssignStmt:
+		if s.Tok != token.ASSIGN {
+			return ""
+		}
+		for _, expr := range append(append([]ast.Expr(nil), s.Lhs...), s.Rhs...) {
+			if !operand(expr) {
+				return ""
+			}
+		}
+		for _, expr := range s.Lhs {
+			if _, ok := ast.Unparen(expr).(*ast.SelectorExpr); !ok {
+				return ""
+			}
+		}
+		return "field copy"
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// recordVarWrites records the assignments to package-level variables made in a piece of a
+// declaration. Assigning to a field of a struct variable or an element of an array
+// variable writes the variable too; writes through a pointer, slice or map do not, since
+// the memory may be shared.
+func (a *Analyzer) recordVarWrites(pkg *packages.Package, node ast.Node) {
+	write := func(expr ast.Expr) {
+		ident := writtenVar(pkg.TypesInfo, expr)
+		if ident == nil {
+			return
+		}
+		obj, ok := pkg.TypesInfo.Uses[ident].(*types.Var)
+		if !ok {
+			return
+		}
+		// Only package-level variables of the project are symbols
+		if id, ok := a.objectIDs[obj.Origin()]; ok {
+			a.varWrites[id] = append(a.varWrites[id], ident.Pos())
+			a.writePositions[ident.Pos()] = true
+		}
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if stmt.Tok == token.DEFINE {
+				return true
+			}
+			for _, lhs := range stmt.Lhs {
+				write(lhs)
+			}
+		case *ast.IncDecStmt:
+			write(stmt.X)
+		case *ast.RangeStmt:
+			if stmt.Tok == token.ASSIGN {
+				write(stmt.Key)
+				write(stmt.Value)
+			}
+		}
+		return true
+	})
+}
+
+// writtenVar returns the variable identifier an assignment target writes to, following
+// fields of struct values and elements of arrays, or nil
+func writtenVar(info *types.Info, expr ast.Expr) *ast.Ident {
+	for expr != nil {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			selection, ok := info.Selections[e]
+			if !ok {
+				return e.Sel // pkg.Var
+			}
+			if selection.Kind() != types.FieldVal || selection.Indirect() {
+				return nil
+			}
+			if _, ok := info.TypeOf(e.X).Underlying().(*types.Struct); !ok {
+				return nil
+			}
+			expr = e.X
+		case *ast.IndexExpr:
+			if _, ok := info.TypeOf(e.X).Underlying().(*types.Array); !ok {
+				return nil
+			}
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// writeOnlyVariables returns the reachable package-level variables that are assigned but
+// never read by reachable code, with every assignment. Reads in dead code don't count: they
+// go away with it. Under module semantics, exported variables of the public API may be
+// read by importers and are left out.
+func (a *Analyzer) writeOnlyVariables() []*Symbol {
+	read := make(map[int32]bool)
+	for from, uses := range a.declUses {
+		if int(from) >= len(a.reached) || a.reached[from] == 0 {
+			continue
+		}
+		for _, use := range uses {
+			if !a.writePositions[use.Pos] {
+				read[use.To] = true
+			}
+		}
+	}
+	for _, inits := range []map[string][]fileUse{a.initUses, a.keepAliveUses} {
+		for _, uses := range inits {
+			for _, use := range uses {
+				if !a.writePositions[use.Pos] {
+					read[use.To] = true
+				}
+			}
+		}
+	}
+
+	var writeOnly []*Symbol
+	for id, writes := range a.varWrites {
+		key := a.graph.keys[id]
+		symbol, ok := a.symbols[key]
+		if !ok || read[id] || !a.inShard(symbol.Package) || !a.isReachable(key) {
+			continue
+		}
+		if a.semantics == SemanticsModule && symbol.Exported && !isInternalPackage(symbol.Package) {
+			continue
+		}
+
+		reported := *symbol
+		for _, pos := range writes {
+			position := a.fileSet.Position(pos)
+			reported.WrittenAt = append(reported.WrittenAt, RefLocation{
+				File:   position.Filename,
+				Line:   position.Line,
+				Column: position.Column,
+				From:   a.referencingSymbol(pos),
+			})
+		}
+		sort.Slice(reported.WrittenAt, func(i, j int) bool {
+			wi, wj := reported.WrittenAt[i], reported.WrittenAt[j]
+			if wi.File != wj.File {
+				return wi.File < wj.File
+			}
+			return wi.Line < wj.Line || wi.Line == wj.Line && wi.Column < wj.Column
+		})
+		writeOnly = append(writeOnly, &reported)
+	}
+	sort.Slice(writeOnly, func(i, j int) bool { return a.symbolKey(writeOnly[i]) < a.symbolKey(writeOnly[j]) })
+
+	if a.config.Verbose && !a.config.OutputJSON && len(writeOnly) > 0 {
+		fmt.Printf("✏️  %d variable(s) are assigned but never read\n", len(writeOnly))
+	}
+
+	return writeOnly
+}
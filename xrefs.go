@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// crossReferences lists the references to every reachable symbol reported by this shard,
+// with --with-references. Orphans are left out: whatever references them is dead too.
+func (a *Analyzer) crossReferences() []*SymbolReferences {
+	var xrefs []*SymbolReferences
+	total := 0
+	for _, key := range sortedSymbolKeys(a.symbols) {
+		symbol := a.symbols[key]
+		if !a.isReachable(key) || !a.inShard(symbol.Package) {
+			continue
+		}
+		refs := a.symbolReferences(key)
+		if len(refs.References) == 0 {
+			continue
+		}
+		xrefs = append(xrefs, refs)
+		total += len(refs.References)
+	}
+
+	if a.config.Verbose && !a.config.OutputJSON {
+		fmt.Printf("🔗 Recorded %d reference(s) to %d reachable symbol(s)\n", total, len(xrefs))
+	}
+
+	return xrefs
+}
+
+// referencingSymbol returns the key of the declaration a reference appears in. References
+// made by blank variable initializers belong to the package's init.
+func (a *Analyzer) referencingSymbol(pos token.Pos) string {
+	if a.referenceOwners == nil {
+		a.referenceOwners = make(map[token.Pos]string)
+		for from, uses := range a.declUses {
+			key := a.graph.keys[from]
+			for _, use := range uses {
+				// Specs sharing a value, like iota constants, own the same references
+				if owner, ok := a.referenceOwners[use.Pos]; !ok || key < owner {
+					a.referenceOwners[use.Pos] = key
+				}
+			}
+		}
+		for _, inits := range []map[string][]fileUse{a.initUses, a.keepAliveUses} {
+			for keyPath, uses := range inits {
+				init := a.getSymbolKey(keyPath, "init", "function")
+				for _, use := range uses {
+					a.referenceOwners[use.Pos] = init
+				}
+			}
+		}
+	}
+	return a.referenceOwners[pos]
+}